@@ -6,6 +6,7 @@ package main
 import (
 	"context"
 	"errors"
+	"expvar"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -13,21 +14,31 @@ import (
 	"os/signal"
 	"path/filepath"
 	"syscall"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
+	"github.com/samber/lo"
 
 	"github.com/0x0BSoD/newsMaker/internal/bot"
 	"github.com/0x0BSoD/newsMaker/internal/bot/middleware"
 	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/breaking"
+	"github.com/0x0BSoD/newsMaker/internal/cluster"
 	"github.com/0x0BSoD/newsMaker/internal/config"
 	"github.com/0x0BSoD/newsMaker/internal/digest"
+	"github.com/0x0BSoD/newsMaker/internal/feedback"
 	"github.com/0x0BSoD/newsMaker/internal/fetcher"
+	"github.com/0x0BSoD/newsMaker/internal/gate"
 	"github.com/0x0BSoD/newsMaker/internal/github"
 	"github.com/0x0BSoD/newsMaker/internal/notifier"
+	"github.com/0x0BSoD/newsMaker/internal/prompt"
+	"github.com/0x0BSoD/newsMaker/internal/ranking"
+	"github.com/0x0BSoD/newsMaker/internal/recap"
 	"github.com/0x0BSoD/newsMaker/internal/reporter"
 	"github.com/0x0BSoD/newsMaker/internal/storage"
+	"github.com/0x0BSoD/newsMaker/internal/subscription"
 	"github.com/0x0BSoD/newsMaker/internal/summary"
 	"github.com/0x0BSoD/newsMaker/internal/telegraph"
 )
@@ -65,76 +76,104 @@ func main() {
 	githubClient := github.NewClient(cfg.GitHubToken)
 	telegraphClient := telegraph.NewClient(cfg.TelegraphToken)
 	repoStorage := storage.NewGitHubRepoStorage(db)
+	postStorage := storage.NewPostStorage(db)
+	promptStorage := storage.NewPromptStorage(db)
+	feedbackStorage := storage.NewFeedbackStorage(db)
+	userSettingsStorage := storage.NewUserSettingsStorage(db)
+	subscriptionStorage := storage.NewSubscriptionStorage(db)
+
+	// Config prompts are the defaults until a version is saved via /setprompt.
+	prompts := prompt.NewLibrary(promptStorage, map[string]string{
+		prompt.NewsDigestSystem:   cfg.NewsDigestPrompt,
+		prompt.NewsDigestMap:      cfg.NewsDigestMapPrompt,
+		prompt.GitHubDigestSystem: cfg.DigestSummaryPrompt,
+		prompt.ArticleSummary:     cfg.AIPrompt,
+	})
+
+	rep := reporter.New(botAPI, cfg.TelegramAdminChatID)
+
+	chain, err := newSummarizerChain(cfg, rep)
+	if err != nil {
+		slog.Error("failed to configure llm providers", "err", err)
+		return
+	}
 
 	var (
-		digestSummarizer     notifier.Summarizer
-		newsDigestSummarizer notifier.Summarizer
+		summarizer summary.Summarizer = chain
+		llmCache   *summary.Cached
 	)
+	if cfg.AICacheTTL > 0 {
+		llmCache = summary.NewCached(chain, storage.NewLLMCacheStorage(db), cfg.AICacheTTL)
+		summarizer = llmCache
+		slog.Info("llm cache enabled", "ttl", cfg.AICacheTTL)
+	}
 
-	switch cfg.AIType {
-	case "openai":
-		if cfg.AIKey == "" {
-			slog.Error("ai_key is required when ai_type is openai")
-			return
-		}
-		digestSummarizer = summary.NewOpenAISummarizer(
-			cfg.AIBaseURL,
-			cfg.AIKey,
-			cfg.DigestSummaryPrompt,
-			cfg.AIModel,
-			cfg.AITimeout,
-		)
-		newsDigestSummarizer = summary.NewOpenAISummarizer(
-			cfg.AIBaseURL,
-			cfg.AIKey,
-			cfg.NewsDigestPrompt,
-			cfg.AIModel,
-			cfg.AITimeout,
-		)
-		slog.Info("summarizers ready", "type", "openai", "model", cfg.AIModel)
-	default:
-		if cfg.AIBaseURL == "" {
-			slog.Error("ai_base_url is required when ai_type is ollama")
-			return
-		}
-		digestSummarizer = summary.NewOllamaSummarizer(
-			cfg.AIBaseURL,
-			cfg.DigestSummaryPrompt,
-			cfg.AIModel,
-			cfg.AITimeout,
-		)
-		newsDigestSummarizer = summary.NewOllamaSummarizer(
-			cfg.AIBaseURL,
-			cfg.NewsDigestPrompt,
-			cfg.AIModel,
-			cfg.AITimeout,
-		)
-		slog.Info("summarizers ready", "type", "ollama", "model", cfg.AIModel)
+	grouper, err := newArticleGrouper(cfg, db, summarizer, prompts)
+	if err != nil {
+		slog.Error("failed to configure topic grouping", "err", err)
+		return
+	}
+
+	keywords, err := ranking.ParseKeywords(cfg.RankingKeywords)
+	if err != nil {
+		slog.Error("failed to configure ranking", "err", err)
+		return
+	}
+	var hnSignal ranking.Signal
+	if cfg.RankingHNWeight != 0 {
+		hnSignal = ranking.NewHNSignal()
+	}
+	ranker := ranking.NewRanker(ranking.Weights{
+		Priority: cfg.RankingPriorityWeight,
+		Recency:  cfg.RankingRecencyWeight,
+		HalfLife: cfg.RankingHalfLife,
+		Coverage: cfg.RankingCoverageWeight,
+		HN:       cfg.RankingHNWeight,
+	}, keywords, cfg.NewsDigestPerSourceCap, hnSignal, grouper)
+
+	postGate, err := newPostGate(cfg, postStorage)
+	if err != nil {
+		slog.Error("failed to configure posting limits", "err", err)
+		return
 	}
+	// Every channel post goes through channelBot, so none skips the gate.
+	channelBot := gate.NewSender(botAPI, postGate)
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
-	rep := reporter.New(botAPI, cfg.TelegramAdminChatID)
-
 	var (
 		articleStorage = storage.NewArticleStorage(db)
 		sourceStorage  = storage.NewSourceStorage(db)
 		notifier       = notifier.New(
 			articleStorage,
-			newsDigestSummarizer,
-			botAPI,
+			postStorage,
+			summarizer,
+			grouper,
+			ranker,
+			channelBot,
 			cfg.NewsDigestMorningHour,
 			cfg.NewsDigestNoonHour,
 			cfg.NewsDigestEveningHour,
 			cfg.NewsDigestLookback,
 			cfg.NewsDigestMaxArticles,
+			cfg.NewsDigestCandidates,
 			cfg.TelegramChannelID,
 			rep,
 			cfg.NewsDigestRetryInterval,
 			cfg.NewsDigestMaxRetries,
 			summaryInputDir,
 			cfg.NewsDigestMaxDataLen,
+			prompts,
+			channelLanguage(cfg, cfg.TelegramChannelID),
+			cfg.NewsDigestMode,
+			cfg.NewsDigestChunkTokens,
+			cfg.NewsDigestOutput,
+			cfg.PostImages,
+			cfg.FeedbackButtons,
+			postGate,
+			cfg.TranslateTitles,
+			feedbackStorage,
 		)
 		fetcher = fetcher.New(
 			articleStorage,
@@ -146,16 +185,117 @@ func main() {
 		digest = digest.New(
 			githubClient,
 			telegraphClient,
-			botAPI,
+			channelBot,
 			repoStorage,
-			digestSummarizer,
+			postStorage,
+			summarizer,
+			prompts,
+			cfg.NewsDigestLanguage,
 			cfg.TelegramChannelID,
 			cfg.GitHubTopics,
 			cfg.DigestInterval,
 			summaryInputDir,
+			postGate,
 		)
 	)
 
+	articleNotifiers, digestEnabled, err := newArticleNotifiers(cfg, articleStorage, postStorage, summarizer, ranker, channelBot, rep, prompts, postGate)
+	if err != nil {
+		slog.Error("failed to configure channels", "err", err)
+		return
+	}
+
+	if cfg.BreakingEnabled {
+		breakingLocation, err := channelLocation(cfg, cfg.TelegramChannelID)
+		if err != nil {
+			slog.Error("failed to configure breaking news", "err", err)
+			return
+		}
+		watcher := breaking.New(
+			articleStorage,
+			postStorage,
+			summarizer,
+			prompts,
+			channelBot,
+			rep,
+			cfg.TelegramChannelID,
+			breaking.Rules{
+				MinPriority: cfg.BreakingMinPriority,
+				Keywords:    cfg.BreakingKeywords,
+				MinSources:  cfg.BreakingMinSources,
+				Window:      cfg.BreakingWindow,
+			},
+			cfg.BreakingInterval,
+			cfg.BreakingMaxPerHour,
+			cfg.BreakingQuietStart,
+			cfg.BreakingQuietEnd,
+			channelLanguage(cfg, cfg.TelegramChannelID),
+			cfg.PostImages,
+			cfg.FeedbackButtons,
+			postGate,
+			breakingLocation,
+		)
+
+		go func(ctx context.Context) {
+			if err := watcher.Start(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				slog.Error("breaking news watcher stopped unexpectedly", "err", err)
+				rep.Notify(fmt.Sprintf("Breaking news watcher stopped: %v", err))
+			}
+		}(ctx)
+	}
+
+	var recapTelegraph *telegraph.Client
+	if cfg.RecapTelegraph {
+		recapTelegraph = telegraphClient
+	}
+	weeklyRecap := recap.New(
+		articleStorage,
+		feedbackStorage,
+		postStorage,
+		summarizer,
+		prompts,
+		channelBot,
+		recapTelegraph,
+		rep,
+		postGate,
+		cfg.TelegramChannelID,
+		time.Weekday(cfg.RecapWeekday),
+		cfg.RecapHour,
+		cfg.RecapMaxStories,
+		recap.Weights{
+			Coverage: cfg.RecapCoverageWeight,
+			Feedback: cfg.RecapFeedbackWeight,
+			Priority: cfg.RecapPriorityWeight,
+		},
+		cfg.NewsDigestMaxDataLen,
+		channelLanguage(cfg, cfg.TelegramChannelID),
+		grouper,
+	)
+	if cfg.RecapEnabled {
+		go func(ctx context.Context) {
+			if err := weeklyRecap.Start(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				slog.Error("weekly recap stopped unexpectedly", "err", err)
+				rep.Notify(fmt.Sprintf("Weekly recap stopped: %v", err))
+			}
+		}(ctx)
+	}
+
+	if cfg.SubscriptionsEnabled {
+		personalDigests := subscription.New(
+			subscriptionStorage,
+			articleStorage,
+			botAPI,
+			rep,
+			cfg.SubscriptionMaxArticles,
+		)
+		go func(ctx context.Context) {
+			if err := personalDigests.Start(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				slog.Error("subscription digester stopped unexpectedly", "err", err)
+				rep.Notify(fmt.Sprintf("Subscription digester stopped: %v", err))
+			}
+		}(ctx)
+	}
+
 	// Fall back to the admin chat if no dedicated test channel is configured.
 	testChannelID := cfg.TelegramTestChannelID
 	if testChannelID == 0 {
@@ -163,32 +303,60 @@ func main() {
 	}
 
 	newsBot := botkit.New(botAPI)
-	newsBot.RegisterCmdView(
+	// /testnews and friends run the summarizer; see RegisterLongCmdView.
+	newsBot.SetLongUpdateTimeout(cfg.AITimeout)
+	newsBot.SetLanguageFunc(middleware.UserLanguage(userSettingsStorage, cfg.BotLanguage))
+	newsBot.SetConversationStore(storage.NewConversationStorage(db))
+	addSource := bot.AddSourceConversation(sourceStorage, fetcher)
+	newsBot.RegisterConversation(addSource)
+	newsBot.RegisterConversation(bot.EditSourceConversation(sourceStorage, fetcher))
+	newsBot.RegisterLongCmdView(
 		"testdigest",
 		middleware.AdminsOnly(
 			cfg.TelegramChannelID,
 			bot.ViewCmdTestDigest(digest, testChannelID),
 		),
 	)
-	newsBot.RegisterCmdView(
+	newsBot.RegisterLongCmdView(
 		"testnews",
 		middleware.AdminsOnly(
 			cfg.TelegramChannelID,
 			bot.ViewCmdTestNews(notifier, testChannelID),
 		),
 	)
-	newsBot.RegisterCmdView(
+	newsBot.RegisterLongCmdView(
+		"testrecap",
+		middleware.AdminsOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdTestRecap(weeklyRecap, testChannelID),
+		),
+	)
+	newsBot.RegisterLongCmdView(
 		"repostnews",
 		middleware.AdminsOnly(
 			cfg.TelegramChannelID,
 			bot.ViewCmdRepostNews(notifier),
 		),
 	)
+	newsBot.RegisterLongCmdView(
+		"explainnews",
+		middleware.AdminsOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdExplainNews(notifier),
+		),
+	)
 	newsBot.RegisterCmdView(
 		"addsource",
 		middleware.AdminsOnly(
 			cfg.TelegramChannelID,
-			bot.ViewCmdAddSource(sourceStorage, newsBot),
+			newsBot.ConversationView(addSource.Name),
+		),
+	)
+	newsBot.RegisterCmdView(
+		"editsource",
+		middleware.AdminsOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdEditSource(sourceStorage, newsBot),
 		),
 	)
 	newsBot.RegisterCmdView(
@@ -219,11 +387,139 @@ func main() {
 			bot.ViewCmdDeleteSource(sourceStorage),
 		),
 	)
+	newsBot.RegisterLongCmdView(
+		"previewsource",
+		middleware.AdminsOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdPreviewSource(sourceStorage, fetcher),
+		),
+	)
+	newsBot.RegisterCmdView(
+		"restoresource",
+		middleware.AdminsOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdRestoreSource(sourceStorage),
+		),
+	)
+	newsBot.RegisterCmdView(
+		"pausesource",
+		middleware.AdminsOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdPauseSource(sourceStorage),
+		),
+	)
+	newsBot.RegisterCmdView(
+		"resumesource",
+		middleware.AdminsOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdResumeSource(sourceStorage),
+		),
+	)
+	newsBot.RegisterCmdView(
+		"prompts",
+		middleware.AdminsOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdPrompts(prompts, promptStorage),
+		),
+	)
+	newsBot.RegisterCmdView(
+		"prompt",
+		middleware.AdminsOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdPrompt(prompts, promptStorage),
+		),
+	)
+	newsBot.RegisterCmdView(
+		"setprompt",
+		middleware.AdminsOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdSetPrompt(prompts, promptStorage, newsBot),
+		),
+	)
+	newsBot.RegisterCmdView(
+		"rollbackprompt",
+		middleware.AdminsOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdRollbackPrompt(prompts, promptStorage),
+		),
+	)
+	newsBot.RegisterCmdView(
+		"posts",
+		middleware.AdminsOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdPosts(postStorage),
+		),
+	)
+	newsBot.RegisterLongCmdView(
+		"editdigest",
+		middleware.AdminsOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdEditDigest(postStorage, notifier),
+		),
+	)
+	newsBot.RegisterCmdView(
+		"retractpost",
+		middleware.AdminsOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdRetractPost(postStorage),
+		),
+	)
+	newsBot.RegisterCmdView(
+		"feedback",
+		middleware.AdminsOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdFeedback(feedbackStorage),
+		),
+	)
+	newsBot.RegisterCmdView(
+		"search",
+		middleware.AdminsOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdSearch(articleStorage, sourceStorage),
+		),
+	)
+	newsBot.RegisterCmdView(
+		"language",
+		middleware.AdminsOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCmdLanguage(userSettingsStorage),
+		),
+	)
+	if cfg.SubscriptionsEnabled {
+		// Open to every user: subscriptions are personal.
+		newsBot.RegisterConversation(bot.SubscribeConversation(
+			subscriptionStorage,
+			sourceStorage,
+			[]int{cfg.NewsDigestMorningHour, cfg.NewsDigestNoonHour, cfg.NewsDigestEveningHour},
+			subscriptionZones(cfg),
+			cfg.SubscriptionMaxPerUser,
+		))
+		newsBot.RegisterCmdView("subscribe", bot.ViewCmdSubscribe(newsBot, subscriptionStorage, cfg.SubscriptionMaxPerUser))
+		newsBot.RegisterCmdView("mysubs", bot.ViewCmdMySubs(subscriptionStorage, sourceStorage))
+		newsBot.RegisterCmdView("unsubscribe", bot.ViewCmdUnsubscribe(subscriptionStorage))
+	}
+	newsBot.RegisterCallbackView(feedback.CallbackPrefix, bot.ViewCallbackFeedback(feedbackStorage))
+	newsBot.RegisterCallbackView(
+		bot.SearchCallbackPrefix,
+		middleware.AdminsOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCallbackSearch(articleStorage, sourceStorage),
+		),
+	)
+	newsBot.RegisterCallbackView(
+		bot.SourcesCallbackPrefix,
+		middleware.AdminsOnly(
+			cfg.TelegramChannelID,
+			bot.ViewCallbackSources(sourceStorage, fetcher, newsBot),
+		),
+	)
 
 	mux := http.NewServeMux()
 	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
 	})
+	// Runtime counters, including llm_cache_hits / llm_cache_misses.
+	mux.Handle("/debug/vars", expvar.Handler())
 
 	go func(ctx context.Context) {
 		if err := digest.Start(ctx); err != nil {
@@ -247,16 +543,35 @@ func main() {
 		}
 	}(ctx)
 
-	go func(ctx context.Context) {
-		if err := notifier.Start(ctx); err != nil {
-			if !errors.Is(err, context.Canceled) {
-				slog.Error("notifier stopped unexpectedly", "err", err)
-				rep.Notify(fmt.Sprintf("Notifier stopped: %v", err))
-				return
+	if digestEnabled {
+		go func(ctx context.Context) {
+			if err := notifier.Start(ctx); err != nil {
+				if !errors.Is(err, context.Canceled) {
+					slog.Error("notifier stopped unexpectedly", "err", err)
+					rep.Notify(fmt.Sprintf("Notifier stopped: %v", err))
+					return
+				}
+				slog.Info("notifier stopped")
 			}
-			slog.Info("notifier stopped")
-		}
-	}(ctx)
+		}(ctx)
+	}
+
+	if llmCache != nil {
+		go func(ctx context.Context) {
+			if err := llmCache.Start(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				slog.Error("llm cache cleanup stopped unexpectedly", "err", err)
+			}
+		}(ctx)
+	}
+
+	for _, n := range articleNotifiers {
+		go func(ctx context.Context) {
+			if err := n.Start(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				slog.Error("article notifier stopped unexpectedly", "err", err)
+				rep.Notify(fmt.Sprintf("Article notifier stopped: %v", err))
+			}
+		}(ctx)
+	}
 
 	go func(ctx context.Context) {
 		if err := http.ListenAndServe("127.0.0.1:8088", mux); err != nil {
@@ -270,3 +585,260 @@ func main() {
 		slog.Error("botkit stopped unexpectedly", "err", err)
 	}
 }
+
+const (
+	defaultProviderFailures = 3
+	defaultProviderCooldown = 10 * time.Minute
+)
+
+// newSummarizerChain builds the ordered LLM fallback chain from ai_providers,
+// or a single-provider chain from the legacy ai_* settings. When every
+// provider fails, callers post their plain non-LLM digest.
+func newSummarizerChain(cfg config.Config, rep *reporter.Reporter) (*summary.Chain, error) {
+	providers := cfg.AIProviders
+	if len(providers) == 0 {
+		providers = []config.AIProvider{{
+			Name:     cfg.AIType,
+			Type:     cfg.AIType,
+			Endpoint: cfg.AIBaseURL,
+			Key:      cfg.AIKey,
+		}}
+	}
+
+	chain := make([]summary.Provider, 0, len(providers))
+	for i, p := range providers {
+		name := p.Name
+		if name == "" {
+			name = fmt.Sprintf("%s#%d", p.Type, i+1)
+		}
+		model := p.Model
+		if model == "" {
+			model = cfg.AIModel
+		}
+		timeout := p.Timeout
+		if timeout == 0 {
+			timeout = cfg.AITimeout
+		}
+		failures := p.Failures
+		if failures == 0 {
+			failures = defaultProviderFailures
+		}
+		cooldown := p.Cooldown
+		if cooldown == 0 {
+			cooldown = defaultProviderCooldown
+		}
+
+		var s summary.Summarizer
+		switch p.Type {
+		case "openai":
+			if p.Key == "" {
+				return nil, fmt.Errorf("provider %q: key is required for openai", name)
+			}
+			s = summary.NewOpenAISummarizer(p.Endpoint, p.Key, model, timeout)
+		case "ollama", "":
+			if p.Endpoint == "" {
+				return nil, fmt.Errorf("provider %q: endpoint is required for ollama", name)
+			}
+			s = summary.NewOllamaSummarizer(p.Endpoint, model, timeout)
+		default:
+			return nil, fmt.Errorf("provider %q: unknown type %q", name, p.Type)
+		}
+
+		chain = append(chain, summary.Provider{
+			Name:       name,
+			Summarizer: s,
+			Breaker:    summary.NewBreaker(failures, cooldown),
+		})
+		slog.Info("llm provider ready", "name", name, "type", p.Type, "model", model, "timeout", timeout)
+	}
+
+	return summary.NewChain(rep, chain...), nil
+}
+
+// newArticleGrouper builds the news digest topic grouper. With embedding_type
+// set, related articles are clustered by embedding and clusters without a
+// dominant category are labeled by the LLM; otherwise articles are grouped by
+// their category, normalized through category_aliases.
+func newArticleGrouper(cfg config.Config, db *sqlx.DB, summarizer summary.Summarizer, prompts *prompt.Library) (*cluster.Grouper, error) {
+	normalizer, err := cluster.NewNormalizer(cfg.CategoryAliases)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := cfg.EmbeddingBaseURL
+	if baseURL == "" {
+		baseURL = cfg.AIBaseURL
+	}
+	key := cfg.EmbeddingKey
+	if key == "" {
+		key = cfg.AIKey
+	}
+
+	var embedder summary.Embedder
+	switch cfg.EmbeddingType {
+	case "":
+		return cluster.NewGrouper(nil, nil, nil, normalizer, 0), nil
+	case "ollama":
+		embedder = summary.NewOllamaEmbedder(baseURL, cfg.EmbeddingModel, cfg.AITimeout)
+	case "openai":
+		embedder = summary.NewOpenAIEmbedder(baseURL, key, cfg.EmbeddingModel, cfg.AITimeout)
+	default:
+		return nil, fmt.Errorf("unknown embedding_type %q", cfg.EmbeddingType)
+	}
+	slog.Info("embedding topic grouping enabled", "type", cfg.EmbeddingType, "model", cfg.EmbeddingModel, "threshold", cfg.EmbeddingThreshold)
+
+	return cluster.NewGrouper(
+		embedder,
+		storage.NewEmbeddingStorage(db),
+		cluster.NewLLMLabeler(summarizer, prompts),
+		normalizer,
+		cfg.EmbeddingThreshold,
+	), nil
+}
+
+// newArticleNotifiers builds a notifier for every channel configured in
+// article mode. The returned flag reports whether telegram_channel_id still
+// gets digests, which is the case unless it is configured in article mode.
+func newArticleNotifiers(
+	cfg config.Config,
+	articles notifier.ArticleProvider,
+	posts notifier.PostStorage,
+	summarizer summary.Summarizer,
+	ranker notifier.ArticleRanker,
+	channelBot botkit.Sender,
+	rep *reporter.Reporter,
+	prompts *prompt.Library,
+	postGate notifier.PostGate,
+) ([]*notifier.ArticleNotifier, bool, error) {
+	var (
+		notifiers []*notifier.ArticleNotifier
+		digest    = true
+	)
+	for _, ch := range cfg.Channels {
+		switch ch.Mode {
+		case notifier.ChannelModeDigest, "":
+			// Digests are scheduled for telegram_channel_id only.
+			if ch.ID != cfg.TelegramChannelID {
+				return nil, false, fmt.Errorf("channel %d: digest mode is only supported for telegram_channel_id", ch.ID)
+			}
+		case notifier.ChannelModeArticle:
+			preview, err := notifier.ParseLinkPreview(ch.Preview, ch.Above)
+			if err != nil {
+				return nil, false, fmt.Errorf("channel %d: %w", ch.ID, err)
+			}
+			if ch.ID == cfg.TelegramChannelID {
+				digest = false
+			}
+			notifiers = append(notifiers, notifier.NewArticleNotifier(
+				articles,
+				posts,
+				summarizer,
+				ranker,
+				channelBot,
+				rep,
+				ch.ID,
+				cfg.NotificationInterval,
+				cfg.NewsDigestLookback,
+				cfg.NewsDigestCandidates,
+				cfg.NewsDigestMaxDataLen,
+				prompts,
+				channelLanguage(cfg, ch.ID),
+				preview,
+				cfg.PostImages,
+				cfg.FeedbackButtons,
+				postGate,
+				cfg.TranslateTitles,
+			))
+		default:
+			return nil, false, fmt.Errorf("channel %d: unknown mode %q", ch.ID, ch.Mode)
+		}
+	}
+	return notifiers, digest, nil
+}
+
+// channelLanguage returns the language configured for a channel, or
+// news_digest_language.
+func channelLanguage(cfg config.Config, channelID int64) string {
+	for _, ch := range cfg.Channels {
+		if ch.ID == channelID && ch.Language != "" {
+			return ch.Language
+		}
+	}
+	return cfg.NewsDigestLanguage
+}
+
+// channelLocation returns the timezone of a channel: its own timezone if it
+// sets one, else post_timezone.
+func channelLocation(cfg config.Config, channelID int64) (*time.Location, error) {
+	for _, ch := range cfg.Channels {
+		if ch.ID == channelID && ch.Timezone != "" {
+			loc, err := time.LoadLocation(ch.Timezone)
+			if err != nil {
+				return nil, fmt.Errorf("channel %d: timezone: %w", ch.ID, err)
+			}
+			return loc, nil
+		}
+	}
+
+	loc, err := time.LoadLocation(cfg.PostTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("post_timezone: %w", err)
+	}
+	return loc, nil
+}
+
+// subscriptionZones returns the timezones offered to subscribers: those of
+// the channels and post_timezone, then UTC. The server's Local zone is left
+// out since its name says nothing to a user.
+func subscriptionZones(cfg config.Config) []string {
+	zones := []string{cfg.PostTimezone}
+	for _, ch := range cfg.Channels {
+		zones = append(zones, ch.Timezone)
+	}
+	zones = append(zones, "UTC")
+
+	var offered []string
+	for _, z := range zones {
+		if z == "" || z == "Local" || lo.Contains(offered, z) {
+			continue
+		}
+		offered = append(offered, z)
+	}
+	return offered
+}
+
+// newPostGate builds the posting limits: the post_* settings apply to every
+// channel, and the timezone, quiet and daily settings of a channel override
+// them.
+func newPostGate(cfg config.Config, posts gate.PostCounter) (*gate.Gate, error) {
+	loc, err := time.LoadLocation(cfg.PostTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("post_timezone: %w", err)
+	}
+	fallback := gate.Policy{
+		Location:   loc,
+		QuietStart: cfg.PostQuietStart,
+		QuietEnd:   cfg.PostQuietEnd,
+		MaxPerDay:  cfg.PostMaxPerDay,
+	}
+
+	policies := make(map[int64]gate.Policy, len(cfg.Channels))
+	for _, ch := range cfg.Channels {
+		p := fallback
+		if ch.Timezone != "" {
+			if p.Location, err = time.LoadLocation(ch.Timezone); err != nil {
+				return nil, fmt.Errorf("channel %d: timezone: %w", ch.ID, err)
+			}
+		}
+		if ch.Quiet != "" {
+			if p.QuietStart, p.QuietEnd, err = gate.ParseQuietHours(ch.Quiet); err != nil {
+				return nil, fmt.Errorf("channel %d: %w", ch.ID, err)
+			}
+		}
+		if ch.Daily != 0 {
+			p.MaxPerDay = ch.Daily
+		}
+		policies[ch.ID] = p
+	}
+	return gate.New(posts, fallback, policies), nil
+}
@@ -1,3 +1,45 @@
 package bot
 
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/0x0BSoD/newsMaker/internal/prompt"
+	"github.com/0x0BSoD/newsMaker/internal/summary"
+)
+
 const parseModeMarkdownV2 = "MarkdownV2"
+
+// argNoCache is the command argument that forces a fresh LLM generation.
+const argNoCache = "nocache"
+
+// withCacheArg disables the LLM cache for ctx when the command arguments
+// contain "nocache".
+func withCacheArg(ctx context.Context, args string) context.Context {
+	for _, arg := range strings.Fields(args) {
+		if strings.EqualFold(arg, argNoCache) {
+			return summary.WithoutCache(ctx)
+		}
+	}
+	return ctx
+}
+
+// withPromptArgs selects prompt template versions for ctx from command
+// arguments of the form name=version, e.g. news_digest_system=3.
+func withPromptArgs(ctx context.Context, args string) context.Context {
+	versions := make(map[string]int)
+	for _, arg := range strings.Fields(args) {
+		name, v, ok := strings.Cut(arg, "=")
+		if !ok {
+			continue
+		}
+		if version, err := strconv.Atoi(v); err == nil && version > 0 {
+			versions[name] = version
+		}
+	}
+	if len(versions) == 0 {
+		return ctx
+	}
+	return prompt.WithVersions(ctx, versions)
+}
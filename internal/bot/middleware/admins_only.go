@@ -6,6 +6,7 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
 )
 
 func AdminsOnly(channelID int64, next botkit.ViewFunc) botkit.ViewFunc {
@@ -28,9 +29,14 @@ func AdminsOnly(channelID int64, next botkit.ViewFunc) botkit.ViewFunc {
 			}
 		}
 
+		if update.CallbackQuery != nil {
+			_, err := bot.Request(tgbotapi.NewCallbackWithAlert(update.CallbackQuery.ID, i18n.T(ctx, i18n.NoRights)))
+			return err
+		}
+
 		if _, err := bot.Send(tgbotapi.NewMessage(
 			update.FromChat().ID,
-			"У вас нет прав на выполнение этой команды.",
+			i18n.T(ctx, i18n.NoRights),
 		)); err != nil {
 			return err
 		}
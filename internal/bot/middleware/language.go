@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+)
+
+// LanguageSettings is satisfied by storage.UserSettingsPostgresStorage.
+type LanguageSettings interface {
+	Language(ctx context.Context, userID int64) (string, error)
+}
+
+// UserLanguage picks the reply language of an update: the one the user chose
+// with /language, else their Telegram language_code, else fallback.
+func UserLanguage(settings LanguageSettings, fallback string) botkit.LanguageFunc {
+	return func(ctx context.Context, update tgbotapi.Update) string {
+		user := update.SentFrom()
+		if user == nil {
+			return fallback
+		}
+
+		language, err := settings.Language(ctx, user.ID)
+		if err != nil {
+			slog.Warn("failed to load user language", "user", user.ID, "err", err)
+		}
+		if language = i18n.Match(language); language != "" {
+			return language
+		}
+		if language = i18n.Match(user.LanguageCode); language != "" {
+			return language
+		}
+		return fallback
+	}
+}
@@ -0,0 +1,63 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/feedback"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+// VoteStorage is satisfied by storage.FeedbackPostgresStorage.
+type VoteStorage interface {
+	ToggleVote(ctx context.Context, vote model.Vote) (bool, error)
+	VoteCounts(ctx context.Context, chatID int64, messageID int) (map[string]int, error)
+}
+
+// ViewCallbackFeedback records a press of the vote buttons under a post and
+// updates the counts on the buttons.
+func ViewCallbackFeedback(votes VoteStorage) botkit.ViewFunc {
+	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		query := update.CallbackQuery
+		if query.Message == nil || query.From == nil {
+			_, err := api.Request(tgbotapi.NewCallback(query.ID, ""))
+			return err
+		}
+
+		vote, articleID, err := feedback.ParseCallback(query.Data)
+		if err != nil {
+			return err
+		}
+
+		chatID, messageID := query.Message.Chat.ID, query.Message.MessageID
+		set, err := votes.ToggleVote(ctx, model.Vote{
+			ChatID:    chatID,
+			MessageID: messageID,
+			ArticleID: articleID,
+			UserID:    query.From.ID,
+			Vote:      vote,
+		})
+		if err != nil {
+			return fmt.Errorf("toggle vote: %w", err)
+		}
+
+		answer := i18n.T(ctx, i18n.FeedbackThanks)
+		if !set {
+			answer = i18n.T(ctx, i18n.FeedbackRemoved)
+		}
+		if _, err := api.Request(tgbotapi.NewCallback(query.ID, answer)); err != nil {
+			return err
+		}
+
+		counts, err := votes.VoteCounts(ctx, chatID, messageID)
+		if err != nil {
+			return fmt.Errorf("count votes: %w", err)
+		}
+		_, err = api.Request(tgbotapi.NewEditMessageReplyMarkup(chatID, messageID, feedback.Keyboard(articleID, counts)))
+		return err
+	}
+}
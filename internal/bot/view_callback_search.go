@@ -0,0 +1,48 @@
+package bot
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+)
+
+// SearchCallbackPrefix routes the page buttons of /search results.
+const SearchCallbackPrefix = "search"
+
+// ViewCallbackSearch turns the page of a /search results message. The query
+// is read back from the first line of the message.
+func ViewCallbackSearch(searcher ArticleSearcher, sources SourceLister) botkit.ViewFunc {
+	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		query := update.CallbackQuery
+
+		_, args := botkit.ParseCallbackData(query.Data)
+		firstLine, _, _ := strings.Cut(messageText(query.Message), "\n")
+		search, ok := strings.CutPrefix(firstLine, searchQueryMark)
+		if len(args) != 1 || !ok {
+			_, err := api.Request(tgbotapi.NewCallback(query.ID, ""))
+			return err
+		}
+
+		page, err := strconv.Atoi(args[0])
+		if err != nil {
+			return err
+		}
+
+		text, keyboard, err := searchPage(ctx, searcher, sources, search, page)
+		if err != nil {
+			return err
+		}
+		return answerAndEdit(api, query.ID, "", query.Message.Chat.ID, query.Message.MessageID, text, keyboard)
+	}
+}
+
+func messageText(msg *tgbotapi.Message) string {
+	if msg == nil {
+		return ""
+	}
+	return msg.Text
+}
@@ -0,0 +1,276 @@
+package bot
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+// SourcesCallbackPrefix routes the buttons of the source browser.
+const SourcesCallbackPrefix = "src"
+
+const (
+	sourcesPageSize = 8
+	// testFetchTitles bounds the titles listed after a test fetch.
+	testFetchTitles = 5
+)
+
+// Source browser actions. Every callback carries the source ID, or the page
+// for srcActPage, and the list page to return to.
+const (
+	srcActPage     = "page"
+	srcActShow     = "show"
+	srcActUp       = "up"
+	srcActDown     = "down"
+	srcActPause    = "pause"
+	srcActResume   = "resume"
+	srcActTest     = "test"
+	srcActEdit     = "edit"
+	srcActDelete   = "del"
+	srcActDeleteOK = "delok"
+)
+
+// SourceManager is satisfied by storage.SourcePostgresStorage.
+type SourceManager interface {
+	SourceLister
+	SourceProvider
+	PrioritySetter
+	SourceDeleter
+	SetEnabled(ctx context.Context, id int64, enabled bool) error
+}
+
+// SourceFetcher is satisfied by fetcher.Fetcher.
+type SourceFetcher interface {
+	FetchSource(ctx context.Context, source model.Source) ([]model.Item, error)
+}
+
+// ViewCallbackSources handles the buttons of the source browser opened by
+// /listsources, editing the browser message in place.
+func ViewCallbackSources(sources SourceManager, fetcher SourceFetcher, b *botkit.Bot) botkit.ViewFunc {
+	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		query := update.CallbackQuery
+		if query.Message == nil || query.From == nil {
+			_, err := api.Request(tgbotapi.NewCallback(query.ID, ""))
+			return err
+		}
+
+		action, id, page, err := parseSourcesCallback(query.Data)
+		if err != nil {
+			return err
+		}
+		chatID, messageID := query.Message.Chat.ID, query.Message.MessageID
+
+		if action == srcActPage {
+			all, err := sources.Sources(ctx)
+			if err != nil {
+				return err
+			}
+			text, keyboard := sourcesPage(ctx, all, int(id))
+			return answerAndEdit(api, query.ID, "", chatID, messageID, text, keyboard)
+		}
+
+		source, err := sources.SourceByID(ctx, id)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if source == nil || !source.DeletedAt.IsZero() {
+			_, err := api.Request(tgbotapi.NewCallback(query.ID, i18n.T(ctx, i18n.SourceNotFound, id)))
+			return err
+		}
+
+		switch action {
+		case srcActShow:
+		case srcActUp, srcActDown:
+			source.Priority++
+			if action == srcActDown {
+				source.Priority -= 2
+			}
+			if err := sources.SetPriority(ctx, id, source.Priority); err != nil {
+				return err
+			}
+		case srcActPause, srcActResume:
+			source.Enabled, source.PausedUntil = action == srcActResume, time.Time{}
+			if err := sources.SetEnabled(ctx, id, source.Enabled); err != nil {
+				return err
+			}
+		case srcActTest:
+			if _, err := api.Request(tgbotapi.NewCallback(query.ID, i18n.T(ctx, i18n.SourceFetching))); err != nil {
+				return err
+			}
+			_, err := api.Send(tgbotapi.NewMessage(chatID, testFetchReport(ctx, fetcher, *source)))
+			return err
+		case srcActEdit:
+			if _, err := api.Request(tgbotapi.NewCallback(query.ID, "")); err != nil {
+				return err
+			}
+			return b.StartConversation(ctx, editSourceConversation, chatID, query.From.ID, editSourceValues(*source, query.From.ID))
+		case srcActDelete:
+			return answerAndEdit(api, query.ID, "", chatID, messageID,
+				i18n.T(ctx, i18n.SourceDeleteConfirm, source.Name),
+				tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+					sourceButton(ctx, i18n.SourceButtonDeleteYes, srcActDeleteOK, id, page),
+					sourceButton(ctx, i18n.SourceButtonKeep, srcActShow, id, page),
+				)))
+		case srcActDeleteOK:
+			if err := sources.Delete(ctx, id); err != nil {
+				return err
+			}
+			all, err := sources.Sources(ctx)
+			if err != nil {
+				return err
+			}
+			text, keyboard := sourcesPage(ctx, all, page)
+			return answerAndEdit(api, query.ID, i18n.T(ctx, i18n.SourceDeleted, source.Name), chatID, messageID, text, keyboard)
+		default:
+			return fmt.Errorf("unknown source action %q", action)
+		}
+
+		text, keyboard := sourceCard(ctx, *source, page)
+		return answerAndEdit(api, query.ID, "", chatID, messageID, text, keyboard)
+	}
+}
+
+// parseSourcesCallback splits callback data made by sourceButton.
+func parseSourcesCallback(data string) (string, int64, int, error) {
+	_, args := botkit.ParseCallbackData(data)
+	if len(args) != 3 {
+		return "", 0, 0, fmt.Errorf("invalid source callback %q", data)
+	}
+	id, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("invalid source callback %q: %w", data, err)
+	}
+	page, err := strconv.Atoi(args[2])
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("invalid source callback %q: %w", data, err)
+	}
+	return args[0], id, page, nil
+}
+
+func sourceButton(ctx context.Context, label i18n.Key, action string, id int64, page int) tgbotapi.InlineKeyboardButton {
+	return tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, label), sourceCallbackData(action, id, page))
+}
+
+func sourceCallbackData(action string, id int64, page int) string {
+	return botkit.CallbackData(SourcesCallbackPrefix, action, strconv.FormatInt(id, 10), strconv.Itoa(page))
+}
+
+// sourcesPage lists one page of sources, highest priority first, as buttons
+// opening each source. page is clamped to the pages there are.
+func sourcesPage(ctx context.Context, sources []model.Source, page int) (string, tgbotapi.InlineKeyboardMarkup) {
+	if len(sources) == 0 {
+		return i18n.T(ctx, i18n.SourcesEmpty), tgbotapi.InlineKeyboardMarkup{}
+	}
+
+	sort.SliceStable(sources, func(i, j int) bool {
+		return sources[i].Priority > sources[j].Priority
+	})
+
+	pages := (len(sources) + sourcesPageSize - 1) / sourcesPageSize
+	page = max(0, min(page, pages-1))
+	from := page * sourcesPageSize
+	to := min(from+sourcesPageSize, len(sources))
+
+	var (
+		rows [][]tgbotapi.InlineKeyboardButton
+		now  = time.Now()
+	)
+	for _, s := range sources[from:to] {
+		label := fmt.Sprintf("%s · %d", s.Name, s.Priority)
+		if s.Paused(now) {
+			label = "⏸ " + label
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, sourceCallbackData(srcActShow, s.ID, page)),
+		))
+	}
+
+	var nav []tgbotapi.InlineKeyboardButton
+	if page > 0 {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.PageButtonPrev), sourceCallbackData(srcActPage, int64(page-1), page)))
+	}
+	if page < pages-1 {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.PageButtonNext), sourceCallbackData(srcActPage, int64(page+1), page)))
+	}
+	if len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+
+	return i18n.T(ctx, i18n.SourcesPage, len(sources), page+1, pages), tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// sourceCard shows a source with its management buttons.
+func sourceCard(ctx context.Context, s model.Source, page int) (string, tgbotapi.InlineKeyboardMarkup) {
+	status, toggle, toggleLabel := i18n.T(ctx, i18n.SourceActive), srcActPause, i18n.SourceButtonPause
+	switch {
+	case !s.Enabled:
+		status, toggle, toggleLabel = i18n.T(ctx, i18n.SourcePaused), srcActResume, i18n.SourceButtonResume
+	case s.Paused(time.Now()):
+		status, toggle, toggleLabel = i18n.T(ctx, i18n.SourcePausedUntil, s.PausedUntil.UTC().Format(pausedUntilLayout)), srcActResume, i18n.SourceButtonResume
+	}
+
+	text := i18n.T(ctx, i18n.SourceDetails, s.Name, s.ID, s.SourceType, s.FeedURL, s.Priority, status)
+	if s.ScraperConfig != nil {
+		text += i18n.T(ctx, i18n.SourceDetailsWeb, s.ScraperConfig.LinkSelector, s.ScraperConfig.BaseURL)
+	}
+
+	return text, tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			sourceButton(ctx, i18n.SourceButtonUp, srcActUp, s.ID, page),
+			sourceButton(ctx, i18n.SourceButtonDown, srcActDown, s.ID, page),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			sourceButton(ctx, toggleLabel, toggle, s.ID, page),
+			sourceButton(ctx, i18n.SourceButtonTest, srcActTest, s.ID, page),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			sourceButton(ctx, i18n.SourceButtonEdit, srcActEdit, s.ID, page),
+			sourceButton(ctx, i18n.SourceButtonDelete, srcActDelete, s.ID, page),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.SourceButtonList), sourceCallbackData(srcActPage, int64(page), page)),
+		),
+	)
+}
+
+// testFetchReport fetches a source without storing anything and describes
+// the result.
+func testFetchReport(ctx context.Context, fetcher SourceFetcher, source model.Source) string {
+	items, err := fetcher.FetchSource(ctx, source)
+	if err != nil {
+		return i18n.T(ctx, i18n.SourceTestFailed, source.Name, err)
+	}
+
+	lines := []string{i18n.T(ctx, i18n.SourceTestResult, source.Name, len(items))}
+	for _, item := range items[:min(len(items), testFetchTitles)] {
+		lines = append(lines, "• "+item.Title)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// answerAndEdit answers a callback query and replaces the message it came
+// from.
+func answerAndEdit(api *tgbotapi.BotAPI, queryID, answer string, chatID int64, messageID int, text string, keyboard tgbotapi.InlineKeyboardMarkup) error {
+	if _, err := api.Request(tgbotapi.NewCallback(queryID, answer)); err != nil {
+		return err
+	}
+
+	edit := tgbotapi.NewEditMessageText(chatID, messageID, text)
+	edit.DisableWebPagePreview = true
+	if len(keyboard.InlineKeyboard) > 0 {
+		edit.ReplyMarkup = &keyboard
+	}
+	_, err := api.Request(edit)
+	return err
+}
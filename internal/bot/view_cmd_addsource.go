@@ -2,7 +2,7 @@ package bot
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"net/http"
 	"time"
 
@@ -10,155 +10,167 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
 	"github.com/0x0BSoD/newsMaker/internal/model"
 )
 
 const feedProbeTimeout = 15 * time.Second
 
+// Steps of the /addsource conversation.
+const (
+	stepSourceName     = "name"
+	stepSourceType     = "type"
+	stepSourceURL      = "url"
+	stepSourceSelector = "selector"
+	stepSourceBaseURL  = "base_url"
+	stepSourceConfirm  = "confirm"
+)
+
+const answerSave = "save"
+
 type SourceStorage interface {
 	Add(ctx context.Context, source model.Source) (int64, error)
 }
 
-func ViewCmdAddSource(storage SourceStorage, b *botkit.Bot) botkit.ViewFunc {
-	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
-		chatID := update.Message.Chat.ID
-
-		if _, err := api.Send(tgbotapi.NewMessage(chatID, "Введите название источника:")); err != nil {
-			return err
-		}
-
-		b.RegisterMsgHandler(chatID, askTypeHandler(storage, b, chatID))
-		return nil
+// AddSourceConversation asks for the name, type and URL of a new source and,
+// for web sources, the link selector and base URL. The URL is probed before
+// the dialog moves on, and the source is saved once its preview is
+// confirmed.
+func AddSourceConversation(storage SourceStorage, previewer SourcePreviewer) botkit.Conversation {
+	return botkit.Conversation{
+		Name:  "addsource",
+		First: stepSourceName,
+		Steps: map[string]botkit.Step{
+			stepSourceName: {
+				Prompt: stepPrompt(i18n.AddSourceName),
+				Next:   nextStep(stepSourceType),
+			},
+			stepSourceType: {
+				Prompt: stepPrompt(i18n.AddSourceType),
+				Choices: func(context.Context, botkit.Values) []botkit.Choice {
+					return []botkit.Choice{
+						{Label: "RSS", Value: model.SourceTypeRSS},
+						{Label: "Web", Value: model.SourceTypeWeb},
+					}
+				},
+				Validate: validateSourceType,
+				Next:     nextStep(stepSourceURL),
+			},
+			stepSourceURL: {
+				Prompt: func(ctx context.Context, values botkit.Values) string {
+					if values[stepSourceType] == model.SourceTypeWeb {
+						return i18n.T(ctx, i18n.AddSourceListingURL)
+					}
+					return i18n.T(ctx, i18n.AddSourceFeedURL)
+				},
+				Validate: validateSourceURL,
+				Next: func(values botkit.Values) string {
+					if values[stepSourceType] == model.SourceTypeWeb {
+						return stepSourceSelector
+					}
+					return stepSourceConfirm
+				},
+			},
+			stepSourceSelector: {
+				Prompt: stepPrompt(i18n.AddSourceLinkSelector),
+				Next:   nextStep(stepSourceBaseURL),
+			},
+			stepSourceBaseURL: {
+				Prompt: stepPrompt(i18n.AddSourceBaseURL),
+				Next:   nextStep(stepSourceConfirm),
+			},
+			stepSourceConfirm: {
+				Prompt: func(ctx context.Context, values botkit.Values) string {
+					return previewReport(ctx, previewer, newSourceFromValues(values)) + "\n\n" + i18n.T(ctx, i18n.AddSourceConfirm)
+				},
+				Choices: func(ctx context.Context, _ botkit.Values) []botkit.Choice {
+					return []botkit.Choice{{Label: i18n.T(ctx, i18n.AddSourceSave), Value: answerSave}}
+				},
+				Validate: func(ctx context.Context, input string, _ botkit.Values) (string, error) {
+					if input != answerSave {
+						return "", errors.New(i18n.T(ctx, i18n.AddSourceConfirmHint))
+					}
+					return input, nil
+				},
+			},
+		},
+		Done: func(ctx context.Context, api *tgbotapi.BotAPI, chatID int64, values botkit.Values) error {
+			return storeSource(ctx, api, storage, chatID, newSourceFromValues(values))
+		},
 	}
 }
 
-func askTypeHandler(storage SourceStorage, b *botkit.Bot, chatID int64) botkit.ViewFunc {
-	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
-		name := update.Message.Text
-
-		msg := tgbotapi.NewMessage(chatID, "Выберите тип источника: rss или web")
-		if _, err := api.Send(msg); err != nil {
-			return err
+// newSourceFromValues builds the source described by the /addsource answers.
+func newSourceFromValues(values botkit.Values) model.Source {
+	source := model.Source{
+		Name:       values[stepSourceName],
+		FeedURL:    values[stepSourceURL],
+		SourceType: values[stepSourceType],
+	}
+	if source.SourceType == model.SourceTypeWeb {
+		source.ScraperConfig = &model.ScraperConfig{
+			LinkSelector: values[stepSourceSelector],
+			BaseURL:      values[stepSourceBaseURL],
 		}
-
-		b.RegisterMsgHandler(chatID, askURLHandler(storage, b, chatID, name))
-		return nil
 	}
+	return source
 }
 
-func askURLHandler(storage SourceStorage, b *botkit.Bot, chatID int64, name string) botkit.ViewFunc {
-	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
-		sourceType := update.Message.Text
-		if sourceType != model.SourceTypeRSS && sourceType != model.SourceTypeWeb {
-			reply := tgbotapi.NewMessage(chatID,
-				fmt.Sprintf("Неверный тип %q. Введите rss или web:", sourceType))
-			_, _ = api.Send(reply)
-			b.RegisterMsgHandler(chatID, askURLHandler(storage, b, chatID, name))
-			return nil
-		}
-
-		prompt := "Введите URL RSS-фида:"
-		if sourceType == model.SourceTypeWeb {
-			prompt = "Введите URL страницы-листинга (например, https://platformengineering.org/blog):"
-		}
-		if _, err := api.Send(tgbotapi.NewMessage(chatID, prompt)); err != nil {
-			return err
-		}
-
-		b.RegisterMsgHandler(chatID, saveSourceHandler(storage, b, chatID, name, sourceType))
-		return nil
+// stepPrompt returns a step prompt that is a fixed message.
+func stepPrompt(key i18n.Key) func(context.Context, botkit.Values) string {
+	return func(ctx context.Context, _ botkit.Values) string {
+		return i18n.T(ctx, key)
 	}
 }
 
-func saveSourceHandler(storage SourceStorage, b *botkit.Bot, chatID int64, name, sourceType string) botkit.ViewFunc {
-	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
-		feedURL := update.Message.Text
-
-		source := model.Source{
-			Name:       name,
-			FeedURL:    feedURL,
-			SourceType: sourceType,
-		}
-
-		switch sourceType {
-		case model.SourceTypeRSS:
-			probeClient := &http.Client{Timeout: feedProbeTimeout}
-			if _, err := rss.FetchByClient(feedURL, probeClient); err != nil {
-				reply := tgbotapi.NewMessage(chatID,
-					fmt.Sprintf("Не удалось получить фид по указанному URL: %v\n\nВведите другой URL или отправьте команду для отмены.", err))
-				_, _ = api.Send(reply)
-				b.RegisterMsgHandler(chatID, saveSourceHandler(storage, b, chatID, name, sourceType))
-				return nil
-			}
-
-		case model.SourceTypeWeb:
-			if _, err := api.Send(tgbotapi.NewMessage(chatID,
-				"Введите CSS-селектор для ссылок (например, a[href^='/blog/']):")); err != nil {
-				return err
-			}
-			b.RegisterMsgHandler(chatID, saveWebSourceHandler(storage, b, chatID, source))
-			return nil
-		}
-
-		return storeSource(ctx, api, storage, b, chatID, source)
+// nextStep returns a Next that always goes to step.
+func nextStep(step string) func(botkit.Values) string {
+	return func(botkit.Values) string {
+		return step
 	}
 }
 
-func saveWebSourceHandler(storage SourceStorage, b *botkit.Bot, chatID int64, source model.Source) botkit.ViewFunc {
-	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
-		linkSelector := update.Message.Text
-
-		if _, err := api.Send(tgbotapi.NewMessage(chatID,
-			"Введите базовый URL (например, https://platformengineering.org):")); err != nil {
-			return err
-		}
-
-		b.RegisterMsgHandler(chatID, saveWebSourceBaseURLHandler(storage, b, chatID, source, linkSelector))
-		return nil
+func validateSourceType(ctx context.Context, input string, _ botkit.Values) (string, error) {
+	if input != model.SourceTypeRSS && input != model.SourceTypeWeb {
+		return "", errors.New(i18n.T(ctx, i18n.AddSourceBadType, input))
 	}
+	return input, nil
 }
 
-func saveWebSourceBaseURLHandler(storage SourceStorage, b *botkit.Bot, chatID int64, source model.Source, linkSelector string) botkit.ViewFunc {
-	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
-		baseURL := update.Message.Text
-
-		probeClient := &http.Client{Timeout: feedProbeTimeout}
-		resp, err := probeClient.Get(source.FeedURL) //nolint:noctx
-		if err != nil || resp.StatusCode != http.StatusOK {
-			errMsg := fmt.Sprintf("URL недоступен: %v", err)
-			if err == nil {
-				errMsg = fmt.Sprintf("URL вернул статус %d", resp.StatusCode)
-			}
-			reply := tgbotapi.NewMessage(chatID, errMsg+"\n\nВведите другой URL или отправьте команду для отмены.")
-			_, _ = api.Send(reply)
-			b.RegisterMsgHandler(chatID, saveWebSourceBaseURLHandler(storage, b, chatID, source, linkSelector))
-			return nil
-		}
-		resp.Body.Close()
+// validateSourceURL checks that an RSS URL serves a feed and a web URL
+// answers with 200 OK.
+func validateSourceURL(ctx context.Context, input string, values botkit.Values) (string, error) {
+	probeClient := &http.Client{Timeout: feedProbeTimeout}
 
-		source.ScraperConfig = &model.ScraperConfig{
-			LinkSelector: linkSelector,
-			BaseURL:      baseURL,
+	if values[stepSourceType] == model.SourceTypeRSS {
+		if _, err := rss.FetchByClient(input, probeClient); err != nil {
+			return "", errors.New(i18n.T(ctx, i18n.AddSourceFeedFailed, err))
 		}
+		return input, nil
+	}
 
-		return storeSource(ctx, api, storage, b, chatID, source)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, input, nil)
+	if err != nil {
+		return "", errors.New(i18n.T(ctx, i18n.AddSourceURLUnreachable, err))
 	}
+	resp, err := probeClient.Do(req)
+	if err != nil {
+		return "", errors.New(i18n.T(ctx, i18n.AddSourceURLUnreachable, err))
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.New(i18n.T(ctx, i18n.AddSourceURLStatus, resp.StatusCode))
+	}
+	return input, nil
 }
 
-func storeSource(ctx context.Context, api *tgbotapi.BotAPI, storage SourceStorage, b *botkit.Bot, chatID int64, source model.Source) error {
+func storeSource(ctx context.Context, api *tgbotapi.BotAPI, storage SourceStorage, chatID int64, source model.Source) error {
 	sourceID, err := storage.Add(ctx, source)
 	if err != nil {
-		b.ClearMsgHandler(chatID)
 		return err
 	}
 
-	b.ClearMsgHandler(chatID)
-
-	msgText := fmt.Sprintf(
-		"Источник добавлен с ID: `%d`\\. Используйте этот ID для обновления источника или удаления\\.",
-		sourceID,
-	)
-	reply := tgbotapi.NewMessage(chatID, msgText)
+	reply := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.AddSourceDone, sourceID))
 	reply.ParseMode = parseModeMarkdownV2
 
 	if _, err := api.Send(reply); err != nil {
@@ -7,6 +7,7 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
 )
 
 type SourceDeleter interface {
@@ -26,7 +27,7 @@ func ViewCmdDeleteSource(deleter SourceDeleter) botkit.ViewFunc {
 			return nil
 		}
 
-		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Источник успешно удален")
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, i18n.T(ctx, i18n.DeleteSourceDone))
 		if _, err := bot.Send(msg); err != nil {
 			return err
 		}
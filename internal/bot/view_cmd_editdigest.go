@@ -0,0 +1,46 @@
+package bot
+
+import (
+	"context"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+	"github.com/0x0BSoD/newsMaker/internal/summary"
+)
+
+type PostProvider interface {
+	Post(ctx context.Context, id int64) (*model.Post, error)
+}
+
+type DigestRegenerator interface {
+	Regenerate(ctx context.Context, post model.Post) error
+}
+
+// ViewCmdEditDigest regenerates a posted news digest from its articles and
+// edits the channel message in place.
+func ViewCmdEditDigest(posts PostProvider, regenerator DigestRegenerator) botkit.ViewFunc {
+	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		chatID := update.Message.Chat.ID
+
+		post, err := postArg(ctx, api, update, posts, i18n.EditDigestUsage)
+		if err != nil || post == nil {
+			return err
+		}
+
+		if _, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.EditDigestRunning, post.ID))); err != nil {
+			return err
+		}
+
+		// A cached answer would bring back the text being replaced.
+		if err := regenerator.Regenerate(summary.WithoutCache(ctx), *post); err != nil {
+			_, _ = api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.EditDigestFailed, err)))
+			return err
+		}
+
+		_, err = api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.EditDigestDone, post.ID)))
+		return err
+	}
+}
@@ -0,0 +1,214 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+const editSourceConversation = "editsource"
+
+// Steps and preset values of the edit source conversation.
+const (
+	stepEditField = "field"
+	stepEditValue = "value"
+	valSourceID   = "source_id"
+	valSourceName = "source_name"
+	valSourceType = "source_type"
+	valEditorID   = "editor_id"
+)
+
+// Fields the edit source conversation changes.
+const (
+	fieldName     = "name"
+	fieldURL      = "url"
+	fieldSelector = "selector"
+	fieldBaseURL  = "base_url"
+	fieldInsecure = "insecure"
+)
+
+const (
+	answerYes = "yes"
+	answerNo  = "no"
+)
+
+var fieldLabels = map[string]i18n.Key{
+	fieldName:     i18n.EditSourceFieldName,
+	fieldURL:      i18n.EditSourceFieldURL,
+	fieldSelector: i18n.EditSourceFieldSel,
+	fieldBaseURL:  i18n.EditSourceFieldBaseURL,
+	fieldInsecure: i18n.EditSourceFieldTLS,
+}
+
+// SourceUpdater is satisfied by storage.SourcePostgresStorage.
+type SourceUpdater interface {
+	SourceProvider
+	Update(ctx context.Context, source model.Source, changedBy int64) error
+}
+
+// ViewCmdEditSource starts the edit source conversation for /editsource <id>.
+func ViewCmdEditSource(sources SourceProvider, b *botkit.Bot) botkit.ViewFunc {
+	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		chatID := update.Message.Chat.ID
+		source, err := sourceArg(ctx, api, chatID, update.Message.CommandArguments(), sources, i18n.EditSourceUsage)
+		if err != nil || source == nil {
+			return err
+		}
+
+		userID := update.SentFrom().ID
+		return b.StartConversation(ctx, editSourceConversation, chatID, userID, editSourceValues(*source, userID))
+	}
+}
+
+// EditSourceConversation changes one field of a source. It is started with
+// the source ID, name and type and the editor preset; see editSourceValues.
+// A new URL, selector, base URL or TLS setting is kept only if the source
+// still fetches with it, and every change is recorded with the old values.
+func EditSourceConversation(sources SourceUpdater, fetcher SourceFetcher) botkit.Conversation {
+	return botkit.Conversation{
+		Name:  editSourceConversation,
+		First: stepEditField,
+		Steps: map[string]botkit.Step{
+			stepEditField: {
+				Prompt: func(ctx context.Context, values botkit.Values) string {
+					return i18n.T(ctx, i18n.EditSourceField, values[valSourceName])
+				},
+				Choices: func(ctx context.Context, values botkit.Values) []botkit.Choice {
+					choices := make([]botkit.Choice, 0, len(fieldLabels))
+					for _, f := range editableFields(values[valSourceType]) {
+						choices = append(choices, botkit.Choice{Label: i18n.T(ctx, fieldLabels[f]), Value: f})
+					}
+					return choices
+				},
+				Validate: func(ctx context.Context, input string, values botkit.Values) (string, error) {
+					for _, f := range editableFields(values[valSourceType]) {
+						if input == f {
+							return input, nil
+						}
+					}
+					return "", errors.New(i18n.T(ctx, i18n.EditSourceBadField))
+				},
+				Next: nextStep(stepEditValue),
+			},
+			stepEditValue: {
+				Prompt: func(ctx context.Context, values botkit.Values) string {
+					if values[stepEditField] == fieldInsecure {
+						return i18n.T(ctx, i18n.EditSourceInsecure)
+					}
+					return i18n.T(ctx, i18n.EditSourceValue, i18n.T(ctx, fieldLabels[values[stepEditField]]))
+				},
+				Choices: func(ctx context.Context, values botkit.Values) []botkit.Choice {
+					if values[stepEditField] != fieldInsecure {
+						return nil
+					}
+					return []botkit.Choice{
+						{Label: i18n.T(ctx, i18n.Yes), Value: answerYes},
+						{Label: i18n.T(ctx, i18n.No), Value: answerNo},
+					}
+				},
+				Validate: func(ctx context.Context, input string, values botkit.Values) (string, error) {
+					field := values[stepEditField]
+					if field == fieldInsecure && input != answerYes && input != answerNo {
+						return "", errors.New(i18n.T(ctx, i18n.EditSourceBadBool))
+					}
+					if field == fieldName {
+						return input, nil
+					}
+
+					source, err := editedSource(ctx, sources, values, input)
+					if err != nil {
+						return "", err
+					}
+					return input, probeSource(ctx, fetcher, *source)
+				},
+			},
+		},
+		Done: func(ctx context.Context, api *tgbotapi.BotAPI, chatID int64, values botkit.Values) error {
+			source, err := editedSource(ctx, sources, values, values[stepEditValue])
+			if err != nil {
+				return err
+			}
+			editor, err := strconv.ParseInt(values[valEditorID], 10, 64)
+			if err != nil {
+				return err
+			}
+			if err := sources.Update(ctx, *source, editor); err != nil {
+				return err
+			}
+
+			_, err = api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.EditSourceDone, source.ID)))
+			return err
+		},
+	}
+}
+
+// editSourceValues are the preset values of the edit source conversation.
+func editSourceValues(source model.Source, editorID int64) botkit.Values {
+	return botkit.Values{
+		valSourceID:   strconv.FormatInt(source.ID, 10),
+		valSourceName: source.Name,
+		valSourceType: source.SourceType,
+		valEditorID:   strconv.FormatInt(editorID, 10),
+	}
+}
+
+// editedSource loads the source being edited and sets the chosen field to
+// value.
+func editedSource(ctx context.Context, sources SourceProvider, values botkit.Values, value string) (*model.Source, error) {
+	id, err := strconv.ParseInt(values[valSourceID], 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	source, err := sources.SourceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	applySourceField(source, values[stepEditField], value)
+	return source, nil
+}
+
+// probeSource fetches source without storing anything. A web source must
+// yield at least one link, so a selector matching nothing is caught.
+func probeSource(ctx context.Context, fetcher SourceFetcher, source model.Source) error {
+	items, err := fetcher.FetchSource(ctx, source)
+	if err != nil {
+		return errors.New(i18n.T(ctx, i18n.EditSourceProbeFailed, err))
+	}
+	if len(items) == 0 && source.SourceType == model.SourceTypeWeb {
+		return errors.New(i18n.T(ctx, i18n.EditSourceProbeEmpty))
+	}
+	return nil
+}
+
+func editableFields(sourceType string) []string {
+	if sourceType == model.SourceTypeWeb {
+		return []string{fieldName, fieldURL, fieldSelector, fieldBaseURL, fieldInsecure}
+	}
+	return []string{fieldName, fieldURL, fieldInsecure}
+}
+
+func applySourceField(source *model.Source, field, value string) {
+	switch field {
+	case fieldName:
+		source.Name = value
+	case fieldURL:
+		source.FeedURL = value
+	case fieldInsecure:
+		source.Insecure = value == answerYes
+	case fieldSelector, fieldBaseURL:
+		if source.ScraperConfig == nil {
+			source.ScraperConfig = &model.ScraperConfig{}
+		}
+		if field == fieldSelector {
+			source.ScraperConfig.LinkSelector = value
+		} else {
+			source.ScraperConfig.BaseURL = value
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+	"github.com/0x0BSoD/newsMaker/internal/ranking"
+)
+
+// maxMessageLen is Telegram's limit on the text of one message.
+const maxMessageLen = 4096
+
+type NewsExplainer interface {
+	Explain(ctx context.Context) ([]ranking.Score, error)
+}
+
+// ViewCmdExplainNews lists the current digest candidates with their scores
+// and why each would be picked or dropped.
+func ViewCmdExplainNews(e NewsExplainer) botkit.ViewFunc {
+	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		chatID := update.Message.Chat.ID
+
+		scores, err := e.Explain(ctx)
+		if err != nil {
+			_, _ = api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.ExplainFailed, err)))
+			return err
+		}
+		if len(scores) == 0 {
+			_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.ExplainEmpty)))
+			return err
+		}
+
+		var picked int
+		lines := make([]string, 0, len(scores)+1)
+		for _, s := range scores {
+			if s.Picked {
+				picked++
+			}
+		}
+		lines = append(lines, i18n.T(ctx, i18n.ExplainHeader, len(scores), picked))
+
+		for i, s := range scores {
+			mark := "✅"
+			if !s.Picked {
+				mark = "❌ " + s.Reason
+			}
+			lines = append(lines, fmt.Sprintf("%d. %s\n[%s] %s\n%s\n", i+1, mark, s.Article.SourceName, s.Article.Title, s.Explain()))
+		}
+
+		for _, msg := range splitLines(lines, maxMessageLen) {
+			if _, err := api.Send(tgbotapi.NewMessage(chatID, msg)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// splitLines joins lines into messages of at most limit bytes. A single line
+// longer than limit is cut at the last rune boundary that fits.
+func splitLines(lines []string, limit int) []string {
+	var (
+		messages []string
+		sb       strings.Builder
+	)
+	for _, line := range lines {
+		if len(line) > limit {
+			cut := limit
+			for cut > 0 && !utf8.RuneStart(line[cut]) {
+				cut--
+			}
+			line = line[:cut]
+		}
+		if sb.Len()+len(line)+1 > limit {
+			messages = append(messages, sb.String())
+			sb.Reset()
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	if sb.Len() > 0 {
+		messages = append(messages, sb.String())
+	}
+	return messages
+}
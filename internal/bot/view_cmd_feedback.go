@@ -0,0 +1,72 @@
+package bot
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+const (
+	defaultFeedbackDays = 7
+	// feedbackTopArticles bounds the articles listed in the report.
+	feedbackTopArticles = 10
+)
+
+type FeedbackReporter interface {
+	FeedbackStats(ctx context.Context, since time.Time, limit int) (model.FeedbackStats, error)
+}
+
+// ViewCmdFeedback reports the reader votes of the last days, seven by
+// default: totals, per source and the most voted articles.
+func ViewCmdFeedback(reporter FeedbackReporter) botkit.ViewFunc {
+	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		chatID := update.Message.Chat.ID
+
+		days := defaultFeedbackDays
+		if arg := strings.TrimSpace(update.Message.CommandArguments()); arg != "" {
+			n, err := strconv.Atoi(arg)
+			if err != nil || n <= 0 {
+				_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.FeedbackUsage)))
+				return err
+			}
+			days = n
+		}
+
+		stats, err := reporter.FeedbackStats(ctx, time.Now().AddDate(0, 0, -days), feedbackTopArticles)
+		if err != nil {
+			return err
+		}
+
+		lines := []string{i18n.T(ctx, i18n.FeedbackHeader,
+			days, stats.Totals[model.VoteUp], stats.Totals[model.VoteDown], stats.Totals[model.VoteMore])}
+
+		if len(stats.Sources) > 0 {
+			lines = append(lines, i18n.T(ctx, i18n.FeedbackBySource))
+			for _, s := range stats.Sources {
+				lines = append(lines, i18n.T(ctx, i18n.FeedbackSourceLine, s.SourceName, s.Up, s.Down, s.More))
+			}
+			lines = append(lines, "")
+		}
+
+		if len(stats.Articles) > 0 {
+			lines = append(lines, i18n.T(ctx, i18n.FeedbackTopArticles))
+			for i, a := range stats.Articles {
+				lines = append(lines, i18n.T(ctx, i18n.FeedbackArticleLine, i+1, a.Title, a.Up, a.Down, a.More, a.Link))
+			}
+		}
+
+		for _, msg := range splitLines(lines, maxMessageLen) {
+			if _, err := api.Send(tgbotapi.NewMessage(chatID, msg)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -2,13 +2,16 @@ package bot
 
 import (
 	"context"
-	"fmt"
+	"database/sql"
+	"errors"
 	"strconv"
+	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"github.com/0x0BSoD/newsMaker/internal/botkit"
 	"github.com/0x0BSoD/newsMaker/internal/botkit/markup"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
 	"github.com/0x0BSoD/newsMaker/internal/model"
 )
 
@@ -18,19 +21,12 @@ type SourceProvider interface {
 
 func ViewCmdGetSource(provider SourceProvider) botkit.ViewFunc {
 	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
-		idStr := update.Message.CommandArguments()
-
-		id, err := strconv.ParseInt(idStr, 10, 64)
-		if err != nil {
+		source, err := sourceArg(ctx, bot, update.Message.Chat.ID, update.Message.CommandArguments(), provider, i18n.GetSourceUsage)
+		if err != nil || source == nil {
 			return err
 		}
 
-		source, err := provider.SourceByID(ctx, id)
-		if err != nil {
-			return err
-		}
-
-		reply := tgbotapi.NewMessage(update.Message.Chat.ID, formatSource(*source))
+		reply := tgbotapi.NewMessage(update.Message.Chat.ID, formatSource(ctx, *source))
 		reply.ParseMode = parseModeMarkdownV2
 
 		if _, err := bot.Send(reply); err != nil {
@@ -41,12 +37,32 @@ func ViewCmdGetSource(provider SourceProvider) botkit.ViewFunc {
 	}
 }
 
-func formatSource(source model.Source) string {
-	return fmt.Sprintf(
-		"🌐 *%s*\nID: `%d`\nURL фида: %s\nПриоритет: %d",
+func formatSource(ctx context.Context, source model.Source) string {
+	return i18n.T(ctx, i18n.SourceInfo,
 		markup.EscapeForMarkdown(source.Name),
 		source.ID,
 		markup.EscapeForMarkdown(source.FeedURL),
 		source.Priority,
 	)
 }
+
+// sourceArg loads the source whose ID is arg. It replies to chatID with
+// usage or a not found message and returns nil when there is no such source;
+// deleted sources count as missing.
+func sourceArg(ctx context.Context, api *tgbotapi.BotAPI, chatID int64, arg string, provider SourceProvider, usage i18n.Key) (*model.Source, error) {
+	id, err := strconv.ParseInt(strings.TrimSpace(arg), 10, 64)
+	if err != nil {
+		_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, usage)))
+		return nil, err
+	}
+
+	source, err := provider.SourceByID(ctx, id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+	if source == nil || !source.DeletedAt.IsZero() {
+		_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.SourceNotFound, id)))
+		return nil, err
+	}
+	return source, nil
+}
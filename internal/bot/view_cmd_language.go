@@ -0,0 +1,50 @@
+package bot
+
+import (
+	"context"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+)
+
+// argAuto clears the chosen language so replies follow Telegram's.
+const argAuto = "auto"
+
+type LanguageSetter interface {
+	SetLanguage(ctx context.Context, userID int64, language string) error
+}
+
+// ViewCmdLanguage shows or sets the language of the bot replies for the
+// admin sending it.
+func ViewCmdLanguage(setter LanguageSetter) botkit.ViewFunc {
+	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		chatID := update.Message.Chat.ID
+		languages := strings.Join(i18n.Languages(), ", ")
+
+		arg := strings.ToLower(strings.TrimSpace(update.Message.CommandArguments()))
+		if arg == "" {
+			_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.LanguageCurrent, i18n.Language(ctx), languages)))
+			return err
+		}
+
+		language := i18n.Match(arg)
+		if language == "" && arg != argAuto {
+			_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.LanguageUsage, strings.Join(i18n.Languages(), "|"))))
+			return err
+		}
+
+		if err := setter.SetLanguage(ctx, update.SentFrom().ID, language); err != nil {
+			return err
+		}
+
+		reply := i18n.T(i18n.WithLanguage(ctx, language), i18n.LanguageSet)
+		if language == "" {
+			reply = i18n.T(ctx, i18n.LanguageAuto)
+		}
+		_, err := api.Send(tgbotapi.NewMessage(chatID, reply))
+		return err
+	}
+}
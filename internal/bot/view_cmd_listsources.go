@@ -2,12 +2,8 @@ package bot
 
 import (
 	"context"
-	"fmt"
-	"sort"
-	"strings"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
-	"github.com/samber/lo"
 
 	"github.com/0x0BSoD/newsMaker/internal/botkit"
 	"github.com/0x0BSoD/newsMaker/internal/model"
@@ -17,6 +13,8 @@ type SourceLister interface {
 	Sources(ctx context.Context) ([]model.Source, error)
 }
 
+// ViewCmdListSource opens the source browser on its first page; its buttons
+// are handled by ViewCallbackSources.
 func ViewCmdListSource(lister SourceLister) botkit.ViewFunc {
 	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
 		sources, err := lister.Sources(ctx)
@@ -24,21 +22,11 @@ func ViewCmdListSource(lister SourceLister) botkit.ViewFunc {
 			return err
 		}
 
-		sort.SliceStable(sources, func(i, j int) bool {
-			return sources[i].Priority > sources[j].Priority
-		})
-
-		var (
-			sourceInfos = lo.Map(sources, func(source model.Source, _ int) string { return formatSource(source) })
-			msgText     = fmt.Sprintf(
-				"Список источников \\(всего %d\\):\n\n%s",
-				len(sources),
-				strings.Join(sourceInfos, "\n\n"),
-			)
-		)
-
-		reply := tgbotapi.NewMessage(update.Message.Chat.ID, msgText)
-		reply.ParseMode = parseModeMarkdownV2
+		text, keyboard := sourcesPage(ctx, sources, 0)
+		reply := tgbotapi.NewMessage(update.Message.Chat.ID, text)
+		if len(keyboard.InlineKeyboard) > 0 {
+			reply.ReplyMarkup = keyboard
+		}
 
 		if _, err := bot.Send(reply); err != nil {
 			return err
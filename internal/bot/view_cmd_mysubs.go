@@ -0,0 +1,84 @@
+package bot
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+// SubscriptionLister is satisfied by storage.SubscriptionPostgresStorage.
+type SubscriptionLister interface {
+	UserSubscriptions(ctx context.Context, userID int64) ([]model.Subscription, error)
+}
+
+// ViewCmdMySubs lists the subscriptions of the sender.
+func ViewCmdMySubs(subs SubscriptionLister, sources SourceLister) botkit.ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		chatID := update.Message.Chat.ID
+
+		list, err := subs.UserSubscriptions(ctx, update.SentFrom().ID)
+		if err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			_, err := bot.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.MySubsEmpty)))
+			return err
+		}
+
+		known, err := sources.Sources(ctx)
+		if err != nil {
+			return err
+		}
+		names := make(map[int64]string, len(known))
+		for _, s := range known {
+			names[s.ID] = s.Name
+		}
+
+		lines := []string{i18n.T(ctx, i18n.MySubsHeader)}
+		for _, sub := range list {
+			lines = append(lines, formatSubscription(ctx, sub, names))
+		}
+
+		if _, err := bot.Send(tgbotapi.NewMessage(chatID, strings.Join(lines, "\n\n"))); err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
+
+// formatSubscription describes sub; sources missing from names are shown by
+// ID.
+func formatSubscription(ctx context.Context, sub model.Subscription, names map[int64]string) string {
+	var topics []string
+	if len(sub.Keywords) > 0 {
+		topics = append(topics, i18n.T(ctx, i18n.SubsKeywords, strings.Join(sub.Keywords, ", ")))
+	}
+	if len(sub.Categories) > 0 {
+		topics = append(topics, i18n.T(ctx, i18n.SubsCategories, strings.Join(sub.Categories, ", ")))
+	}
+	if len(sub.SourceIDs) > 0 {
+		sourceNames := make([]string, 0, len(sub.SourceIDs))
+		for _, id := range sub.SourceIDs {
+			name, ok := names[id]
+			if !ok {
+				name = "#" + strconv.FormatInt(id, 10)
+			}
+			sourceNames = append(sourceNames, name)
+		}
+		topics = append(topics, i18n.T(ctx, i18n.SubsSources, strings.Join(sourceNames, ", ")))
+	}
+
+	schedule := i18n.T(ctx, i18n.SubscribeDaily)
+	if sub.Schedule == model.ScheduleWeekly {
+		schedule = i18n.T(ctx, i18n.SubscribeWeekly)
+	}
+
+	return i18n.T(ctx, i18n.MySubsLine, sub.ID, schedule, sub.Hour, sub.Location(), strings.Join(topics, "\n"))
+}
@@ -0,0 +1,66 @@
+package bot
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+)
+
+const pausedUntilLayout = "2006-01-02 15:04 MST"
+
+// SourcePauser is satisfied by storage.SourcePostgresStorage.
+type SourcePauser interface {
+	SourceProvider
+	SetEnabled(ctx context.Context, id int64, enabled bool) error
+	PauseUntil(ctx context.Context, id int64, until time.Time) error
+}
+
+// ViewCmdPauseSource stops fetching a source until /resumesource, or for a
+// while: /pausesource <id> [duration].
+func ViewCmdPauseSource(sources SourcePauser) botkit.ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		chatID := update.Message.Chat.ID
+		args := strings.Fields(update.Message.CommandArguments())
+		if len(args) == 0 || len(args) > 2 {
+			_, err := bot.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.PauseSourceUsage)))
+			return err
+		}
+
+		var pause time.Duration
+		if len(args) == 2 {
+			d, err := time.ParseDuration(args[1])
+			if err != nil || d <= 0 {
+				_, err := bot.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.PauseSourceUsage)))
+				return err
+			}
+			pause = d
+		}
+
+		source, err := sourceArg(ctx, bot, chatID, args[0], sources, i18n.PauseSourceUsage)
+		if err != nil || source == nil {
+			return err
+		}
+
+		text := i18n.T(ctx, i18n.PauseSourceDone, source.Name)
+		if pause > 0 {
+			until := time.Now().Add(pause).UTC()
+			if err := sources.PauseUntil(ctx, source.ID, until); err != nil {
+				return err
+			}
+			text = i18n.T(ctx, i18n.PauseSourceDoneUntil, source.Name, until.Format(pausedUntilLayout))
+		} else if err := sources.SetEnabled(ctx, source.ID, false); err != nil {
+			return err
+		}
+
+		if _, err := bot.Send(tgbotapi.NewMessage(chatID, text)); err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
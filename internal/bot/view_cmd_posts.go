@@ -0,0 +1,91 @@
+package bot
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+const defaultPostsLimit = 10
+
+type PostLister interface {
+	RecentPosts(ctx context.Context, limit int) ([]model.Post, error)
+}
+
+// ViewCmdPosts lists the latest posts with the IDs /editdigest and
+// /retractpost take.
+func ViewCmdPosts(lister PostLister) botkit.ViewFunc {
+	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		chatID := update.Message.Chat.ID
+
+		limit := defaultPostsLimit
+		if arg := strings.TrimSpace(update.Message.CommandArguments()); arg != "" {
+			n, err := strconv.Atoi(arg)
+			if err != nil || n <= 0 {
+				_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.PostsUsage)))
+				return err
+			}
+			limit = n
+		}
+
+		posts, err := lister.RecentPosts(ctx, limit)
+		if err != nil {
+			return err
+		}
+		if len(posts) == 0 {
+			_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.PostsEmpty)))
+			return err
+		}
+
+		lines := make([]string, 0, len(posts))
+		for _, p := range posts {
+			line := i18n.T(ctx, i18n.PostsLine, p.ID, p.CreatedAt.Format("2006-01-02 15:04"), p.Kind, p.ChannelID, p.ArticleCount)
+			switch {
+			case !p.RetractedAt.IsZero():
+				line += i18n.T(ctx, i18n.PostsRetracted)
+			case !p.EditedAt.IsZero():
+				line += i18n.T(ctx, i18n.PostsEdited)
+			}
+			lines = append(lines, line)
+		}
+
+		for _, msg := range splitLines(lines, maxMessageLen) {
+			if _, err := api.Send(tgbotapi.NewMessage(chatID, msg)); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// postArg parses the post ID argument of a command and loads the post. It
+// replies with usage or a not-found message itself and then returns nil.
+func postArg(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update, provider PostProvider, usage i18n.Key) (*model.Post, error) {
+	chatID := update.Message.Chat.ID
+
+	id, err := strconv.ParseInt(strings.TrimPrefix(strings.TrimSpace(update.Message.CommandArguments()), "#"), 10, 64)
+	if err != nil {
+		_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, usage)))
+		return nil, err
+	}
+
+	post, err := provider.Post(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if post == nil {
+		_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.PostNotFound, id)))
+		return nil, err
+	}
+	if !post.RetractedAt.IsZero() {
+		_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.PostWasRetracted, id)))
+		return nil, err
+	}
+	return post, nil
+}
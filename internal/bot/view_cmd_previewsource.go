@@ -0,0 +1,162 @@
+package bot
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/fetcher"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+const (
+	// previewItems bounds the items listed by a preview.
+	previewItems = 10
+	// previewTextLimit keeps a preview within a Telegram message.
+	previewTextLimit = 3500
+)
+
+// SourcePreviewer is satisfied by fetcher.Fetcher.
+type SourcePreviewer interface {
+	Preview(ctx context.Context, source model.Source) ([]fetcher.PreviewItem, error)
+}
+
+// ViewCmdPreviewSource fetches a source without storing anything and shows
+// the items with the filter rule that would skip each. The argument is a
+// source ID, a feed URL, or a JSON source description for web sources.
+func ViewCmdPreviewSource(sources SourceProvider, previewer SourcePreviewer) botkit.ViewFunc {
+	type previewArgs struct {
+		Name         string `json:"name"`
+		URL          string `json:"url"`
+		Type         string `json:"type"`
+		LinkSelector string `json:"link_selector"`
+		BaseURL      string `json:"base_url"`
+		Insecure     bool   `json:"insecure"`
+	}
+
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		var (
+			chatID = update.Message.Chat.ID
+			arg    = strings.TrimSpace(update.Message.CommandArguments())
+			source *model.Source
+		)
+
+		switch {
+		case arg == "":
+			_, err := bot.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.PreviewSourceUsage)))
+			return err
+		case strings.HasPrefix(arg, "{"):
+			args, err := botkit.ParseJSON[previewArgs](arg)
+			if err != nil || args.URL == "" {
+				_, err := bot.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.PreviewSourceUsage)))
+				return err
+			}
+			source = &model.Source{
+				Name:       args.Name,
+				FeedURL:    args.URL,
+				SourceType: args.Type,
+				Insecure:   args.Insecure,
+			}
+			if source.SourceType == "" {
+				source.SourceType = model.SourceTypeRSS
+			}
+			if source.SourceType == model.SourceTypeWeb {
+				source.ScraperConfig = &model.ScraperConfig{LinkSelector: args.LinkSelector, BaseURL: args.BaseURL}
+			}
+		case isSourceID(arg):
+			var err error
+			if source, err = sourceArg(ctx, bot, chatID, arg, sources, i18n.PreviewSourceUsage); err != nil || source == nil {
+				return err
+			}
+		default:
+			source = &model.Source{FeedURL: arg, SourceType: model.SourceTypeRSS}
+		}
+		if source.Name == "" {
+			source.Name = i18n.T(ctx, i18n.PreviewSourceAdHocName)
+		}
+
+		if _, err := bot.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.SourceFetching))); err != nil {
+			return err
+		}
+
+		reply := tgbotapi.NewMessage(chatID, previewReport(ctx, previewer, *source))
+		reply.DisableWebPagePreview = true
+		if _, err := bot.Send(reply); err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
+
+// previewReport previews source and describes the result.
+func previewReport(ctx context.Context, previewer SourcePreviewer, source model.Source) string {
+	items, err := previewer.Preview(ctx, source)
+	if err != nil {
+		return i18n.T(ctx, i18n.SourceTestFailed, source.Name, err)
+	}
+	return formatPreview(ctx, source.Name, items)
+}
+
+func formatPreview(ctx context.Context, name string, items []fetcher.PreviewItem) string {
+	var skipped int
+	for _, item := range items {
+		if item.SkipKeyword != "" {
+			skipped++
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(i18n.T(ctx, i18n.PreviewSourceHeader, name, len(items), skipped))
+
+	shown := 0
+	for i, item := range items {
+		if i == previewItems {
+			break
+		}
+
+		lines := []string{strconv.Itoa(i+1) + ". " + item.Title, item.Link}
+		meta := make([]string, 0, 2)
+		if !item.Date.IsZero() {
+			meta = append(meta, item.Date.Format("2006-01-02 15:04"))
+		}
+		if len(item.Categories) > 0 {
+			meta = append(meta, strings.Join(item.Categories, ", "))
+		}
+		if len(meta) > 0 {
+			lines = append(lines, strings.Join(meta, " · "))
+		}
+		if item.SkipKeyword != "" {
+			lines = append(lines, i18n.T(ctx, i18n.PreviewSourceSkipped, previewField(ctx, item.SkipField), item.SkipKeyword))
+		}
+
+		entry := "\n\n" + strings.Join(lines, "\n")
+		if b.Len()+len(entry) > previewTextLimit {
+			break
+		}
+		b.WriteString(entry)
+		shown++
+	}
+
+	if rest := len(items) - shown; rest > 0 {
+		b.WriteString("\n\n" + i18n.T(ctx, i18n.PreviewSourceMore, rest))
+	}
+
+	return b.String()
+}
+
+func previewField(ctx context.Context, field string) string {
+	if field == fetcher.SkipFieldCategory {
+		return i18n.T(ctx, i18n.PreviewFieldCategory)
+	}
+	return i18n.T(ctx, i18n.PreviewFieldTitle)
+}
+
+func isSourceID(s string) bool {
+	_, err := strconv.ParseInt(s, 10, 64)
+	return err == nil
+}
@@ -0,0 +1,107 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+// PromptLibrary is satisfied by prompt.Library.
+type PromptLibrary interface {
+	Names() []string
+	Default(name string) (string, bool)
+}
+
+type PromptProvider interface {
+	ActivePrompt(ctx context.Context, name string) (*model.PromptTemplate, error)
+	PromptVersion(ctx context.Context, name string, version int) (*model.PromptTemplate, error)
+	PromptVersions(ctx context.Context, name string) ([]model.PromptTemplate, error)
+}
+
+// ViewCmdPrompts lists the prompt templates with their active versions.
+func ViewCmdPrompts(lib PromptLibrary, provider PromptProvider) botkit.ViewFunc {
+	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		var sb strings.Builder
+		sb.WriteString(i18n.T(ctx, i18n.PromptsHeader))
+
+		for _, name := range lib.Names() {
+			versions, err := provider.PromptVersions(ctx, name)
+			if err != nil {
+				return err
+			}
+
+			active := i18n.T(ctx, i18n.PromptDefault)
+			for _, v := range versions {
+				if v.Active {
+					active = fmt.Sprintf("v%d", v.Version)
+				}
+			}
+			sb.WriteString(i18n.T(ctx, i18n.PromptsLine, name, active, len(versions)))
+		}
+		sb.WriteString(i18n.T(ctx, i18n.PromptsHelp))
+
+		_, err := api.Send(tgbotapi.NewMessage(update.Message.Chat.ID, sb.String()))
+		return err
+	}
+}
+
+// ViewCmdPrompt shows the active or the given version of a prompt template.
+func ViewCmdPrompt(lib PromptLibrary, provider PromptProvider) botkit.ViewFunc {
+	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		chatID := update.Message.Chat.ID
+		args := strings.Fields(update.Message.CommandArguments())
+		if len(args) == 0 {
+			_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.PromptUsage)))
+			return err
+		}
+
+		name := args[0]
+		def, ok := lib.Default(name)
+		if !ok {
+			_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.PromptUnknown, name)))
+			return err
+		}
+
+		var (
+			p   *model.PromptTemplate
+			err error
+		)
+		if len(args) > 1 {
+			version, convErr := strconv.Atoi(args[1])
+			if convErr != nil {
+				_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.PromptInvalidVersion, args[1])))
+				return err
+			}
+			p, err = provider.PromptVersion(ctx, name, version)
+			if err == nil && p == nil {
+				_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.PromptNoVersion, name, version)))
+				return err
+			}
+		} else {
+			p, err = provider.ActivePrompt(ctx, name)
+		}
+		if err != nil {
+			return err
+		}
+
+		header := i18n.T(ctx, i18n.PromptHeaderDefault, name)
+		body := def
+		if p != nil {
+			header = i18n.T(ctx, i18n.PromptHeaderVersion, name, p.Version, p.CreatedAt.Format("2006-01-02 15:04"))
+			if p.Active {
+				header += i18n.T(ctx, i18n.PromptActive)
+			}
+			body = p.Body
+		}
+
+		_, err = api.Send(tgbotapi.NewMessage(chatID, header+"\n\n"+body))
+		return err
+	}
+}
@@ -2,11 +2,11 @@ package bot
 
 import (
 	"context"
-	"fmt"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
 )
 
 type NewsReposter interface {
@@ -16,18 +16,19 @@ type NewsReposter interface {
 func ViewCmdRepostNews(n NewsReposter) botkit.ViewFunc {
 	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
 		chatID := update.Message.Chat.ID
+		ctx = withCacheArg(ctx, update.Message.CommandArguments())
 
-		if _, err := api.Send(tgbotapi.NewMessage(chatID, "Reposting news digest to the channel…")); err != nil {
+		if _, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.RepostRunning))); err != nil {
 			return err
 		}
 
 		if err := n.Repost(ctx); err != nil {
-			reply := tgbotapi.NewMessage(chatID, fmt.Sprintf("Repost failed: %v", err))
+			reply := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.RepostFailed, err))
 			_, _ = api.Send(reply)
 			return err
 		}
 
-		_, _ = api.Send(tgbotapi.NewMessage(chatID, "News digest reposted."))
+		_, _ = api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.RepostDone)))
 		return nil
 	}
 }
@@ -0,0 +1,75 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+// SourceRestorer is satisfied by storage.SourcePostgresStorage.
+type SourceRestorer interface {
+	SourceProvider
+	DeletedSources(ctx context.Context) ([]model.Source, error)
+	Restore(ctx context.Context, id int64) (bool, error)
+}
+
+// ViewCmdRestoreSource brings back a deleted source with /restoresource <id>;
+// without an ID it lists the deleted sources.
+func ViewCmdRestoreSource(sources SourceRestorer) botkit.ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		chatID := update.Message.Chat.ID
+		arg := strings.TrimSpace(update.Message.CommandArguments())
+
+		if arg == "" {
+			deleted, err := sources.DeletedSources(ctx)
+			if err != nil {
+				return err
+			}
+
+			text := i18n.T(ctx, i18n.RestoreSourceNone)
+			if len(deleted) > 0 {
+				lines := make([]string, 0, len(deleted))
+				for _, s := range deleted {
+					lines = append(lines, fmt.Sprintf("%d · %s · %s", s.ID, s.Name, s.DeletedAt.Format("2006-01-02 15:04")))
+				}
+				text = i18n.T(ctx, i18n.RestoreSourceList, strings.Join(lines, "\n"))
+			}
+
+			_, err = bot.Send(tgbotapi.NewMessage(chatID, text))
+			return err
+		}
+
+		id, err := strconv.ParseInt(arg, 10, 64)
+		if err != nil {
+			_, err := bot.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.RestoreSourceUsage)))
+			return err
+		}
+
+		restored, err := sources.Restore(ctx, id)
+		if err != nil {
+			return err
+		}
+		if !restored {
+			_, err := bot.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.RestoreSourceNotGone, id)))
+			return err
+		}
+
+		source, err := sources.SourceByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if _, err := bot.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.RestoreSourceDone, source.Name))); err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,31 @@
+package bot
+
+import (
+	"context"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+)
+
+// ViewCmdResumeSource ends a pause set by /pausesource or the source browser.
+func ViewCmdResumeSource(sources SourcePauser) botkit.ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		chatID := update.Message.Chat.ID
+		source, err := sourceArg(ctx, bot, chatID, update.Message.CommandArguments(), sources, i18n.ResumeSourceUsage)
+		if err != nil || source == nil {
+			return err
+		}
+
+		if err := sources.SetEnabled(ctx, source.ID, true); err != nil {
+			return err
+		}
+
+		if _, err := bot.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.ResumeSourceDone, source.Name))); err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
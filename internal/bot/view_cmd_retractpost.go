@@ -0,0 +1,53 @@
+package bot
+
+import (
+	"context"
+	"log/slog"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+)
+
+type PostRetractor interface {
+	PostProvider
+	Retract(ctx context.Context, id int64) error
+}
+
+// ViewCmdRetractPost deletes a post from its channel and returns its articles
+// to the queue. Messages Telegram refuses to delete are reported so they can
+// be removed by hand; the articles are returned either way.
+func ViewCmdRetractPost(posts PostRetractor) botkit.ViewFunc {
+	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		chatID := update.Message.Chat.ID
+
+		post, err := postArg(ctx, api, update, posts, i18n.RetractUsage)
+		if err != nil || post == nil {
+			return err
+		}
+		if post.MessageID == 0 {
+			_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.RetractNoMessageID, post.ID)))
+			return err
+		}
+
+		var failed int
+		for _, messageID := range append(post.MediaMessageIDs, post.MessageID) {
+			if _, err := api.Request(tgbotapi.NewDeleteMessage(post.ChannelID, messageID)); err != nil {
+				slog.Warn("failed to delete post message", "post", post.ID, "message", messageID, "err", err)
+				failed++
+			}
+		}
+
+		if err := posts.Retract(ctx, post.ID); err != nil {
+			return err
+		}
+
+		reply := i18n.T(ctx, i18n.RetractDone, post.ID, len(post.ArticleIDs))
+		if failed > 0 {
+			reply += i18n.T(ctx, i18n.RetractUndeleted, failed)
+		}
+		_, err = api.Send(tgbotapi.NewMessage(chatID, reply))
+		return err
+	}
+}
@@ -0,0 +1,80 @@
+package bot
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+type PromptActivator interface {
+	PromptVersions(ctx context.Context, name string) ([]model.PromptTemplate, error)
+	ActivatePrompt(ctx context.Context, name string, version int) error
+}
+
+// ViewCmdRollbackPrompt activates the given version of a prompt, or the one
+// before the active version when none is given. Version 0 reverts to the
+// built-in default.
+func ViewCmdRollbackPrompt(lib PromptLibrary, activator PromptActivator) botkit.ViewFunc {
+	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		chatID := update.Message.Chat.ID
+		args := strings.Fields(update.Message.CommandArguments())
+		if len(args) == 0 {
+			_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.RollbackUsage)))
+			return err
+		}
+
+		name := args[0]
+		if _, ok := lib.Default(name); !ok {
+			_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.PromptUnknown, name)))
+			return err
+		}
+
+		versions, err := activator.PromptVersions(ctx, name)
+		if err != nil {
+			return err
+		}
+
+		var version int
+		if len(args) > 1 {
+			if version, err = strconv.Atoi(args[1]); err != nil || version < 0 {
+				_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.PromptInvalidVersion, args[1])))
+				return err
+			}
+		} else {
+			version = previousVersion(versions)
+		}
+
+		if err := activator.ActivatePrompt(ctx, name, version); err != nil {
+			_, _ = api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.RollbackFailed, err)))
+			return err
+		}
+
+		reply := i18n.T(ctx, i18n.RollbackDone, name, version)
+		if version == 0 {
+			reply = i18n.T(ctx, i18n.RollbackDefault, name)
+		}
+		_, err = api.Send(tgbotapi.NewMessage(chatID, reply))
+		return err
+	}
+}
+
+// previousVersion returns the newest version older than the active one, or 0
+// (the default template) when there is none. versions are newest first.
+func previousVersion(versions []model.PromptTemplate) int {
+	for i, v := range versions {
+		if !v.Active {
+			continue
+		}
+		if i+1 < len(versions) {
+			return versions[i+1].Version
+		}
+		return 0
+	}
+	return 0
+}
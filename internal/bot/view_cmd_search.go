@@ -0,0 +1,227 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+const (
+	searchPageSize = 5
+	// searchQueryMark starts the first line of a results message, which
+	// repeats the query so the page buttons can run it again.
+	searchQueryMark = "🔎 "
+	searchDayLayout = "2006-01-02"
+)
+
+// Filters of the /search query.
+const (
+	searchFilterSource   = "source:"
+	searchFilterCategory = "category:"
+	searchFilterFrom     = "from:"
+	searchFilterTo       = "to:"
+)
+
+// ArticleSearcher is satisfied by storage.ArticlePostgresStorage.
+type ArticleSearcher interface {
+	Search(ctx context.Context, q model.ArticleSearch) ([]model.Article, int, error)
+}
+
+// ViewCmdSearch runs a full-text search over all articles. The results are
+// paged with buttons handled by ViewCallbackSearch.
+func ViewCmdSearch(searcher ArticleSearcher, sources SourceLister) botkit.ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		chatID := update.Message.Chat.ID
+
+		query := strings.TrimSpace(update.Message.CommandArguments())
+		if query == "" {
+			_, err := bot.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.SearchUsage)))
+			return err
+		}
+
+		text, keyboard, err := searchPage(ctx, searcher, sources, query, 0)
+		if err != nil {
+			return err
+		}
+
+		reply := tgbotapi.NewMessage(chatID, text)
+		reply.DisableWebPagePreview = true
+		if len(keyboard.InlineKeyboard) > 0 {
+			reply.ReplyMarkup = keyboard
+		}
+
+		if _, err := bot.Send(reply); err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
+
+// searchInputError is a problem with the query to show to the user.
+type searchInputError struct {
+	msg string
+}
+
+func (e searchInputError) Error() string {
+	return e.msg
+}
+
+// searchPage runs query and renders the given page of results. An invalid
+// query renders as the problem with it.
+func searchPage(ctx context.Context, searcher ArticleSearcher, sources SourceLister, query string, page int) (string, tgbotapi.InlineKeyboardMarkup, error) {
+	search, err := parseSearchQuery(ctx, sources, query)
+
+	var invalid searchInputError
+	if errors.As(err, &invalid) {
+		return invalid.msg, tgbotapi.InlineKeyboardMarkup{}, nil
+	}
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
+	}
+
+	search.Limit = searchPageSize
+	search.Offset = max(page, 0) * searchPageSize
+
+	articles, total, err := searcher.Search(ctx, search)
+	if err != nil {
+		return "", tgbotapi.InlineKeyboardMarkup{}, err
+	}
+	if total == 0 {
+		return searchQueryMark + query + "\n\n" + i18n.T(ctx, i18n.SearchNoResults), tgbotapi.InlineKeyboardMarkup{}, nil
+	}
+
+	pages := (total + searchPageSize - 1) / searchPageSize
+	lines := []string{searchQueryMark + query, i18n.T(ctx, i18n.SearchHeader, total, page+1, pages)}
+	for i, a := range articles {
+		lines = append(lines, fmt.Sprintf("%d. %s\n%s · %s\n%s",
+			search.Offset+i+1, a.Title, a.SourceName, a.PublishedAt.Format(searchDayLayout), a.Link))
+	}
+
+	var nav []tgbotapi.InlineKeyboardButton
+	if page > 0 {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.PageButtonPrev), botkit.CallbackData(SearchCallbackPrefix, strconv.Itoa(page-1))))
+	}
+	if page < pages-1 {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.PageButtonNext), botkit.CallbackData(SearchCallbackPrefix, strconv.Itoa(page+1))))
+	}
+
+	var keyboard tgbotapi.InlineKeyboardMarkup
+	if len(nav) > 0 {
+		keyboard = tgbotapi.NewInlineKeyboardMarkup(nav)
+	}
+
+	return strings.Join(lines, "\n\n"), keyboard, nil
+}
+
+// parseSearchQuery takes the filters out of query; the rest is the search
+// text. Sources are matched by ID or case-insensitive name, and the to: day
+// is included.
+func parseSearchQuery(ctx context.Context, sources SourceLister, query string) (model.ArticleSearch, error) {
+	var (
+		search model.ArticleSearch
+		words  []string
+		known  []model.Source
+	)
+
+	for _, token := range splitSearchQuery(query) {
+		key, value, ok := searchFilter(token)
+		if !ok {
+			words = append(words, token)
+			continue
+		}
+
+		switch key {
+		case searchFilterSource:
+			if known == nil {
+				var err error
+				if known, err = sources.Sources(ctx); err != nil {
+					return model.ArticleSearch{}, err
+				}
+			}
+			id, ok := findSource(known, value)
+			if !ok {
+				return model.ArticleSearch{}, searchInputError{msg: i18n.T(ctx, i18n.SearchUnknownSource, value)}
+			}
+			search.SourceIDs = append(search.SourceIDs, id)
+		case searchFilterCategory:
+			search.Categories = append(search.Categories, value)
+		case searchFilterFrom, searchFilterTo:
+			day, err := time.Parse(searchDayLayout, value)
+			if err != nil {
+				return model.ArticleSearch{}, searchInputError{msg: i18n.T(ctx, i18n.SearchBadDate, value)}
+			}
+			if key == searchFilterFrom {
+				search.From = day
+			} else {
+				search.To = day.AddDate(0, 0, 1)
+			}
+		}
+	}
+
+	search.Query = strings.Join(words, " ")
+	return search, nil
+}
+
+// searchFilter splits a filter token into its key and unquoted value.
+func searchFilter(token string) (string, string, bool) {
+	for _, key := range []string{searchFilterSource, searchFilterCategory, searchFilterFrom, searchFilterTo} {
+		if value, ok := strings.CutPrefix(token, key); ok && value != "" {
+			return key, strings.Trim(value, `"`), true
+		}
+	}
+	return "", "", false
+}
+
+func findSource(sources []model.Source, value string) (int64, bool) {
+	if id, err := strconv.ParseInt(value, 10, 64); err == nil {
+		for _, s := range sources {
+			if s.ID == id {
+				return id, true
+			}
+		}
+	}
+	for _, s := range sources {
+		if strings.EqualFold(s.Name, value) {
+			return s.ID, true
+		}
+	}
+	return 0, false
+}
+
+// splitSearchQuery splits query on spaces outside double quotes, keeping the
+// quotes so phrases reach the full-text query intact.
+func splitSearchQuery(query string) []string {
+	var (
+		tokens  []string
+		current strings.Builder
+		quoted  bool
+	)
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case r == ' ' && !quoted:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
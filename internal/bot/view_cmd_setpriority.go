@@ -6,6 +6,7 @@ import (
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
 )
 
 type PrioritySetter interface {
@@ -28,7 +29,7 @@ func ViewCmdSetPriority(prioritySetter PrioritySetter) botkit.ViewFunc {
 			return err
 		}
 
-		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "Приоритет успешно обновлен")
+		msg := tgbotapi.NewMessage(update.Message.Chat.ID, i18n.T(ctx, i18n.SetPriorityDone))
 
 		if _, err := bot.Send(msg); err != nil {
 			return err
@@ -0,0 +1,64 @@
+package bot
+
+import (
+	"context"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+	"github.com/0x0BSoD/newsMaker/internal/prompt"
+)
+
+type PromptSaver interface {
+	SavePrompt(ctx context.Context, name, body string, createdBy int64) (int, error)
+}
+
+// ViewCmdSetPrompt asks for a new template body and saves it as the next,
+// active version of the named prompt.
+func ViewCmdSetPrompt(lib PromptLibrary, saver PromptSaver, b *botkit.Bot) botkit.ViewFunc {
+	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		chatID := update.Message.Chat.ID
+		name := strings.TrimSpace(update.Message.CommandArguments())
+		if name == "" {
+			_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.SetPromptUsage)))
+			return err
+		}
+		if _, ok := lib.Default(name); !ok {
+			_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.PromptUnknown, name)))
+			return err
+		}
+
+		msg := i18n.T(ctx, i18n.SetPromptAsk, name)
+		if _, err := api.Send(tgbotapi.NewMessage(chatID, msg)); err != nil {
+			return err
+		}
+
+		b.RegisterMsgHandler(chatID, savePromptHandler(saver, b, chatID, name))
+		return nil
+	}
+}
+
+func savePromptHandler(saver PromptSaver, b *botkit.Bot, chatID int64, name string) botkit.ViewFunc {
+	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		body := update.Message.Text
+
+		if err := prompt.Validate(name, body); err != nil {
+			reply := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.SetPromptInvalid, err))
+			_, _ = api.Send(reply)
+			b.RegisterMsgHandler(chatID, savePromptHandler(saver, b, chatID, name))
+			return nil
+		}
+
+		b.ClearMsgHandler(chatID)
+
+		version, err := saver.SavePrompt(ctx, name, body, update.SentFrom().ID)
+		if err != nil {
+			return err
+		}
+
+		_, err = api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.SetPromptDone, name, version)))
+		return err
+	}
+}
@@ -0,0 +1,238 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+const subscribeConversation = "subscribe"
+
+// Steps of the /subscribe conversation.
+const (
+	stepSubKeywords   = "keywords"
+	stepSubCategories = "categories"
+	stepSubSources    = "sources"
+	stepSubSchedule   = "schedule"
+	stepSubTimezone   = "timezone"
+	stepSubHour       = "hour"
+)
+
+// answerSkip is stored for a skipped list step.
+const answerSkip = "-"
+
+// SubscriptionAdder is satisfied by storage.SubscriptionPostgresStorage.
+type SubscriptionAdder interface {
+	Add(ctx context.Context, sub model.Subscription, limit int) (int64, bool, error)
+}
+
+// ViewCmdSubscribe starts the /subscribe conversation. It runs in private
+// chats only, since that is where personal digests are sent, and not for
+// users who already have limit subscriptions.
+func ViewCmdSubscribe(b *botkit.Bot, subs SubscriptionLister, limit int) botkit.ViewFunc {
+	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		chatID := update.Message.Chat.ID
+		if !update.Message.Chat.IsPrivate() {
+			_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.SubscribePrivateOnly)))
+			return err
+		}
+
+		list, err := subs.UserSubscriptions(ctx, update.SentFrom().ID)
+		if err != nil {
+			return err
+		}
+		if len(list) >= limit {
+			_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.SubscribeLimit, limit)))
+			return err
+		}
+
+		return b.StartConversation(ctx, subscribeConversation, chatID, update.SentFrom().ID, nil)
+	}
+}
+
+// SubscribeConversation asks for the keywords, categories and sources to
+// follow, at least one of them, and when to send the digest. zones and hours
+// are offered as buttons; any IANA timezone and any hour can be typed. A user
+// gets at most limit subscriptions.
+func SubscribeConversation(subs SubscriptionAdder, sources SourceLister, hours []int, zones []string, limit int) botkit.Conversation {
+	skip := func(ctx context.Context, _ botkit.Values) []botkit.Choice {
+		return []botkit.Choice{{Label: i18n.T(ctx, i18n.SubscribeSkip), Value: answerSkip}}
+	}
+
+	return botkit.Conversation{
+		Name:  subscribeConversation,
+		First: stepSubKeywords,
+		Steps: map[string]botkit.Step{
+			stepSubKeywords: {
+				Prompt:   stepPrompt(i18n.SubscribeKeywords),
+				Choices:  skip,
+				Validate: validateList,
+				Next:     nextStep(stepSubCategories),
+			},
+			stepSubCategories: {
+				Prompt:   stepPrompt(i18n.SubscribeCategories),
+				Choices:  skip,
+				Validate: validateList,
+				Next:     nextStep(stepSubSources),
+			},
+			stepSubSources: {
+				Prompt:  stepPrompt(i18n.SubscribeSources),
+				Choices: skip,
+				Validate: func(ctx context.Context, input string, values botkit.Values) (string, error) {
+					if input == answerSkip {
+						if values[stepSubKeywords] == answerSkip && values[stepSubCategories] == answerSkip {
+							return "", errors.New(i18n.T(ctx, i18n.SubscribeNothing))
+						}
+						return answerSkip, nil
+					}
+
+					known, err := sources.Sources(ctx)
+					if err != nil {
+						return "", err
+					}
+					var ids []string
+					for _, name := range splitList(input) {
+						id, ok := findSource(known, name)
+						if !ok {
+							return "", errors.New(i18n.T(ctx, i18n.SearchUnknownSource, name))
+						}
+						ids = append(ids, strconv.FormatInt(id, 10))
+					}
+					if len(ids) == 0 {
+						return "", errors.New(i18n.T(ctx, i18n.SubscribeNothing))
+					}
+					return strings.Join(ids, ","), nil
+				},
+				Next: nextStep(stepSubSchedule),
+			},
+			stepSubSchedule: {
+				Prompt: stepPrompt(i18n.SubscribeSchedule),
+				Choices: func(ctx context.Context, _ botkit.Values) []botkit.Choice {
+					return []botkit.Choice{
+						{Label: i18n.T(ctx, i18n.SubscribeDaily), Value: model.ScheduleDaily},
+						{Label: i18n.T(ctx, i18n.SubscribeWeekly), Value: model.ScheduleWeekly},
+					}
+				},
+				Validate: func(ctx context.Context, input string, _ botkit.Values) (string, error) {
+					if input != model.ScheduleDaily && input != model.ScheduleWeekly {
+						return "", errors.New(i18n.T(ctx, i18n.SubscribeBadChoice))
+					}
+					return input, nil
+				},
+				Next: nextStep(stepSubTimezone),
+			},
+			stepSubTimezone: {
+				Prompt: stepPrompt(i18n.SubscribeTimezone),
+				Choices: func(context.Context, botkit.Values) []botkit.Choice {
+					choices := make([]botkit.Choice, 0, len(zones))
+					for _, z := range zones {
+						choices = append(choices, botkit.Choice{Label: z, Value: z})
+					}
+					return choices
+				},
+				Validate: func(ctx context.Context, input string, _ botkit.Values) (string, error) {
+					// Local is the server's zone, which is what the user
+					// should not depend on.
+					loc, err := time.LoadLocation(input)
+					if err != nil || input == "" || input == "Local" {
+						return "", errors.New(i18n.T(ctx, i18n.SubscribeBadTimezone, input))
+					}
+					return loc.String(), nil
+				},
+				Next: nextStep(stepSubHour),
+			},
+			stepSubHour: {
+				Prompt: func(ctx context.Context, values botkit.Values) string {
+					return i18n.T(ctx, i18n.SubscribeHour, values[stepSubTimezone])
+				},
+				Choices: func(context.Context, botkit.Values) []botkit.Choice {
+					choices := make([]botkit.Choice, 0, len(hours))
+					for _, h := range hours {
+						choices = append(choices, botkit.Choice{Label: fmt.Sprintf("%02d:00", h), Value: strconv.Itoa(h)})
+					}
+					return choices
+				},
+				Validate: func(ctx context.Context, input string, _ botkit.Values) (string, error) {
+					hour, err := strconv.Atoi(strings.TrimSuffix(input, ":00"))
+					if err != nil || hour < 0 || hour > 23 {
+						return "", errors.New(i18n.T(ctx, i18n.SubscribeBadHour))
+					}
+					return strconv.Itoa(hour), nil
+				},
+			},
+		},
+		Done: func(ctx context.Context, api *tgbotapi.BotAPI, chatID int64, values botkit.Values) error {
+			hour, err := strconv.Atoi(values[stepSubHour])
+			if err != nil {
+				return err
+			}
+
+			sub := model.Subscription{
+				// The conversation runs in a private chat, whose ID is the
+				// user's.
+				UserID:     chatID,
+				ChatID:     chatID,
+				Keywords:   splitList(values[stepSubKeywords]),
+				Categories: splitList(values[stepSubCategories]),
+				Schedule:   values[stepSubSchedule],
+				Hour:       hour,
+				Timezone:   values[stepSubTimezone],
+				Language:   i18n.Language(ctx),
+			}
+			for _, s := range splitList(values[stepSubSources]) {
+				id, err := strconv.ParseInt(s, 10, 64)
+				if err != nil {
+					return err
+				}
+				sub.SourceIDs = append(sub.SourceIDs, id)
+			}
+
+			id, ok, err := subs.Add(ctx, sub, limit)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.SubscribeLimit, limit)))
+				return err
+			}
+
+			_, err = api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.SubscribeDone, id)))
+			return err
+		},
+	}
+}
+
+// validateList accepts a comma-separated list or the skip answer.
+func validateList(ctx context.Context, input string, _ botkit.Values) (string, error) {
+	if input == answerSkip {
+		return input, nil
+	}
+	items := splitList(input)
+	if len(items) == 0 {
+		return "", errors.New(i18n.T(ctx, i18n.ConversationEmptyInput))
+	}
+	return strings.Join(items, ","), nil
+}
+
+// splitList splits a comma-separated answer; the skip answer is empty.
+func splitList(s string) []string {
+	if s == answerSkip {
+		return nil
+	}
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -2,11 +2,11 @@ package bot
 
 import (
 	"context"
-	"fmt"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
 )
 
 type DigestRunner interface {
@@ -16,19 +16,21 @@ type DigestRunner interface {
 func ViewCmdTestDigest(d DigestRunner, testChannelID int64) botkit.ViewFunc {
 	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
 		chatID := update.Message.Chat.ID
+		ctx = withCacheArg(ctx, update.Message.CommandArguments())
+		ctx = withPromptArgs(ctx, update.Message.CommandArguments())
 
-		notice := tgbotapi.NewMessage(chatID, "Running test digest, please wait…")
+		notice := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.TestDigestRunning))
 		if _, err := api.Send(notice); err != nil {
 			return err
 		}
 
 		if err := d.RunTest(ctx, testChannelID); err != nil {
-			reply := tgbotapi.NewMessage(chatID, fmt.Sprintf("Test digest failed: %v", err))
+			reply := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.TestDigestFailed, err))
 			_, _ = api.Send(reply)
 			return err
 		}
 
-		reply := tgbotapi.NewMessage(chatID, "Test digest sent.")
+		reply := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.TestDigestDone))
 		_, _ = api.Send(reply)
 		return nil
 	}
@@ -2,11 +2,11 @@ package bot
 
 import (
 	"context"
-	"fmt"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
 	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
 )
 
 type NewsDigestRunner interface {
@@ -16,18 +16,20 @@ type NewsDigestRunner interface {
 func ViewCmdTestNews(n NewsDigestRunner, testChannelID int64) botkit.ViewFunc {
 	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
 		chatID := update.Message.Chat.ID
+		ctx = withCacheArg(ctx, update.Message.CommandArguments())
+		ctx = withPromptArgs(ctx, update.Message.CommandArguments())
 
-		if _, err := api.Send(tgbotapi.NewMessage(chatID, "Running test news digest, please wait…")); err != nil {
+		if _, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.TestNewsRunning))); err != nil {
 			return err
 		}
 
 		if err := n.SendTestDigest(ctx, testChannelID); err != nil {
-			reply := tgbotapi.NewMessage(chatID, fmt.Sprintf("Test news digest failed: %v", err))
+			reply := tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.TestNewsFailed, err))
 			_, _ = api.Send(reply)
 			return err
 		}
 
-		_, _ = api.Send(tgbotapi.NewMessage(chatID, "Test news digest sent."))
+		_, _ = api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.TestNewsDone)))
 		return nil
 	}
 }
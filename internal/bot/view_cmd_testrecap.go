@@ -0,0 +1,36 @@
+package bot
+
+import (
+	"context"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+)
+
+type RecapRunner interface {
+	SendTest(ctx context.Context, channelID int64) error
+}
+
+// ViewCmdTestRecap sends the weekly recap of the past seven days to the test
+// channel.
+func ViewCmdTestRecap(r RecapRunner, testChannelID int64) botkit.ViewFunc {
+	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		chatID := update.Message.Chat.ID
+		ctx = withCacheArg(ctx, update.Message.CommandArguments())
+		ctx = withPromptArgs(ctx, update.Message.CommandArguments())
+
+		if _, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.TestRecapRunning))); err != nil {
+			return err
+		}
+
+		if err := r.SendTest(ctx, testChannelID); err != nil {
+			_, _ = api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.TestRecapFailed, err)))
+			return err
+		}
+
+		_, _ = api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.TestRecapDone)))
+		return nil
+	}
+}
@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+)
+
+// SubscriptionDeleter is satisfied by storage.SubscriptionPostgresStorage.
+type SubscriptionDeleter interface {
+	Delete(ctx context.Context, userID, id int64) (bool, error)
+	DeleteAll(ctx context.Context, userID int64) (int, error)
+}
+
+// ViewCmdUnsubscribe removes one subscription of the sender, or all of them
+// with /unsubscribe all.
+func ViewCmdUnsubscribe(subs SubscriptionDeleter) botkit.ViewFunc {
+	return func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		var (
+			chatID = update.Message.Chat.ID
+			userID = update.SentFrom().ID
+			arg    = strings.TrimSpace(update.Message.CommandArguments())
+			text   string
+		)
+
+		if arg == "all" {
+			n, err := subs.DeleteAll(ctx, userID)
+			if err != nil {
+				return err
+			}
+			text = i18n.T(ctx, i18n.UnsubscribeAllDone, n)
+		} else if id, err := strconv.ParseInt(strings.TrimPrefix(arg, "#"), 10, 64); err != nil {
+			text = i18n.T(ctx, i18n.UnsubscribeUsage)
+		} else {
+			deleted, err := subs.Delete(ctx, userID, id)
+			if err != nil {
+				return err
+			}
+			text = i18n.T(ctx, i18n.UnsubscribeDone, id)
+			if !deleted {
+				text = i18n.T(ctx, i18n.UnsubscribeNotFound, id)
+			}
+		}
+
+		if _, err := bot.Send(tgbotapi.NewMessage(chatID, text)); err != nil {
+			return err
+		}
+
+		return nil
+	}
+}
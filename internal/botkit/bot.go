@@ -8,24 +8,73 @@ import (
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
 )
 
 type ViewFunc func(ctx context.Context, bot *tgbotapi.BotAPI, update tgbotapi.Update) error
 
+// LanguageFunc picks the reply language for an update; see i18n.
+type LanguageFunc func(ctx context.Context, update tgbotapi.Update) string
+
+const defaultUpdateTimeout = 5 * time.Minute
+
+const defaultLongUpdateTimeout = 30 * time.Minute
+
 type Bot struct {
-	api         *tgbotapi.BotAPI
-	cmdViews    map[string]ViewFunc
-	msgHandlers map[int64]ViewFunc
-	mu          sync.Mutex
+	api           *tgbotapi.BotAPI
+	cmdViews      map[string]ViewFunc
+	longCmds      map[string]bool
+	callbackViews map[string]ViewFunc
+	msgHandlers   map[int64]ViewFunc
+	updateTimeout time.Duration
+	longTimeout   time.Duration
+	language      LanguageFunc
+	mu            sync.Mutex
+	running       sync.WaitGroup
+
+	conversations     map[string]*Conversation
+	conversationStore ConversationStore
 }
 
 func New(api *tgbotapi.BotAPI) *Bot {
-	return &Bot{
-		api:         api,
-		msgHandlers: make(map[int64]ViewFunc),
+	b := &Bot{
+		api:           api,
+		longCmds:      make(map[string]bool),
+		callbackViews: make(map[string]ViewFunc),
+		msgHandlers:   make(map[int64]ViewFunc),
+		updateTimeout: defaultUpdateTimeout,
+		longTimeout:   defaultLongUpdateTimeout,
+		conversations: make(map[string]*Conversation),
+	}
+	b.callbackViews[conversationPrefix] = b.viewConversationCallback
+	return b
+}
+
+// SetUpdateTimeout sets how long a single update may be handled before its
+// context is cancelled. Updates are handled one at a time, so keep it short;
+// see RegisterLongCmdView for commands that take longer.
+func (b *Bot) SetUpdateTimeout(d time.Duration) {
+	if d > 0 {
+		b.updateTimeout = d
+	}
+}
+
+// SetLongUpdateTimeout sets how long a command registered with
+// RegisterLongCmdView may run. Commands that call the LLM need at least the
+// AI timeout.
+func (b *Bot) SetLongUpdateTimeout(d time.Duration) {
+	if d > 0 {
+		b.longTimeout = d
 	}
 }
 
+// SetLanguageFunc sets how the reply language of each update is picked.
+// Without it views reply in i18n.Default.
+func (b *Bot) SetLanguageFunc(f LanguageFunc) {
+	b.language = f
+}
+
 func (b *Bot) RegisterCmdView(cmd string, view ViewFunc) {
 	if b.cmdViews == nil {
 		b.cmdViews = make(map[string]ViewFunc)
@@ -34,6 +83,20 @@ func (b *Bot) RegisterCmdView(cmd string, view ViewFunc) {
 	b.cmdViews[cmd] = view
 }
 
+// RegisterLongCmdView registers the view of a command that runs long, such as
+// one calling the LLM. It is handled in its own goroutine, so callbacks,
+// conversations and other commands are not held up meanwhile.
+func (b *Bot) RegisterLongCmdView(cmd string, view ViewFunc) {
+	b.RegisterCmdView(cmd, view)
+	b.longCmds[cmd] = true
+}
+
+// RegisterCallbackView registers the view for inline keyboard callbacks whose
+// data starts with prefix; see CallbackData.
+func (b *Bot) RegisterCallbackView(prefix string, view ViewFunc) {
+	b.callbackViews[prefix] = view
+}
+
 // RegisterMsgHandler registers a one-shot message handler for a specific chat.
 // It will be called for the next non-command message received in that chat.
 func (b *Bot) RegisterMsgHandler(chatID int64, handler ViewFunc) {
@@ -54,19 +117,38 @@ func (b *Bot) Run(ctx context.Context) error {
 	u.Timeout = 60
 
 	updates := b.api.GetUpdatesChan(u)
+	defer b.running.Wait()
 
 	for {
 		select {
 		case update := <-updates:
-			updateCtx, updateCancel := context.WithTimeout(context.Background(), 5*time.Minute)
-			b.handleUpdate(updateCtx, update)
-			updateCancel()
+			if b.long(update) {
+				b.running.Add(1)
+				go func() {
+					defer b.running.Done()
+					b.handle(ctx, update, b.longTimeout)
+				}()
+				continue
+			}
+			b.handle(ctx, update, b.updateTimeout)
 		case <-ctx.Done():
 			return ctx.Err()
 		}
 	}
 }
 
+// long reports whether update is a command registered with
+// RegisterLongCmdView.
+func (b *Bot) long(update tgbotapi.Update) bool {
+	return update.Message != nil && update.Message.IsCommand() && b.longCmds[update.Message.Command()]
+}
+
+func (b *Bot) handle(ctx context.Context, update tgbotapi.Update, timeout time.Duration) {
+	updateCtx, updateCancel := context.WithTimeout(ctx, timeout)
+	defer updateCancel()
+	b.handleUpdate(updateCtx, update)
+}
+
 func (b *Bot) handleUpdate(ctx context.Context, update tgbotapi.Update) {
 	defer func() {
 		if p := recover(); p != nil {
@@ -78,17 +160,30 @@ func (b *Bot) handleUpdate(ctx context.Context, update tgbotapi.Update) {
 		return
 	}
 
+	if b.language != nil {
+		ctx = i18n.WithLanguage(ctx, b.language(ctx, update))
+	}
+
+	if update.CallbackQuery != nil {
+		b.handleCallback(ctx, update)
+		return
+	}
+
 	if update.Message != nil && !update.Message.IsCommand() {
 		b.mu.Lock()
 		handler, ok := b.msgHandlers[update.Message.Chat.ID]
 		b.mu.Unlock()
 
+		var err error
 		if ok {
-			if err := handler(ctx, b.api, update); err != nil {
-				slog.Error("message handler failed", "err", err)
-				if _, err := b.api.Send(tgbotapi.NewMessage(update.Message.Chat.ID, "Internal error")); err != nil {
-					slog.Error("failed to send error reply", "err", err)
-				}
+			err = handler(ctx, b.api, update)
+		} else {
+			_, err = b.conversationInput(ctx, update)
+		}
+		if err != nil {
+			slog.Error("message handler failed", "err", err)
+			if _, err := b.api.Send(tgbotapi.NewMessage(update.Message.Chat.ID, i18n.T(ctx, i18n.InternalError))); err != nil {
+				slog.Error("failed to send error reply", "err", err)
 			}
 		}
 		return
@@ -98,10 +193,20 @@ func (b *Bot) handleUpdate(ctx context.Context, update tgbotapi.Update) {
 		return
 	}
 
+	cmd := update.Message.Command()
+
+	if cmd == CmdCancel || cmd == CmdBack {
+		if err := b.conversationCommand(ctx, update.Message.Chat.ID, update.SentFrom().ID, cmd); err != nil {
+			slog.Error("conversation command failed", "cmd", cmd, "err", err)
+		}
+		return
+	}
+
 	// A new command clears any pending conversation state for this chat.
 	b.ClearMsgHandler(update.Message.Chat.ID)
-
-	cmd := update.Message.Command()
+	if err := b.endConversation(ctx, update.Message.Chat.ID); err != nil {
+		slog.Error("failed to end conversation", "err", err)
+	}
 
 	cmdView, ok := b.cmdViews[cmd]
 	if !ok {
@@ -111,8 +216,31 @@ func (b *Bot) handleUpdate(ctx context.Context, update tgbotapi.Update) {
 	if err := cmdView(ctx, b.api, update); err != nil {
 		slog.Error("command view failed", "cmd", cmd, "err", err)
 
-		if _, err := b.api.Send(tgbotapi.NewMessage(update.Message.Chat.ID, "Internal error")); err != nil {
+		if _, err := b.api.Send(tgbotapi.NewMessage(update.Message.Chat.ID, i18n.T(ctx, i18n.InternalError))); err != nil {
 			slog.Error("failed to send error reply", "err", err)
 		}
 	}
 }
+
+// handleCallback dispatches a callback query by its data prefix. Views answer
+// the query themselves; on error the user gets a generic alert.
+func (b *Bot) handleCallback(ctx context.Context, update tgbotapi.Update) {
+	query := update.CallbackQuery
+	prefix, _ := ParseCallbackData(query.Data)
+
+	view, ok := b.callbackViews[prefix]
+	if !ok {
+		slog.Debug("unknown callback", "data", query.Data)
+		if _, err := b.api.Request(tgbotapi.NewCallback(query.ID, "")); err != nil {
+			slog.Error("failed to answer callback", "err", err)
+		}
+		return
+	}
+
+	if err := view(ctx, b.api, update); err != nil {
+		slog.Error("callback view failed", "prefix", prefix, "err", err)
+		if _, err := b.api.Request(tgbotapi.NewCallbackWithAlert(query.ID, i18n.T(ctx, i18n.InternalError))); err != nil {
+			slog.Error("failed to answer callback", "err", err)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package botkit
+
+import (
+	"context"
+	"testing"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBot_Long(t *testing.T) {
+	view := func(context.Context, *tgbotapi.BotAPI, tgbotapi.Update) error { return nil }
+	b := New(nil)
+	b.RegisterCmdView("list", view)
+	b.RegisterLongCmdView("testnews", view)
+
+	command := func(text string) tgbotapi.Update {
+		return tgbotapi.Update{Message: &tgbotapi.Message{
+			Text:     text,
+			Entities: []tgbotapi.MessageEntity{{Type: "bot_command", Offset: 0, Length: len(text)}},
+		}}
+	}
+
+	t.Run("should run long commands in the background", func(t *testing.T) {
+		assert.True(t, b.long(command("/testnews")))
+	})
+
+	t.Run("should handle other updates in turn", func(t *testing.T) {
+		assert.False(t, b.long(command("/list")))
+		assert.False(t, b.long(tgbotapi.Update{Message: &tgbotapi.Message{Text: "testnews"}}))
+		assert.False(t, b.long(tgbotapi.Update{CallbackQuery: &tgbotapi.CallbackQuery{Data: "testnews"}}))
+	})
+}
@@ -0,0 +1,18 @@
+package botkit
+
+import "strings"
+
+// MaxCallbackData is Telegram's limit on callback data, in bytes.
+const MaxCallbackData = 64
+
+// CallbackData joins a view prefix and its arguments into callback data,
+// e.g. "fb:up:42". Arguments must not contain ':'.
+func CallbackData(prefix string, args ...string) string {
+	return strings.Join(append([]string{prefix}, args...), ":")
+}
+
+// ParseCallbackData splits callback data made by CallbackData.
+func ParseCallbackData(data string) (string, []string) {
+	parts := strings.Split(data, ":")
+	return parts[0], parts[1:]
+}
@@ -0,0 +1,382 @@
+package botkit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+// Commands the bot handles itself while a conversation is in progress.
+const (
+	CmdCancel = "cancel"
+	CmdBack   = "back"
+)
+
+const (
+	// conversationPrefix is the callback prefix of conversation buttons.
+	conversationPrefix = "cv"
+	convArgChoice      = "c"
+
+	defaultConversationTimeout = 15 * time.Minute
+)
+
+var errConversationExpired = errors.New("conversation expired")
+
+// Values are the answers of a conversation, keyed by step name.
+type Values map[string]string
+
+// Choice is an inline keyboard button offered by a step. Value is what the
+// step receives as input and must fit callback data.
+type Choice struct {
+	Label string
+	Value string
+}
+
+// Step is one question of a conversation.
+type Step struct {
+	// Prompt is the message asking for the input.
+	Prompt func(ctx context.Context, values Values) string
+	// Choices, if set, are offered as buttons; typed input is accepted too.
+	Choices func(ctx context.Context, values Values) []Choice
+	// Validate checks the trimmed input and returns the value to store under
+	// the step name. Its error is shown to the user, who is asked again.
+	Validate func(ctx context.Context, input string, values Values) (string, error)
+	// Next returns the name of the following step, or "" when the answers
+	// are complete.
+	Next func(values Values) string
+}
+
+// Conversation is a multi-step dialog with one user in a chat. Its state is
+// kept in a ConversationStore, so it survives restarts. The user can go back
+// a step with /back, leave with /cancel, and a dialog idle for Timeout is
+// dropped.
+type Conversation struct {
+	Name  string
+	First string
+	Steps map[string]Step
+	// Timeout defaults to 15 minutes.
+	Timeout time.Duration
+	// Done runs with the answers once the last step is complete.
+	Done func(ctx context.Context, api *tgbotapi.BotAPI, chatID int64, values Values) error
+}
+
+// ConversationStore is satisfied by storage.ConversationPostgresStorage.
+type ConversationStore interface {
+	Conversation(ctx context.Context, chatID int64) (*model.Conversation, error)
+	SaveConversation(ctx context.Context, c model.Conversation) error
+	DeleteConversation(ctx context.Context, chatID int64) error
+}
+
+// invalidInputError carries a validation error to show to the user.
+type invalidInputError struct {
+	err error
+}
+
+func (e invalidInputError) Error() string {
+	return e.err.Error()
+}
+
+// answer stores input for the current step and moves state to the next one.
+// It reports whether the answers are complete. On invalid input state is
+// left as is.
+func (c *Conversation) answer(ctx context.Context, state *model.Conversation, input string) (bool, error) {
+	step, ok := c.Steps[state.Step]
+	if !ok {
+		return false, fmt.Errorf("conversation %s: unknown step %q", c.Name, state.Step)
+	}
+
+	value := strings.TrimSpace(input)
+	if value == "" {
+		return false, invalidInputError{err: errors.New(i18n.T(ctx, i18n.ConversationEmptyInput))}
+	}
+	if step.Validate != nil {
+		v, err := step.Validate(ctx, value, Values(state.Values))
+		if err != nil {
+			return false, invalidInputError{err: err}
+		}
+		value = v
+	}
+
+	if state.Values == nil {
+		state.Values = make(map[string]string)
+	}
+	state.Values[state.Step] = value
+
+	var next string
+	if step.Next != nil {
+		next = step.Next(Values(state.Values))
+	}
+	if next == "" {
+		return true, nil
+	}
+	if _, ok := c.Steps[next]; !ok {
+		return false, fmt.Errorf("conversation %s: unknown step %q", c.Name, next)
+	}
+	state.History = append(state.History, state.Step)
+	state.Step = next
+	return false, nil
+}
+
+// back returns state to the previous step, forgetting its answer. It reports
+// false on the first step.
+func (c *Conversation) back(state *model.Conversation) bool {
+	if len(state.History) == 0 {
+		return false
+	}
+	state.Step = state.History[len(state.History)-1]
+	state.History = state.History[:len(state.History)-1]
+	delete(state.Values, state.Step)
+	return true
+}
+
+func (c *Conversation) expired(state *model.Conversation, now time.Time) bool {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultConversationTimeout
+	}
+	return now.Sub(state.UpdatedAt) > timeout
+}
+
+// SetConversationStore sets where conversation state is kept. Conversations
+// need it.
+func (b *Bot) SetConversationStore(store ConversationStore) {
+	b.conversationStore = store
+}
+
+func (b *Bot) RegisterConversation(c Conversation) {
+	b.conversations[c.Name] = &c
+}
+
+// ConversationView returns a view starting the named conversation with the
+// sender, replacing any dialog in progress in the chat.
+func (b *Bot) ConversationView(name string) ViewFunc {
+	return func(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
+		return b.StartConversation(ctx, name, update.Message.Chat.ID, update.SentFrom().ID, nil)
+	}
+}
+
+// StartConversation starts the named conversation with userID in chatID,
+// replacing any dialog in progress there. values are known up front, e.g.
+// the ID of the record a dialog edits.
+func (b *Bot) StartConversation(ctx context.Context, name string, chatID, userID int64, values Values) error {
+	c, ok := b.conversations[name]
+	if !ok {
+		return fmt.Errorf("unknown conversation %q", name)
+	}
+	if b.conversationStore == nil {
+		return errors.New("no conversation store")
+	}
+
+	state := &model.Conversation{
+		ChatID: chatID,
+		UserID: userID,
+		Name:   name,
+		Step:   c.First,
+		Values: make(map[string]string, len(values)),
+	}
+	for k, v := range values {
+		state.Values[k] = v
+	}
+	return b.promptStep(ctx, c, state)
+}
+
+// promptStep asks for the current step of state and saves it.
+func (b *Bot) promptStep(ctx context.Context, c *Conversation, state *model.Conversation) error {
+	step, ok := c.Steps[state.Step]
+	if !ok {
+		return fmt.Errorf("conversation %s: unknown step %q", c.Name, state.Step)
+	}
+
+	var choices []Choice
+	if step.Choices != nil {
+		choices = step.Choices(ctx, Values(state.Values))
+	}
+
+	msg := tgbotapi.NewMessage(state.ChatID, step.Prompt(ctx, Values(state.Values)))
+	msg.ReplyMarkup = conversationKeyboard(ctx, choices, len(state.History) > 0)
+	sent, err := b.api.Send(msg)
+	if err != nil {
+		return err
+	}
+
+	state.MessageID = sent.MessageID
+	return b.conversationStore.SaveConversation(ctx, *state)
+}
+
+// conversationKeyboard offers the choices of a step and the back and cancel
+// buttons.
+func conversationKeyboard(ctx context.Context, choices []Choice, back bool) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if len(choices) > 0 {
+		row := make([]tgbotapi.InlineKeyboardButton, 0, len(choices))
+		for _, ch := range choices {
+			row = append(row, tgbotapi.NewInlineKeyboardButtonData(ch.Label, CallbackData(conversationPrefix, convArgChoice, ch.Value)))
+		}
+		rows = append(rows, row)
+	}
+
+	var nav []tgbotapi.InlineKeyboardButton
+	if back {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ConversationBackButton), CallbackData(conversationPrefix, CmdBack)))
+	}
+	nav = append(nav, tgbotapi.NewInlineKeyboardButtonData(i18n.T(ctx, i18n.ConversationCancelButton), CallbackData(conversationPrefix, CmdCancel)))
+	return tgbotapi.NewInlineKeyboardMarkup(append(rows, nav)...)
+}
+
+// activeConversation loads the dialog of userID in chatID, or nil when there
+// is none. A dialog past its timeout is dropped and errConversationExpired
+// returned.
+func (b *Bot) activeConversation(ctx context.Context, chatID, userID int64) (*model.Conversation, *Conversation, error) {
+	if b.conversationStore == nil {
+		return nil, nil, nil
+	}
+
+	state, err := b.conversationStore.Conversation(ctx, chatID)
+	if err != nil || state == nil || state.UserID != userID {
+		return nil, nil, err
+	}
+
+	c, ok := b.conversations[state.Name]
+	if !ok || c.expired(state, time.Now()) {
+		if err := b.conversationStore.DeleteConversation(ctx, chatID); err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			return nil, nil, nil
+		}
+		return nil, nil, errConversationExpired
+	}
+	return state, c, nil
+}
+
+// conversationInput feeds a message to the dialog of its sender. It reports
+// whether there was one.
+func (b *Bot) conversationInput(ctx context.Context, update tgbotapi.Update) (bool, error) {
+	chatID := update.Message.Chat.ID
+	state, c, err := b.activeConversation(ctx, chatID, update.SentFrom().ID)
+	if errors.Is(err, errConversationExpired) {
+		_, err := b.api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.ConversationExpired)))
+		return true, err
+	}
+	if err != nil || state == nil {
+		return false, err
+	}
+	return true, b.answerStep(ctx, c, state, update.Message.Text)
+}
+
+func (b *Bot) answerStep(ctx context.Context, c *Conversation, state *model.Conversation, input string) error {
+	done, err := c.answer(ctx, state, input)
+
+	var invalid invalidInputError
+	if errors.As(err, &invalid) {
+		if _, err := b.api.Send(tgbotapi.NewMessage(state.ChatID, invalid.Error())); err != nil {
+			return err
+		}
+		// Saving keeps the dialog from timing out while the user retries.
+		return b.conversationStore.SaveConversation(ctx, *state)
+	}
+	if err != nil {
+		_ = b.conversationStore.DeleteConversation(ctx, state.ChatID)
+		return err
+	}
+
+	if !done {
+		return b.promptStep(ctx, c, state)
+	}
+	if err := b.conversationStore.DeleteConversation(ctx, state.ChatID); err != nil {
+		return err
+	}
+	return c.Done(ctx, b.api, state.ChatID, Values(state.Values))
+}
+
+// conversationCommand handles /cancel and /back from userID in chatID.
+// /cancel also drops a pending message handler.
+func (b *Bot) conversationCommand(ctx context.Context, chatID, userID int64, cmd string) error {
+	b.mu.Lock()
+	_, pending := b.msgHandlers[chatID]
+	b.mu.Unlock()
+	b.ClearMsgHandler(chatID)
+
+	state, c, err := b.activeConversation(ctx, chatID, userID)
+	if err != nil && !errors.Is(err, errConversationExpired) {
+		return err
+	}
+
+	switch {
+	case cmd == CmdBack && state != nil:
+		return b.stepBack(ctx, c, state)
+	case cmd == CmdCancel && (state != nil || pending):
+		return b.cancelConversation(ctx, chatID)
+	default:
+		_, err := b.api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.ConversationNone)))
+		return err
+	}
+}
+
+func (b *Bot) stepBack(ctx context.Context, c *Conversation, state *model.Conversation) error {
+	if !c.back(state) {
+		_, err := b.api.Send(tgbotapi.NewMessage(state.ChatID, i18n.T(ctx, i18n.ConversationFirstStep)))
+		return err
+	}
+	return b.promptStep(ctx, c, state)
+}
+
+func (b *Bot) cancelConversation(ctx context.Context, chatID int64) error {
+	if b.conversationStore != nil {
+		if err := b.conversationStore.DeleteConversation(ctx, chatID); err != nil {
+			return err
+		}
+	}
+	_, err := b.api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.ConversationCancelled)))
+	return err
+}
+
+// endConversation drops the dialog in progress in chatID, if any, when
+// another command is sent.
+func (b *Bot) endConversation(ctx context.Context, chatID int64) error {
+	if b.conversationStore == nil {
+		return nil
+	}
+	return b.conversationStore.DeleteConversation(ctx, chatID)
+}
+
+// viewConversationCallback handles the buttons of conversation prompts.
+// Buttons of older prompts are ignored.
+func (b *Bot) viewConversationCallback(ctx context.Context, api *tgbotapi.BotAPI, update tgbotapi.Update) error {
+	query := update.CallbackQuery
+	if _, err := api.Request(tgbotapi.NewCallback(query.ID, "")); err != nil {
+		return err
+	}
+	if query.Message == nil || query.From == nil {
+		return nil
+	}
+
+	chatID := query.Message.Chat.ID
+	state, c, err := b.activeConversation(ctx, chatID, query.From.ID)
+	if errors.Is(err, errConversationExpired) {
+		_, err := api.Send(tgbotapi.NewMessage(chatID, i18n.T(ctx, i18n.ConversationExpired)))
+		return err
+	}
+	if err != nil || state == nil || state.MessageID != query.Message.MessageID {
+		return err
+	}
+
+	_, args := ParseCallbackData(query.Data)
+	switch {
+	case len(args) == 2 && args[0] == convArgChoice:
+		return b.answerStep(ctx, c, state, args[1])
+	case len(args) == 1 && args[0] == CmdBack:
+		return b.stepBack(ctx, c, state)
+	case len(args) == 1 && args[0] == CmdCancel:
+		return b.cancelConversation(ctx, chatID)
+	default:
+		return fmt.Errorf("unknown conversation callback %q", query.Data)
+	}
+}
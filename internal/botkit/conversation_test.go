@@ -0,0 +1,96 @@
+package botkit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+func testConversation() *Conversation {
+	return &Conversation{
+		Name:  "test",
+		First: "kind",
+		Steps: map[string]Step{
+			"kind": {
+				Validate: func(_ context.Context, input string, _ Values) (string, error) {
+					if input != "a" && input != "b" {
+						return "", errors.New("a or b")
+					}
+					return input, nil
+				},
+				Next: func(v Values) string {
+					if v["kind"] == "b" {
+						return "extra"
+					}
+					return "name"
+				},
+			},
+			"extra": {Next: func(Values) string { return "name" }},
+			"name":  {},
+		},
+	}
+}
+
+func TestConversation(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should follow the branch of the answers", func(t *testing.T) {
+		c := testConversation()
+		state := &model.Conversation{Step: c.First}
+
+		done, err := c.answer(ctx, state, " b ")
+		require.NoError(t, err)
+		assert.False(t, done)
+		assert.Equal(t, "extra", state.Step)
+
+		_, err = c.answer(ctx, state, "more")
+		require.NoError(t, err)
+		done, err = c.answer(ctx, state, "Go Blog")
+		require.NoError(t, err)
+		assert.True(t, done)
+		assert.Equal(t, map[string]string{"kind": "b", "extra": "more", "name": "Go Blog"}, state.Values)
+	})
+
+	t.Run("should keep the step on invalid input", func(t *testing.T) {
+		c := testConversation()
+		state := &model.Conversation{Step: c.First}
+
+		_, err := c.answer(ctx, state, "c")
+		var invalid invalidInputError
+		require.ErrorAs(t, err, &invalid)
+		assert.Equal(t, "a or b", invalid.Error())
+		assert.Equal(t, "kind", state.Step)
+
+		_, err = c.answer(ctx, state, "  ")
+		assert.ErrorAs(t, err, &invalid)
+	})
+
+	t.Run("should go back a step", func(t *testing.T) {
+		c := testConversation()
+		state := &model.Conversation{Step: c.First}
+		assert.False(t, c.back(state))
+
+		_, err := c.answer(ctx, state, "a")
+		require.NoError(t, err)
+		require.True(t, c.back(state))
+		assert.Equal(t, "kind", state.Step)
+		assert.Empty(t, state.Values)
+		assert.Empty(t, state.History)
+	})
+
+	t.Run("should expire after the timeout", func(t *testing.T) {
+		c := testConversation()
+		now := time.Now()
+		assert.False(t, c.expired(&model.Conversation{UpdatedAt: now.Add(-time.Minute)}, now))
+		assert.True(t, c.expired(&model.Conversation{UpdatedAt: now.Add(-time.Hour)}, now))
+
+		c.Timeout = 2 * time.Hour
+		assert.False(t, c.expired(&model.Conversation{UpdatedAt: now.Add(-time.Hour)}, now))
+	})
+}
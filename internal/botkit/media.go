@@ -0,0 +1,125 @@
+package botkit
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Telegram limits of photos sent by URL.
+const (
+	MaxPhotoSize  = 5 << 20
+	MaxCaptionLen = 1024
+	MaxMediaGroup = 10
+)
+
+var photoClient = &http.Client{Timeout: 10 * time.Second}
+
+// Photo is an image URL with an HTML caption.
+type Photo struct {
+	URL     string
+	Caption string
+}
+
+// CaptionFits reports whether text can be sent as a caption. It counts the
+// HTML markup too, so it errs on the side of sending text.
+func CaptionFits(text string) bool {
+	return utf8.RuneCountInString(text) <= MaxCaptionLen
+}
+
+// CheckPhoto asks the image server whether url is an image Telegram can
+// fetch: it must answer, be an image if it says what it is, and be at most
+// MaxPhotoSize bytes if it says how large it is.
+func CheckPhoto(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; newsMaker-bot/1.0)")
+
+	resp, err := photoClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusMethodNotAllowed:
+		// Some CDNs only serve GET; let Telegram try.
+		return nil
+	case resp.StatusCode >= http.StatusBadRequest:
+		return fmt.Errorf("image %s returned status %d", url, resp.StatusCode)
+	}
+
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		mediaType, _, err := mime.ParseMediaType(ct)
+		if err != nil || (mediaType != "application/octet-stream" && !strings.HasPrefix(mediaType, "image/")) {
+			return fmt.Errorf("image %s has content type %q", url, ct)
+		}
+	}
+	if resp.ContentLength > MaxPhotoSize {
+		return fmt.Errorf("image %s is %d bytes, over the %d byte limit", url, resp.ContentLength, MaxPhotoSize)
+	}
+	return nil
+}
+
+// SendPhoto sends a photo by URL with an HTML caption. keyboard may be nil.
+func SendPhoto(ctx context.Context, s Sender, chatID int64, photo Photo, keyboard *tgbotapi.InlineKeyboardMarkup) (tgbotapi.Message, error) {
+	msg := tgbotapi.NewPhoto(chatID, tgbotapi.FileURL(photo.URL))
+	msg.Caption = photo.Caption
+	msg.ParseMode = tgbotapi.ModeHTML
+	if keyboard != nil {
+		msg.ReplyMarkup = keyboard
+	}
+	return s.Send(ctx, msg)
+}
+
+// SendMediaGroup sends photos as one album; Telegram needs two to ten of
+// them, so a single photo is sent on its own and extra photos are dropped.
+func SendMediaGroup(ctx context.Context, s Sender, chatID int64, photos []Photo) ([]tgbotapi.Message, error) {
+	switch {
+	case len(photos) == 0:
+		return nil, nil
+	case len(photos) == 1:
+		msg, err := SendPhoto(ctx, s, chatID, photos[0], nil)
+		if err != nil {
+			return nil, err
+		}
+		return []tgbotapi.Message{msg}, nil
+	case len(photos) > MaxMediaGroup:
+		photos = photos[:MaxMediaGroup]
+	}
+
+	files := make([]interface{}, len(photos))
+	for i, p := range photos {
+		media := tgbotapi.NewInputMediaPhoto(tgbotapi.FileURL(p.URL))
+		media.Caption = p.Caption
+		media.ParseMode = tgbotapi.ModeHTML
+		files[i] = media
+	}
+	return s.SendMediaGroup(ctx, tgbotapi.NewMediaGroup(chatID, files))
+}
+
+// SendPhotoOrHTML sends text as the caption of imageURL when there is an
+// image, the text fits a caption and the image looks usable. Otherwise, or
+// when Telegram rejects the photo, it sends text as a message with preview.
+func SendPhotoOrHTML(ctx context.Context, s Sender, chatID int64, imageURL, text string, preview LinkPreview, keyboard *tgbotapi.InlineKeyboardMarkup) (tgbotapi.Message, error) {
+	if imageURL != "" && CaptionFits(text) {
+		err := CheckPhoto(ctx, imageURL)
+		if err == nil {
+			var msg tgbotapi.Message
+			if msg, err = SendPhoto(ctx, s, chatID, Photo{URL: imageURL, Caption: text}, keyboard); err == nil {
+				return msg, nil
+			}
+		}
+		slog.Warn("sending photo failed, sending text", "image", imageURL, "err", err)
+	}
+	return SendHTML(ctx, s, chatID, text, preview, keyboard)
+}
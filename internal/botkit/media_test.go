@@ -0,0 +1,38 @@
+package botkit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPhoto(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok.jpg":
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Header().Set("Content-Length", "1000")
+		case "/huge.jpg":
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Header().Set("Content-Length", strconv.Itoa(MaxPhotoSize+1))
+		case "/page.html":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		case "/get-only.jpg":
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	assert.NoError(t, CheckPhoto(ctx, server.URL+"/ok.jpg"))
+	assert.NoError(t, CheckPhoto(ctx, server.URL+"/get-only.jpg"))
+	assert.ErrorContains(t, CheckPhoto(ctx, server.URL+"/huge.jpg"), "limit")
+	assert.ErrorContains(t, CheckPhoto(ctx, server.URL+"/page.html"), "content type")
+	assert.ErrorContains(t, CheckPhoto(ctx, server.URL+"/missing.jpg"), "status 404")
+}
@@ -0,0 +1,47 @@
+package botkit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// LinkPreview mirrors the Bot API LinkPreviewOptions, which the tgbotapi
+// version in use predates.
+type LinkPreview struct {
+	Disabled         bool   `json:"is_disabled,omitempty"`
+	URL              string `json:"url,omitempty"`
+	PreferSmallMedia bool   `json:"prefer_small_media,omitempty"`
+	PreferLargeMedia bool   `json:"prefer_large_media,omitempty"`
+	ShowAboveText    bool   `json:"show_above_text,omitempty"`
+}
+
+// SendHTML sends an HTML message with the given link preview options through
+// the raw sendMessage method. keyboard may be nil.
+func SendHTML(ctx context.Context, s Sender, chatID int64, text string, preview LinkPreview, keyboard *tgbotapi.InlineKeyboardMarkup) (tgbotapi.Message, error) {
+	params := tgbotapi.Params{}
+	params.AddNonZero64("chat_id", chatID)
+	params["text"] = text
+	params["parse_mode"] = tgbotapi.ModeHTML
+	if err := params.AddInterface("link_preview_options", preview); err != nil {
+		return tgbotapi.Message{}, err
+	}
+	if keyboard != nil {
+		if err := params.AddInterface("reply_markup", keyboard); err != nil {
+			return tgbotapi.Message{}, err
+		}
+	}
+
+	resp, err := s.MakeRequest(ctx, "sendMessage", params)
+	if err != nil {
+		return tgbotapi.Message{}, err
+	}
+
+	var msg tgbotapi.Message
+	if err := json.Unmarshal(resp.Result, &msg); err != nil {
+		return tgbotapi.Message{}, fmt.Errorf("decode sendMessage result: %w", err)
+	}
+	return msg, nil
+}
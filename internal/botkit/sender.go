@@ -0,0 +1,15 @@
+package botkit
+
+import (
+	"context"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Sender sends to channels on behalf of a context, so it can hold a post
+// back until the channel may be posted to; see gate.Sender.
+type Sender interface {
+	Send(ctx context.Context, c tgbotapi.Chattable) (tgbotapi.Message, error)
+	SendMediaGroup(ctx context.Context, config tgbotapi.MediaGroupConfig) ([]tgbotapi.Message, error)
+	MakeRequest(ctx context.Context, endpoint string, params tgbotapi.Params) (*tgbotapi.APIResponse, error)
+}
@@ -0,0 +1,365 @@
+// Package breaking posts breaking news immediately instead of waiting for
+// the next scheduled digest.
+package breaking
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/samber/lo"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/botkit/markup"
+	"github.com/0x0BSoD/newsMaker/internal/feedback"
+	"github.com/0x0BSoD/newsMaker/internal/gate"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+	"github.com/0x0BSoD/newsMaker/internal/prompt"
+	"github.com/0x0BSoD/newsMaker/internal/ranking"
+	"github.com/0x0BSoD/newsMaker/internal/reporter"
+	"github.com/0x0BSoD/newsMaker/internal/summary"
+)
+
+const (
+	// maxCandidates bounds the fresh articles checked per run.
+	maxCandidates = 200
+	// maxSummaryTokens caps the generated summary; it is a sentence or two.
+	maxSummaryTokens = 200
+)
+
+type ArticleProvider interface {
+	AllNotPosted(ctx context.Context, since time.Time, limit uint64) ([]model.Article, error)
+	Claim(ctx context.Context, ids []int64) ([]int64, error)
+	Unclaim(ctx context.Context, ids []int64) error
+}
+
+// PostStorage is satisfied by storage.PostPostgresStorage.
+type PostStorage interface {
+	Store(ctx context.Context, post model.Post) (int64, error)
+	CountSince(ctx context.Context, kind string, channelID int64, since time.Time) (int, error)
+}
+
+// PostGate defers posts during quiet hours and over the daily cap; see
+// gate.Gate. It is checked before a post is generated, so that none goes
+// stale while held back; the sender enforces it again on the send itself.
+type PostGate interface {
+	Allowed(ctx context.Context, channelID int64) (bool, error)
+}
+
+// PromptRenderer renders named prompt templates; see prompt.Library.
+type PromptRenderer interface {
+	Render(ctx context.Context, name string, data prompt.Data) (string, error)
+}
+
+// Watcher checks fresh articles against the breaking rules and posts matches
+// right away. Posted articles are marked as posted so the next digest does
+// not repeat them.
+type Watcher struct {
+	articles   ArticleProvider
+	posts      PostStorage
+	summarizer summary.Summarizer
+	prompts    PromptRenderer
+	bot        botkit.Sender
+	reporter   *reporter.Reporter
+	channelID  int64
+	rules      Rules
+	interval   time.Duration
+	maxPerHour int
+	quietStart int
+	quietEnd   int
+	location   *time.Location
+	language   string
+	images     bool
+	feedback   bool
+	gate       PostGate
+	now        func() time.Time
+
+	// recent are the articles posted within the rules window, so later
+	// coverage of a story that was already posted is not posted again.
+	recent []posted
+}
+
+type posted struct {
+	article model.Article
+	at      time.Time
+}
+
+func New(
+	articleProvider ArticleProvider,
+	postStorage PostStorage,
+	summarizer summary.Summarizer,
+	prompts PromptRenderer,
+	bot botkit.Sender,
+	rep *reporter.Reporter,
+	channelID int64,
+	rules Rules,
+	interval time.Duration,
+	maxPerHour int,
+	quietStart int,
+	quietEnd int,
+	language string,
+	images bool,
+	feedback bool,
+	gate PostGate,
+	location *time.Location,
+) *Watcher {
+	return &Watcher{
+		articles:   articleProvider,
+		posts:      postStorage,
+		summarizer: summarizer,
+		prompts:    prompts,
+		bot:        bot,
+		reporter:   rep,
+		channelID:  channelID,
+		rules:      rules,
+		interval:   interval,
+		maxPerHour: maxPerHour,
+		quietStart: quietStart,
+		quietEnd:   quietEnd,
+		location:   location,
+		language:   language,
+		images:     images,
+		feedback:   feedback,
+		gate:       gate,
+		now:        time.Now,
+	}
+}
+
+func (w *Watcher) Start(ctx context.Context) error {
+	slog.Info("breaking news watcher started", "interval", w.interval)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := w.Check(ctx); err != nil {
+				slog.Error("breaking news check failed", "err", err)
+			}
+		}
+	}
+}
+
+// Check posts the breaking stories among the articles published within the
+// rules window, unless it is quiet hours in the channel's timezone or the
+// hourly limit is reached. Articles that are not posted stay queued for the
+// digest.
+func (w *Watcher) Check(ctx context.Context) error {
+	now := w.now()
+	if gate.InQuietHours(now.In(w.location).Hour(), w.quietStart, w.quietEnd) {
+		return nil
+	}
+
+	sent, err := w.posts.CountSince(ctx, model.PostKindBreaking, w.channelID, now.Add(-time.Hour))
+	if err != nil {
+		return fmt.Errorf("count breaking posts: %w", err)
+	}
+	budget := w.maxPerHour - sent
+	if budget <= 0 {
+		return nil
+	}
+
+	candidates, err := w.articles.AllNotPosted(ctx, now.Add(-w.rules.Window), maxCandidates)
+	if err != nil {
+		return fmt.Errorf("fetch articles: %w", err)
+	}
+
+	w.forget(now)
+	for _, m := range w.rules.Match(candidates) {
+		if budget == 0 {
+			slog.Info("breaking news rate limit reached, leaving the rest for the digest", "maxPerHour", w.maxPerHour)
+			break
+		}
+		if w.seen(m.Article) {
+			continue
+		}
+		if allowed, err := w.gate.Allowed(ctx, w.channelID); err != nil {
+			return err
+		} else if !allowed {
+			slog.Info("channel posting limits reached, leaving breaking news for the digest", "channel", w.channelID)
+			break
+		}
+
+		ok, err := w.post(ctx, m)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		w.recent = append(w.recent, posted{article: m.Article, at: now})
+		budget--
+	}
+
+	return nil
+}
+
+// post claims and posts the lead article of m and the coverage linked with
+// it. It reports false, posting nothing, when another channel has claimed the
+// lead article meanwhile.
+func (w *Watcher) post(ctx context.Context, m Match) (ok bool, err error) {
+	// Only the articles linked in the post are posted; other coverage stays
+	// queued and is dropped from digests as a recently posted story.
+	articles := append([]model.Article{m.Article}, alsoCovered(m)...)
+	claimed, err := w.articles.Claim(ctx, lo.Map(articles, func(a model.Article, _ int) int64 { return a.ID }))
+	if err != nil {
+		return false, fmt.Errorf("claim articles: %w", err)
+	}
+	if !lo.Contains(claimed, m.Article.ID) {
+		slog.Info("breaking news already claimed by another channel", "article", m.Article.ID)
+		w.unclaim(ctx, claimed)
+		return false, nil
+	}
+	defer func() {
+		if err != nil {
+			w.unclaim(ctx, claimed)
+		}
+	}()
+	articles = lo.Filter(articles, func(a model.Article, _ int) bool { return lo.Contains(claimed, a.ID) })
+
+	slog.Info("posting breaking news", "rule", m.Rule, "article", m.Article.ID, "related", len(m.Related))
+
+	description, usage, err := w.summarize(ctx, m)
+	if err != nil {
+		slog.Warn("breaking news summary failed, posting the headline only", "err", err)
+		w.reporter.Notify(fmt.Sprintf("Breaking news summary error: %v", err))
+	}
+
+	var imageURL string
+	if w.images {
+		imageURL = m.Article.ImageURL
+	}
+	var keyboard *tgbotapi.InlineKeyboardMarkup
+	if w.feedback {
+		kb := feedback.Keyboard(m.Article.ID, nil)
+		keyboard = &kb
+	}
+	text := formatMessage(w.language, m.Article, articles[1:], description)
+	sent, err := botkit.SendPhotoOrHTML(ctx, w.bot, w.channelID, imageURL, text, botkit.LinkPreview{Disabled: true}, keyboard)
+	if err != nil {
+		return false, fmt.Errorf("send breaking news: %w", err)
+	}
+
+	if _, err := w.posts.Store(ctx, model.Post{
+		Kind:             model.PostKindBreaking,
+		ChannelID:        w.channelID,
+		Slot:             m.Rule,
+		ArticleCount:     len(articles),
+		Model:            usage.Model,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		Fallback:         description == "",
+		MessageID:        sent.MessageID,
+		ArticleIDs:       lo.Map(articles, func(a model.Article, _ int) int64 { return a.ID }),
+	}); err != nil {
+		slog.Error("failed to record breaking post", "err", err)
+	}
+
+	return true, nil
+}
+
+// unclaim returns claimed articles to the queue.
+func (w *Watcher) unclaim(ctx context.Context, ids []int64) {
+	if len(ids) == 0 {
+		return
+	}
+	if err := w.articles.Unclaim(context.WithoutCancel(ctx), ids); err != nil {
+		slog.Error("unclaim articles failed", "articles", ids, "err", err)
+	}
+}
+
+func (w *Watcher) summarize(ctx context.Context, m Match) (string, summary.Usage, error) {
+	articles := append([]model.Article{m.Article}, m.Related...)
+	systemPrompt, err := w.prompts.Render(ctx, prompt.BreakingSummary, prompt.Data{
+		Channel:  w.channelID,
+		Language: w.language,
+		Articles: articles,
+	})
+	if err != nil {
+		return "", summary.Usage{}, fmt.Errorf("render breaking prompt: %w", err)
+	}
+
+	var sb strings.Builder
+	for _, a := range articles {
+		sb.WriteString(a.Title)
+		if a.Summary != "" {
+			sb.WriteString("\n")
+			sb.WriteString(a.Summary)
+		}
+		sb.WriteString("\n\n")
+	}
+
+	res, err := w.summarizer.Summarize(ctx, sb.String(),
+		summary.WithSystemPrompt(systemPrompt),
+		summary.WithMaxTokens(maxSummaryTokens),
+	)
+	if err != nil {
+		return "", res.Usage, err
+	}
+	return strings.TrimSpace(res.Text), res.Usage, nil
+}
+
+// formatMessage renders a breaking post in language: the headline of lead,
+// the summary when there is one, and the other sources covering the story.
+func formatMessage(language string, lead model.Article, also []model.Article, description string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("⚡ <b>%s</b> <a href=\"%s\">%s</a>\n",
+		markup.EscapeForHTML(i18n.Text(language, i18n.BreakingHeader)), lead.Link, markup.EscapeForHTML(lead.Title)))
+	if description != "" {
+		sb.WriteString("\n")
+		sb.WriteString(markup.EscapeForHTML(description))
+		sb.WriteString("\n")
+	}
+
+	links := make([]string, 0, len(also))
+	for _, a := range also {
+		links = append(links, fmt.Sprintf("<a href=\"%s\">%s</a>", a.Link, markup.EscapeForHTML(a.SourceName)))
+	}
+	if len(links) > 0 {
+		sb.WriteString("\n" + markup.EscapeForHTML(i18n.Text(language, i18n.BreakingAlsoCovered)) + " ")
+		sb.WriteString(strings.Join(links, ", "))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// alsoCovered returns the related articles linked in a breaking post: one per
+// other named source.
+func alsoCovered(m Match) []model.Article {
+	var also []model.Article
+	seen := map[string]bool{m.Article.SourceName: true}
+	for _, a := range m.Related {
+		if a.SourceName == "" || seen[a.SourceName] {
+			continue
+		}
+		seen[a.SourceName] = true
+		also = append(also, a)
+	}
+	return also
+}
+
+// seen reports whether the story of a was posted recently.
+func (w *Watcher) seen(a model.Article) bool {
+	for _, p := range w.recent {
+		if ranking.SameStory(p.article, a) {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Watcher) forget(now time.Time) {
+	kept := w.recent[:0]
+	for _, p := range w.recent {
+		if now.Sub(p.at) <= w.rules.Window {
+			kept = append(kept, p)
+		}
+	}
+	w.recent = kept
+}
@@ -0,0 +1,67 @@
+package breaking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+// countingPosts counts the hourly budget lookups, which Check skips during
+// quiet hours.
+type countingPosts struct {
+	counts int
+}
+
+func (p *countingPosts) Store(context.Context, model.Post) (int64, error) { return 0, nil }
+
+func (p *countingPosts) CountSince(context.Context, string, int64, time.Time) (int, error) {
+	p.counts++
+	return 0, nil
+}
+
+func TestWatcher_Check(t *testing.T) {
+	t.Run("should keep quiet hours in the channel's timezone", func(t *testing.T) {
+		posts := &countingPosts{}
+		// 21:00 UTC is midnight in UTC+3, inside the 23-7 quiet hours.
+		w := New(nil, posts, nil, nil, nil, nil, 0, Rules{}, time.Minute, 0, 23, 7, "en", false, false, nil, time.FixedZone("UTC+3", 3*60*60))
+		w.now = func() time.Time { return time.Date(2026, 6, 1, 21, 0, 0, 0, time.UTC) }
+
+		assert.NoError(t, w.Check(context.Background()))
+		assert.Equal(t, 0, posts.counts)
+	})
+}
+
+func TestAlsoCovered(t *testing.T) {
+	lead := model.Article{ID: 1, SourceName: "A"}
+	m := Match{Article: lead, Related: []model.Article{
+		{ID: 2, SourceName: "A"},
+		{ID: 3, SourceName: "B"},
+		{ID: 4, SourceName: "B"},
+		{ID: 5},
+	}}
+
+	also := alsoCovered(m)
+	assert.Len(t, also, 1)
+	assert.Equal(t, int64(3), also[0].ID)
+}
+
+func TestFormatMessage(t *testing.T) {
+	lead := model.Article{Title: "Go 1.30 released", Link: "https://go.dev/blog"}
+	also := []model.Article{{Link: "https://example.com/go", SourceName: "Example"}}
+
+	t.Run("should write the labels in the channel language", func(t *testing.T) {
+		text := formatMessage("ru", lead, also, "")
+		assert.Contains(t, text, "⚡ <b>Срочно:</b> <a href=\"https://go.dev/blog\">Go 1.30 released</a>")
+		assert.Contains(t, text, "Также пишут: <a href=\"https://example.com/go\">Example</a>")
+	})
+
+	t.Run("should fall back to English", func(t *testing.T) {
+		text := formatMessage("", lead, also, "")
+		assert.Contains(t, text, "<b>Breaking:</b>")
+		assert.Contains(t, text, "Also covered by: ")
+	})
+}
@@ -0,0 +1,118 @@
+package breaking
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+	"github.com/0x0BSoD/newsMaker/internal/ranking"
+)
+
+// Rule names, recorded as the slot of breaking posts.
+const (
+	RulePriority = "priority"
+	RuleKeyword  = "keyword"
+	RuleCoverage = "coverage"
+)
+
+// Rules decide which fresh articles are breaking news. A zero MinPriority or
+// MinSources and empty Keywords disable the respective rule.
+type Rules struct {
+	// MinPriority matches articles from sources with at least this priority.
+	MinPriority int
+	// Keywords match articles whose title contains any of them.
+	Keywords []string
+	// MinSources matches stories covered by at least this many sources
+	// within Window.
+	MinSources int
+	Window     time.Duration
+}
+
+// Match is a breaking story: the article to post and the other articles
+// covering the same story.
+type Match struct {
+	Rule    string
+	Article model.Article
+	Related []model.Article
+}
+
+// Match returns the stories among articles that match a rule, most covered
+// first. Each story is matched at most once.
+func (r Rules) Match(articles []model.Article) []Match {
+	var matches []Match
+	for _, members := range ranking.Stories(articles) {
+		story := make([]model.Article, len(members))
+		for i, idx := range members {
+			story[i] = articles[idx]
+		}
+
+		rule := r.match(story)
+		if rule == "" {
+			continue
+		}
+
+		lead := leadArticle(story)
+		m := Match{Rule: rule, Article: story[lead]}
+		m.Related = append(m.Related, story[:lead]...)
+		m.Related = append(m.Related, story[lead+1:]...)
+		matches = append(matches, m)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return len(matches[i].Related) > len(matches[j].Related) })
+	return matches
+}
+
+func (r Rules) match(story []model.Article) string {
+	if r.MinSources > 0 && sourcesWithin(story, r.Window) >= r.MinSources {
+		return RuleCoverage
+	}
+	for _, a := range story {
+		if r.MinPriority > 0 && a.SourcePriority >= r.MinPriority {
+			return RulePriority
+		}
+	}
+	for _, a := range story {
+		title := strings.ToLower(a.Title)
+		for _, k := range r.Keywords {
+			if k = strings.ToLower(strings.TrimSpace(k)); k != "" && strings.Contains(title, k) {
+				return RuleKeyword
+			}
+		}
+	}
+	return ""
+}
+
+// sourcesWithin returns the largest number of distinct sources that
+// published the story within any window-long interval.
+func sourcesWithin(story []model.Article, window time.Duration) int {
+	sorted := make([]model.Article, len(story))
+	copy(sorted, story)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].PublishedAt.Before(sorted[j].PublishedAt) })
+
+	var best int
+	for i, first := range sorted {
+		sources := make(map[int64]bool)
+		for _, a := range sorted[i:] {
+			if window > 0 && a.PublishedAt.Sub(first.PublishedAt) > window {
+				break
+			}
+			sources[a.SourceID] = true
+		}
+		best = max(best, len(sources))
+	}
+	return best
+}
+
+// leadArticle returns the index of the article to post for a story: the one
+// from the highest-priority source, the earliest on ties.
+func leadArticle(story []model.Article) int {
+	lead := 0
+	for i, a := range story[1:] {
+		l := story[lead]
+		if a.SourcePriority > l.SourcePriority || (a.SourcePriority == l.SourcePriority && a.PublishedAt.Before(l.PublishedAt)) {
+			lead = i + 1
+		}
+	}
+	return lead
+}
@@ -0,0 +1,64 @@
+package breaking
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+var testNow = time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func article(id, sourceID int64, priority int, title string, age time.Duration) model.Article {
+	return model.Article{
+		ID:             id,
+		SourceID:       sourceID,
+		SourceName:     "source",
+		SourcePriority: priority,
+		Title:          title,
+		PublishedAt:    testNow.Add(-age),
+	}
+}
+
+func TestRules_Match(t *testing.T) {
+	t.Run("should match a story covered by enough sources", func(t *testing.T) {
+		rules := Rules{MinSources: 3, Window: 30 * time.Minute}
+		matches := rules.Match([]model.Article{
+			article(1, 1, 0, "Major cloud outage hits AWS us-east-1", 20*time.Minute),
+			article(2, 2, 0, "AWS us-east-1 outage: major cloud hits", 10*time.Minute),
+			article(3, 3, 5, "Major AWS us-east-1 cloud outage hits", 0),
+			article(4, 4, 0, "Unrelated library release", 0),
+		})
+
+		require.Len(t, matches, 1)
+		assert.Equal(t, RuleCoverage, matches[0].Rule)
+		assert.Equal(t, int64(3), matches[0].Article.ID, "the highest-priority source leads")
+		assert.Len(t, matches[0].Related, 2)
+	})
+
+	t.Run("should not count coverage outside the window", func(t *testing.T) {
+		rules := Rules{MinSources: 2, Window: 30 * time.Minute}
+		matches := rules.Match([]model.Article{
+			article(1, 1, 0, "Major cloud outage hits AWS", 2*time.Hour),
+			article(2, 2, 0, "Major cloud outage hits AWS", 0),
+		})
+
+		assert.Empty(t, matches)
+	})
+
+	t.Run("should match priority and keyword rules", func(t *testing.T) {
+		rules := Rules{MinPriority: 10, Keywords: []string{"zero-day"}}
+		matches := rules.Match([]model.Article{
+			article(1, 1, 10, "Go 1.26 released", 0),
+			article(2, 2, 0, "Chrome Zero-Day exploited in the wild", 0),
+			article(3, 3, 9, "Weekly newsletter", 0),
+		})
+
+		require.Len(t, matches, 2)
+		got := map[int64]string{matches[0].Article.ID: matches[0].Rule, matches[1].Article.ID: matches[1].Rule}
+		assert.Equal(t, map[int64]string{1: RulePriority, 2: RuleKeyword}, got)
+	})
+}
@@ -0,0 +1,293 @@
+// Package cluster groups digest articles into topics, either by clustering
+// their embeddings or, as the fallback, by their normalized RSS category.
+package cluster
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+	"github.com/0x0BSoD/newsMaker/internal/summary"
+)
+
+// DefaultTopic is the label of articles without a usable category.
+const DefaultTopic = "General"
+
+// ErrNoEmbedder is returned by Grouper.Clusters when embeddings are off.
+var ErrNoEmbedder = errors.New("no embedder configured")
+
+// maxEmbedRunes bounds the article text sent for embedding.
+const maxEmbedRunes = 1000
+
+// EmbeddingStorage is satisfied by storage.EmbeddingPostgresStorage.
+type EmbeddingStorage interface {
+	Embeddings(ctx context.Context, model string, articleIDs []int64) (map[int64][]float32, error)
+	StoreEmbedding(ctx context.Context, articleID int64, model string, vector []float32) error
+}
+
+// Labeler names a cluster of related articles.
+type Labeler interface {
+	Label(ctx context.Context, articles []model.Article) (string, error)
+}
+
+// Grouper groups articles into labeled topics. Without an embedder, or when
+// embedding fails, it groups by normalized category.
+type Grouper struct {
+	embedder   summary.Embedder
+	storage    EmbeddingStorage
+	labeler    Labeler
+	normalizer *Normalizer
+	threshold  float64
+}
+
+// NewGrouper creates a Grouper. embedder and labeler may be nil. threshold is
+// the minimum cosine similarity for an article to join a cluster.
+func NewGrouper(embedder summary.Embedder, storage EmbeddingStorage, labeler Labeler, normalizer *Normalizer, threshold float64) *Grouper {
+	return &Grouper{
+		embedder:   embedder,
+		storage:    storage,
+		labeler:    labeler,
+		normalizer: normalizer,
+		threshold:  threshold,
+	}
+}
+
+// Group returns articles keyed by topic label.
+func (g *Grouper) Group(ctx context.Context, articles []model.Article) map[string][]model.Article {
+	if g.embedder == nil || len(articles) == 0 {
+		return GroupByCategory(articles, g.normalizer)
+	}
+
+	clusters, err := g.Clusters(ctx, articles)
+	if err != nil {
+		slog.Warn("embedding articles failed, grouping by category", "err", err)
+		return GroupByCategory(articles, g.normalizer)
+	}
+
+	groups := make(map[string][]model.Article)
+	for _, members := range clusters {
+		clustered := make([]model.Article, len(members))
+		for i, idx := range members {
+			clustered[i] = articles[idx]
+		}
+		label := g.label(ctx, clustered)
+		groups[label] = append(groups[label], clustered...)
+	}
+
+	slog.Info("articles clustered", "articles", len(articles), "topics", len(groups))
+	return groups
+}
+
+// Clusters returns the indexes of the articles of every embedding cluster,
+// largest first, without labeling them.
+func (g *Grouper) Clusters(ctx context.Context, articles []model.Article) ([][]int, error) {
+	if g.embedder == nil {
+		return nil, ErrNoEmbedder
+	}
+
+	vectors, err := g.vectors(ctx, articles)
+	if err != nil {
+		return nil, err
+	}
+	return Greedy(vectors, g.threshold), nil
+}
+
+// vectors returns one vector per article, embedding only the articles that
+// have no stored vector for the current model.
+func (g *Grouper) vectors(ctx context.Context, articles []model.Article) ([][]float32, error) {
+	modelName := g.embedder.Model()
+
+	ids := make([]int64, len(articles))
+	for i, a := range articles {
+		ids[i] = a.ID
+	}
+
+	stored, err := g.storage.Embeddings(ctx, modelName, ids)
+	if err != nil {
+		slog.Warn("load embeddings failed, embedding all articles", "err", err)
+		stored = map[int64][]float32{}
+	}
+
+	var (
+		missing []int
+		texts   []string
+	)
+	for i, a := range articles {
+		if _, ok := stored[a.ID]; !ok {
+			missing = append(missing, i)
+			texts = append(texts, embedText(a))
+		}
+	}
+
+	if len(texts) > 0 {
+		embedded, err := g.embedder.Embed(ctx, texts)
+		if err != nil {
+			return nil, err
+		}
+		for j, i := range missing {
+			id := articles[i].ID
+			stored[id] = embedded[j]
+			if err := g.storage.StoreEmbedding(ctx, id, modelName, embedded[j]); err != nil {
+				slog.Warn("store embedding failed", "articleID", id, "err", err)
+			}
+		}
+	}
+
+	vectors := make([][]float32, len(articles))
+	for i, a := range articles {
+		vectors[i] = stored[a.ID]
+	}
+	return vectors, nil
+}
+
+// label names a cluster after the category shared by at least half of its
+// articles, asking the labeler only when there is no such category.
+func (g *Grouper) label(ctx context.Context, articles []model.Article) string {
+	top, count := dominantCategory(articles, g.normalizer)
+	if len(articles) == 1 || count*2 >= len(articles) || g.labeler == nil {
+		if top == "" {
+			return DefaultTopic
+		}
+		return top
+	}
+
+	label, err := g.labeler.Label(ctx, articles)
+	label = strings.TrimSpace(label)
+	if err != nil || label == "" {
+		slog.Warn("cluster labeling failed", "err", err)
+		if top == "" {
+			return DefaultTopic
+		}
+		return top
+	}
+	return label
+}
+
+// dominantCategory returns the most common normalized first category among
+// articles and how many articles have it. Ties go to the alphabetically
+// first category so labels are stable.
+func dominantCategory(articles []model.Article, n *Normalizer) (string, int) {
+	counts := make(map[string]int)
+	for _, a := range articles {
+		if c := firstCategory(a, n); c != "" {
+			counts[c]++
+		}
+	}
+
+	var (
+		top   string
+		count int
+	)
+	for c, cnt := range counts {
+		if cnt > count || (cnt == count && c < top) {
+			top, count = c, cnt
+		}
+	}
+	return top, count
+}
+
+// GroupByCategory groups articles by their normalized first RSS category,
+// using DefaultTopic when none is set.
+func GroupByCategory(articles []model.Article, n *Normalizer) map[string][]model.Article {
+	groups := make(map[string][]model.Article)
+	for _, a := range articles {
+		topic := firstCategory(a, n)
+		if topic == "" {
+			topic = DefaultTopic
+		}
+		groups[topic] = append(groups[topic], a)
+	}
+	return groups
+}
+
+func firstCategory(a model.Article, n *Normalizer) string {
+	if len(a.Categories) == 0 {
+		return ""
+	}
+	return n.Normalize(a.Categories[0])
+}
+
+func embedText(a model.Article) string {
+	text := a.Title
+	if a.Summary != "" {
+		text += ". " + a.Summary
+	}
+	if runes := []rune(text); len(runes) > maxEmbedRunes {
+		text = string(runes[:maxEmbedRunes])
+	}
+	return text
+}
+
+// Greedy clusters vectors in a single pass: each vector joins the cluster
+// whose centroid is most similar to it if that similarity is at least
+// threshold, and starts a new cluster otherwise. It returns the indexes of
+// the members of every cluster, largest cluster first.
+func Greedy(vectors [][]float32, threshold float64) [][]int {
+	var (
+		clusters  [][]int
+		centroids [][]float64
+	)
+
+	for i, v := range vectors {
+		best, bestSim := -1, threshold
+		for c, centroid := range centroids {
+			if sim := cosine64(centroid, v); sim >= bestSim {
+				best, bestSim = c, sim
+			}
+		}
+
+		if best < 0 {
+			centroid := make([]float64, len(v))
+			for k, x := range v {
+				centroid[k] = float64(x)
+			}
+			clusters = append(clusters, []int{i})
+			centroids = append(centroids, centroid)
+			continue
+		}
+
+		// Keep the centroid as the running mean of the members.
+		n := float64(len(clusters[best]))
+		for k := range centroids[best] {
+			if k < len(v) {
+				centroids[best][k] = (centroids[best][k]*n + float64(v[k])) / (n + 1)
+			}
+		}
+		clusters[best] = append(clusters[best], i)
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool { return len(clusters[i]) > len(clusters[j]) })
+	return clusters
+}
+
+// Cosine returns the cosine similarity of a and b, or 0 when either is a
+// zero vector or their lengths differ.
+func Cosine(a, b []float32) float64 {
+	a64 := make([]float64, len(a))
+	for i, x := range a {
+		a64[i] = float64(x)
+	}
+	return cosine64(a64, b)
+}
+
+func cosine64(a []float64, b []float32) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+
+	var dot, na, nb float64
+	for i := range a {
+		y := float64(b[i])
+		dot += a[i] * y
+		na += a[i] * a[i]
+		nb += y * y
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
@@ -0,0 +1,201 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+// fixture is an article with a recorded embedding and the topic it belongs to.
+type fixture struct {
+	ID         int64     `json:"id"`
+	Title      string    `json:"title"`
+	Categories []string  `json:"categories"`
+	Topic      string    `json:"topic"`
+	Vector     []float32 `json:"vector"`
+}
+
+func loadFixtures(t *testing.T) []fixture {
+	t.Helper()
+
+	data, err := os.ReadFile("testdata/articles.json")
+	require.NoError(t, err)
+
+	var fixtures []fixture
+	require.NoError(t, json.Unmarshal(data, &fixtures))
+	return fixtures
+}
+
+func fixtureArticles(fixtures []fixture) []model.Article {
+	articles := make([]model.Article, len(fixtures))
+	for i, f := range fixtures {
+		articles[i] = model.Article{ID: f.ID, Title: f.Title, Categories: f.Categories}
+	}
+	return articles
+}
+
+// recordedEmbedder replays the fixture vectors, keyed by embedded text.
+type recordedEmbedder struct {
+	vectors map[string][]float32
+	err     error
+	calls   int
+}
+
+func newRecordedEmbedder(fixtures []fixture) *recordedEmbedder {
+	e := &recordedEmbedder{vectors: make(map[string][]float32)}
+	for _, f := range fixtures {
+		e.vectors[f.Title] = f.Vector
+	}
+	return e
+}
+
+func (e *recordedEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	e.calls++
+	if e.err != nil {
+		return nil, e.err
+	}
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		out[i] = e.vectors[text]
+	}
+	return out, nil
+}
+
+func (e *recordedEmbedder) Model() string { return "recorded" }
+
+type memStorage map[int64][]float32
+
+func (m memStorage) Embeddings(_ context.Context, _ string, ids []int64) (map[int64][]float32, error) {
+	out := make(map[int64][]float32)
+	for _, id := range ids {
+		if v, ok := m[id]; ok {
+			out[id] = v
+		}
+	}
+	return out, nil
+}
+
+func (m memStorage) StoreEmbedding(_ context.Context, id int64, _ string, vector []float32) error {
+	m[id] = vector
+	return nil
+}
+
+type stubLabeler struct {
+	label string
+	calls int
+}
+
+func (l *stubLabeler) Label(_ context.Context, _ []model.Article) (string, error) {
+	l.calls++
+	return l.label, nil
+}
+
+func testNormalizer(t *testing.T) *Normalizer {
+	t.Helper()
+
+	n, err := NewNormalizer([]string{"k8s=Kubernetes", "cloud native=Kubernetes", "golang=Go"})
+	require.NoError(t, err)
+	return n
+}
+
+func TestGreedy(t *testing.T) {
+	fixtures := loadFixtures(t)
+	vectors := make([][]float32, len(fixtures))
+	for i, f := range fixtures {
+		vectors[i] = f.Vector
+	}
+
+	clusters := Greedy(vectors, 0.75)
+	require.Len(t, clusters, 3)
+
+	for _, members := range clusters {
+		topic := fixtures[members[0]].Topic
+		for _, idx := range members {
+			assert.Equal(t, topic, fixtures[idx].Topic, "article %d clustered with %s", fixtures[idx].ID, topic)
+		}
+	}
+}
+
+func TestGrouper_Group(t *testing.T) {
+	fixtures := loadFixtures(t)
+	articles := fixtureArticles(fixtures)
+
+	t.Run("should cluster and label related articles", func(t *testing.T) {
+		labeler := &stubLabeler{label: "AI models"}
+		g := NewGrouper(newRecordedEmbedder(fixtures), memStorage{}, labeler, testNormalizer(t), 0.75)
+
+		groups := g.Group(context.Background(), articles)
+		assert.Len(t, groups["Kubernetes"], 3)
+		assert.Len(t, groups["Go"], 2)
+		assert.Len(t, groups["AI models"], 3)
+		assert.Equal(t, 1, labeler.calls, "only the cluster without a dominant category needs a label")
+	})
+
+	t.Run("should reuse stored vectors", func(t *testing.T) {
+		embedder := newRecordedEmbedder(fixtures)
+		g := NewGrouper(embedder, memStorage{}, nil, testNormalizer(t), 0.75)
+
+		g.Group(context.Background(), articles)
+		g.Group(context.Background(), articles)
+		assert.Equal(t, 1, embedder.calls)
+	})
+
+	t.Run("should group by normalized category when embedding fails", func(t *testing.T) {
+		embedder := newRecordedEmbedder(fixtures)
+		embedder.err = errors.New("connection refused")
+		g := NewGrouper(embedder, memStorage{}, nil, testNormalizer(t), 0.75)
+
+		groups := g.Group(context.Background(), articles)
+		assert.Len(t, groups["Kubernetes"], 3)
+		assert.Len(t, groups["Go"], 2)
+		assert.Len(t, groups[DefaultTopic], 1)
+	})
+}
+
+func TestGrouper_Clusters(t *testing.T) {
+	fixtures := loadFixtures(t)
+	articles := fixtureArticles(fixtures)
+
+	t.Run("should return the embedding clusters", func(t *testing.T) {
+		g := NewGrouper(newRecordedEmbedder(fixtures), memStorage{}, nil, testNormalizer(t), 0.75)
+
+		clusters, err := g.Clusters(context.Background(), articles)
+		require.NoError(t, err)
+		for _, members := range clusters {
+			for _, idx := range members {
+				assert.Equal(t, fixtures[members[0]].Topic, fixtures[idx].Topic)
+			}
+		}
+	})
+
+	t.Run("should fail without an embedder", func(t *testing.T) {
+		g := NewGrouper(nil, nil, nil, testNormalizer(t), 0.75)
+
+		_, err := g.Clusters(context.Background(), articles)
+		assert.ErrorIs(t, err, ErrNoEmbedder)
+	})
+}
+
+func TestNormalizer(t *testing.T) {
+	n := testNormalizer(t)
+
+	assert.Equal(t, "Kubernetes", n.Normalize(" K8S "))
+	assert.Equal(t, "Kubernetes", n.Normalize("kubernetes"))
+	assert.Equal(t, "Rust", n.Normalize("Rust"))
+
+	_, err := NewNormalizer([]string{"no-separator"})
+	assert.Error(t, err)
+}
+
+func TestCosine(t *testing.T) {
+	assert.InDelta(t, 1.0, Cosine([]float32{1, 2}, []float32{2, 4}), 1e-9)
+	assert.InDelta(t, 0.0, Cosine([]float32{1, 0}, []float32{0, 1}), 1e-9)
+	assert.Zero(t, Cosine([]float32{0, 0}, []float32{1, 1}))
+}
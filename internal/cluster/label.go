@@ -0,0 +1,60 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+	"github.com/0x0BSoD/newsMaker/internal/prompt"
+	"github.com/0x0BSoD/newsMaker/internal/summary"
+)
+
+// maxLabelTokens caps the generated label; labels are a few words.
+const maxLabelTokens = 16
+
+// PromptRenderer is satisfied by prompt.Library.
+type PromptRenderer interface {
+	Render(ctx context.Context, name string, data prompt.Data) (string, error)
+}
+
+// LLMLabeler asks the summarizer for a short topic label, using the
+// topic_label prompt template as the system prompt.
+type LLMLabeler struct {
+	summarizer summary.Summarizer
+	prompts    PromptRenderer
+}
+
+func NewLLMLabeler(summarizer summary.Summarizer, prompts PromptRenderer) *LLMLabeler {
+	return &LLMLabeler{
+		summarizer: summarizer,
+		prompts:    prompts,
+	}
+}
+
+func (l *LLMLabeler) Label(ctx context.Context, articles []model.Article) (string, error) {
+	systemPrompt, err := l.prompts.Render(ctx, prompt.TopicLabel, prompt.Data{Articles: articles})
+	if err != nil {
+		return "", fmt.Errorf("render label prompt: %w", err)
+	}
+
+	var sb strings.Builder
+	for _, a := range articles {
+		sb.WriteString("- ")
+		sb.WriteString(a.Title)
+		sb.WriteString("\n")
+	}
+
+	res, err := l.summarizer.Summarize(ctx, sb.String(),
+		summary.WithSystemPrompt(systemPrompt),
+		summary.WithTemperature(0),
+		summary.WithMaxTokens(maxLabelTokens),
+	)
+	if err != nil {
+		return "", err
+	}
+
+	// Models like to quote or punctuate the label.
+	label := strings.TrimSpace(strings.SplitN(res.Text, "\n", 2)[0])
+	return strings.Trim(label, `"'.*# `), nil
+}
@@ -0,0 +1,43 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Normalizer maps inconsistent feed categories ("k8s", "Cloud Native") to a
+// canonical topic ("Kubernetes"). Matching is case-insensitive.
+type Normalizer struct {
+	aliases map[string]string
+}
+
+// NewNormalizer parses aliases of the form "alias=Canonical". Every canonical
+// name also maps to itself, so differently cased spellings collapse.
+func NewNormalizer(aliases []string) (*Normalizer, error) {
+	n := &Normalizer{aliases: make(map[string]string, len(aliases))}
+
+	for _, entry := range aliases {
+		alias, canonical, ok := strings.Cut(entry, "=")
+		alias, canonical = strings.TrimSpace(alias), strings.TrimSpace(canonical)
+		if !ok || alias == "" || canonical == "" {
+			return nil, fmt.Errorf("invalid category alias %q, want alias=Canonical", entry)
+		}
+		n.aliases[strings.ToLower(alias)] = canonical
+		n.aliases[strings.ToLower(canonical)] = canonical
+	}
+
+	return n, nil
+}
+
+// Normalize returns the canonical name of category, or category itself with
+// surrounding space trimmed when it has no alias. A nil Normalizer only trims.
+func (n *Normalizer) Normalize(category string) string {
+	category = strings.TrimSpace(category)
+	if n == nil {
+		return category
+	}
+	if canonical, ok := n.aliases[strings.ToLower(category)]; ok {
+		return canonical
+	}
+	return category
+}
@@ -11,9 +11,14 @@ import (
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
 	"github.com/0x0BSoD/newsMaker/internal/botkit/markup"
+	"github.com/0x0BSoD/newsMaker/internal/gate"
 	"github.com/0x0BSoD/newsMaker/internal/github"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+	"github.com/0x0BSoD/newsMaker/internal/prompt"
 	"github.com/0x0BSoD/newsMaker/internal/storage"
+	"github.com/0x0BSoD/newsMaker/internal/summary"
 	"github.com/0x0BSoD/newsMaker/internal/telegraph"
 )
 
@@ -25,45 +30,68 @@ type RepoStorage interface {
 	GetNewAndTrending(ctx context.Context, topic string, since time.Time, minGrowthPct float64) (newRepos []storage.GitHubRepo, trending []storage.GitHubRepo, err error)
 }
 
-// Summarizer is satisfied by the Ollama / OpenAI summarizer implementations.
-type Summarizer interface {
-	Summarize(text string) (string, error)
-	CountTokens(text string) (int, error)
+// PostStorage is satisfied by storage.PostPostgresStorage.
+type PostStorage interface {
+	Store(ctx context.Context, post model.Post) (int64, error)
+}
+
+// PostGate defers posts during quiet hours and over the daily cap; see
+// gate.Gate. It is checked before a post is generated, so that none goes
+// stale while held back; the sender enforces it again on the send itself.
+type PostGate interface {
+	Wait(ctx context.Context, channelID int64) error
+}
+
+// PromptRenderer is satisfied by prompt.Library.
+type PromptRenderer interface {
+	Render(ctx context.Context, name string, data prompt.Data) (string, error)
 }
 
 type Digest struct {
 	gh              *github.Client
 	tph             *telegraph.Client
-	bot             *tgbotapi.BotAPI
+	bot             botkit.Sender
 	storage         RepoStorage
-	summarizer      Summarizer
+	posts           PostStorage
+	summarizer      summary.Summarizer
+	prompts         PromptRenderer
+	language        string
 	channelID       int64
 	topics          []string
 	interval        time.Duration
 	summaryInputDir string
+	gate            PostGate
 }
 
 func New(
 	gh *github.Client,
 	tph *telegraph.Client,
-	bot *tgbotapi.BotAPI,
+	bot botkit.Sender,
 	storage RepoStorage,
-	summarizer Summarizer,
+	posts PostStorage,
+	summarizer summary.Summarizer,
+	prompts PromptRenderer,
+	language string,
 	channelID int64,
 	topics []string,
 	interval time.Duration,
 	summaryInputDir string,
+	gate PostGate,
 ) *Digest {
 	return &Digest{
 		gh:              gh,
 		tph:             tph,
 		bot:             bot,
 		storage:         storage,
+		posts:           posts,
 		summarizer:      summarizer,
+		prompts:         prompts,
+		language:        language,
 		channelID:       channelID,
 		topics:          topics,
 		interval:        interval,
 		summaryInputDir: summaryInputDir,
+		gate:            gate,
 	}
 }
 
@@ -118,6 +146,9 @@ func (d *Digest) run(ctx context.Context) error {
 		return nil
 	}
 
+	if err := d.gate.Wait(ctx, d.channelID); err != nil {
+		return err
+	}
 	return d.send(ctx, d.channelID, true)
 }
 
@@ -127,7 +158,7 @@ func (d *Digest) RunTest(ctx context.Context, channelID int64) error {
 	if len(d.topics) == 0 {
 		return fmt.Errorf("no topics configured")
 	}
-	return d.send(ctx, channelID, false)
+	return d.send(gate.Exempt(ctx), channelID, false)
 }
 
 func (d *Digest) send(ctx context.Context, channelID int64, markPosted bool) error {
@@ -152,6 +183,8 @@ func (d *Digest) send(ctx context.Context, channelID int64, markPosted bool) err
 		allFullNames      []string
 		trendingInputBuf  strings.Builder
 		trendingOutputBuf strings.Builder
+		usage             summary.Usage
+		estimatedTokens   int
 	)
 
 	for _, topic := range d.topics {
@@ -194,22 +227,40 @@ func (d *Digest) send(ctx context.Context, channelID int64, markPosted bool) err
 		}
 
 		// AI summary of changes.
-		summaryInput := buildSummaryInput(topic, newRepos, trending)
+		data := prompt.Data{
+			Channel:  channelID,
+			Language: d.language,
+			Topic:    topic,
+			NewRepos: newRepos,
+			Trending: trending,
+		}
+		summaryInput, err := d.prompts.Render(ctx, prompt.GitHubDigestInput, data)
+		if err != nil {
+			return fmt.Errorf("render summary input: %w", err)
+		}
+		systemPrompt, err := d.prompts.Render(ctx, prompt.GitHubDigestSystem, data)
+		if err != nil {
+			return fmt.Errorf("render summary prompt: %w", err)
+		}
 		trendingInputBuf.WriteString(summaryInput)
 		trendingInputBuf.WriteString("\n---\n\n")
-		summary, err := d.summarizer.Summarize(summaryInput)
+		if tokens, err := d.summarizer.CountTokens(summaryInput); err == nil {
+			estimatedTokens += tokens
+		}
+		res, err := d.summarizer.Summarize(ctx, summaryInput, summary.WithSystemPrompt(systemPrompt))
+		usage.Add(res.Usage)
 		if err != nil {
 			slog.Error("summarize failed", "topic", topic, "err", err)
-			summary = ""
+			res.Text = ""
 		}
-		trendingOutputBuf.WriteString(fmt.Sprintf("### %s\n\n%s\n\n---\n\n", topic, summary))
+		trendingOutputBuf.WriteString(fmt.Sprintf("### %s\n\n%s\n\n---\n\n", topic, res.Text))
 
 		results = append(results, topicResult{
 			topic:    topic,
 			newRepos: newRepos,
 			trending: trending,
 			pageURL:  pageURL,
-			summary:  summary,
+			summary:  res.Text,
 		})
 		totalNew += len(newRepos)
 		totalTrending += len(trending)
@@ -228,7 +279,8 @@ func (d *Digest) send(ctx context.Context, channelID int64, markPosted bool) err
 	msg.ParseMode = "HTML"
 	msg.DisableWebPagePreview = true
 
-	if _, err := d.bot.Send(msg); err != nil {
+	sent, err := d.bot.Send(ctx, msg)
+	if err != nil {
 		return fmt.Errorf("send telegram message: %w", err)
 	}
 
@@ -236,6 +288,19 @@ func (d *Digest) send(ctx context.Context, channelID int64, markPosted bool) err
 		return nil
 	}
 
+	if _, err := d.posts.Store(ctx, model.Post{
+		Kind:             model.PostKindGitHubDigest,
+		ChannelID:        channelID,
+		ArticleCount:     totalNew + totalTrending,
+		Model:            usage.Model,
+		EstimatedTokens:  estimatedTokens,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		MessageID:        sent.MessageID,
+	}); err != nil {
+		slog.Error("store post failed", "err", err)
+	}
+
 	// Snapshot stars_at_last_digest for ALL seen repos so the next digest can
 	// detect growth relative to this run, even for repos not in the delta.
 	if err := d.storage.MarkPosted(ctx, allFullNames); err != nil {
@@ -284,44 +349,6 @@ func buildTelegramMessage(results []topicResult, totalNew, totalTrending int, ha
 	return sb.String()
 }
 
-func buildSummaryInput(topic string, newRepos, trending []storage.GitHubRepo) string {
-	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("Topic: %s\n", topic))
-
-	if len(newRepos) > 0 {
-		sb.WriteString("\nNew repos:\n")
-		for _, r := range newRepos {
-			lang := r.Language
-			if lang == "" {
-				lang = "unknown"
-			}
-			sb.WriteString(fmt.Sprintf("- %s (%d stars, %s): %s\n", r.FullName, r.Stars, lang, r.Description))
-		}
-	}
-
-	if len(trending) > 0 {
-		sb.WriteString("\nTrending (significant star growth):\n")
-		for _, r := range trending {
-			lang := r.Language
-			if lang == "" {
-				lang = "unknown"
-			}
-			prev := 0
-			if r.StarsAtLastDigest != nil {
-				prev = *r.StarsAtLastDigest
-			}
-			var growthStr string
-			if prev > 0 {
-				pct := float64(r.Stars-prev) / float64(prev) * 100
-				growthStr = fmt.Sprintf("+%.0f%%", pct)
-			}
-			sb.WriteString(fmt.Sprintf("- %s (%d stars %s, %s): %s\n", r.FullName, r.Stars, growthStr, lang, r.Description))
-		}
-	}
-
-	return sb.String()
-}
-
 // isReadable returns true if the text contains only Latin, Cyrillic, or
 // script-neutral (digits, symbols, spaces) characters.
 func isReadable(text string) bool {
@@ -0,0 +1,54 @@
+// Package feedback builds the vote buttons posted under channel messages and
+// reads back the votes readers press.
+package feedback
+
+import (
+	"fmt"
+	"strconv"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+// CallbackPrefix routes the button callbacks to the feedback view.
+const CallbackPrefix = "fb"
+
+// Keyboard returns the vote buttons of a post with the current counts.
+// articleID is zero for posts covering several articles.
+func Keyboard(articleID int64, counts map[string]int) tgbotapi.InlineKeyboardMarkup {
+	button := func(label, vote string) tgbotapi.InlineKeyboardButton {
+		if n := counts[vote]; n > 0 {
+			label = fmt.Sprintf("%s %d", label, n)
+		}
+		return tgbotapi.NewInlineKeyboardButtonData(label, botkit.CallbackData(CallbackPrefix, vote, strconv.FormatInt(articleID, 10)))
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		button("👍", model.VoteUp),
+		button("👎", model.VoteDown),
+		button("More like this", model.VoteMore),
+	))
+}
+
+// ParseCallback returns the vote and article ID of a button press.
+func ParseCallback(data string) (string, int64, error) {
+	prefix, args := botkit.ParseCallbackData(data)
+	if prefix != CallbackPrefix || len(args) != 2 {
+		return "", 0, fmt.Errorf("not a feedback callback: %q", data)
+	}
+
+	vote := args[0]
+	switch vote {
+	case model.VoteUp, model.VoteDown, model.VoteMore:
+	default:
+		return "", 0, fmt.Errorf("unknown vote %q", vote)
+	}
+
+	articleID, err := strconv.ParseInt(args[1], 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid article ID %q: %w", args[1], err)
+	}
+	return vote, articleID, nil
+}
@@ -0,0 +1,48 @@
+package feedback
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+func TestKeyboard(t *testing.T) {
+	kb := Keyboard(42, map[string]int{model.VoteUp: 3})
+	require.Len(t, kb.InlineKeyboard, 1)
+	row := kb.InlineKeyboard[0]
+	require.Len(t, row, 3)
+
+	assert.Equal(t, "👍 3", row[0].Text)
+	assert.Equal(t, "👎", row[1].Text)
+	assert.Equal(t, "More like this", row[2].Text)
+
+	for _, b := range row {
+		require.NotNil(t, b.CallbackData)
+		assert.LessOrEqual(t, len(*b.CallbackData), botkit.MaxCallbackData)
+	}
+
+	vote, articleID, err := ParseCallback(*row[1].CallbackData)
+	require.NoError(t, err)
+	assert.Equal(t, model.VoteDown, vote)
+	assert.Equal(t, int64(42), articleID)
+}
+
+func TestParseCallback(t *testing.T) {
+	t.Run("should accept a digest vote", func(t *testing.T) {
+		vote, articleID, err := ParseCallback("fb:more:0")
+		require.NoError(t, err)
+		assert.Equal(t, model.VoteMore, vote)
+		assert.Zero(t, articleID)
+	})
+
+	t.Run("should reject other callbacks", func(t *testing.T) {
+		for _, data := range []string{"src:1", "fb:up", "fb:meh:1", "fb:up:x"} {
+			_, _, err := ParseCallback(data)
+			assert.Error(t, err, data)
+		}
+	})
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/tomakado/containers/set"
 
+	"github.com/0x0BSoD/newsMaker/internal/lang"
 	"github.com/0x0BSoD/newsMaker/internal/model"
 	"github.com/0x0BSoD/newsMaker/internal/reporter"
 	src "github.com/0x0BSoD/newsMaker/internal/source"
@@ -32,6 +33,9 @@ type Source interface {
 	Fetch(ctx context.Context) ([]model.Item, error)
 }
 
+// sourceTimeout bounds the fetch of one source.
+const sourceTimeout = 60 * time.Second
+
 type Fetcher struct {
 	articles ArticleStorage
 	sources  SourcesProvider
@@ -85,11 +89,16 @@ func (f *Fetcher) Fetch(ctx context.Context) error {
 		return err
 	}
 
-	const sourceTimeout = 60 * time.Second
-
-	var wg sync.WaitGroup
+	var (
+		wg  sync.WaitGroup
+		now = time.Now()
+	)
 
 	for _, source := range sources {
+		if source.Paused(now) {
+			continue
+		}
+
 		wg.Add(1)
 
 		s, err := newSource(source)
@@ -126,6 +135,20 @@ func (f *Fetcher) Fetch(ctx context.Context) error {
 	return nil
 }
 
+// FetchSource fetches the items of one source, paused or not, without
+// storing anything.
+func (f *Fetcher) FetchSource(ctx context.Context, source model.Source) ([]model.Item, error) {
+	s, err := newSource(source)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sourceTimeout)
+	defer cancel()
+
+	return s.Fetch(ctx)
+}
+
 func (f *Fetcher) processItems(ctx context.Context, source Source, items []model.Item) error {
 	for _, item := range items {
 		item.Date = item.Date.UTC()
@@ -140,6 +163,8 @@ func (f *Fetcher) processItems(ctx context.Context, source Source, items []model
 			Link:        item.Link,
 			Summary:     item.Summary,
 			Categories:  item.Categories,
+			ImageURL:    item.ImageURL,
+			Language:    lang.Detect(item.Title + "\n" + item.Summary),
 			PublishedAt: item.Date,
 		}); err != nil {
 			return err
@@ -149,16 +174,60 @@ func (f *Fetcher) processItems(ctx context.Context, source Source, items []model
 	return nil
 }
 
+// Parts of an item a filter keyword can match.
+const (
+	SkipFieldCategory = "category"
+	SkipFieldTitle    = "title"
+)
+
+// PreviewItem is a fetched item and the filter rule that would skip it, if
+// any.
+type PreviewItem struct {
+	model.Item
+	// SkipKeyword is the filter keyword the item matches and SkipField where
+	// it matched; both are empty for items that would be stored.
+	SkipKeyword string
+	SkipField   string
+}
+
+// Preview fetches a source, paused or not, and reports which items the
+// filter keywords would skip. Nothing is stored.
+func (f *Fetcher) Preview(ctx context.Context, source model.Source) ([]PreviewItem, error) {
+	items, err := f.FetchSource(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+
+	previews := make([]PreviewItem, 0, len(items))
+	for _, item := range items {
+		item.Date = item.Date.UTC()
+		keyword, field := f.skipRule(item)
+		previews = append(previews, PreviewItem{Item: item, SkipKeyword: keyword, SkipField: field})
+	}
+
+	return previews, nil
+}
+
 func (f *Fetcher) itemShouldBeSkipped(item model.Item) bool {
+	keyword, _ := f.skipRule(item)
+	return keyword != ""
+}
+
+// skipRule returns the first filter keyword matching the item and the field
+// it matched, or empty strings.
+func (f *Fetcher) skipRule(item model.Item) (string, string) {
 	categoriesSet := set.New(item.Categories...)
 
 	for _, keyword := range f.filterKeywords {
-		if categoriesSet.Contains(keyword) || strings.Contains(strings.ToLower(item.Title), keyword) {
-			return true
+		if categoriesSet.Contains(keyword) {
+			return keyword, SkipFieldCategory
+		}
+		if strings.Contains(strings.ToLower(item.Title), keyword) {
+			return keyword, SkipFieldTitle
 		}
 	}
 
-	return false
+	return "", ""
 }
 
 func newSource(m model.Source) (Source, error) {
@@ -5,7 +5,9 @@ import (
 	_ "embed"
 	"net/http"
 	"net/http/httptest"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/0x0BSoD/newsMaker/internal/fetcher/mocks"
 	"github.com/stretchr/testify/assert"
@@ -33,12 +35,14 @@ func TestFetcher_Fetch(t *testing.T) {
 						Name:     "dev.to",
 						FeedURL:  source1Server.URL,
 						Priority: 10,
+						Enabled:  true,
 					},
 					{
 						ID:       2,
 						Name:     "Go Time Podcast",
 						FeedURL:  source2Server.URL,
 						Priority: 100,
+						Enabled:  true,
 					},
 				}, nil
 			},
@@ -77,6 +81,54 @@ func TestFetcher_Fetch(t *testing.T) {
 		require.NoError(t, fetcher.Fetch(context.Background()))
 		assert.Len(t, articles, 3)
 	})
+
+	t.Run("should skip paused sources", func(t *testing.T) {
+		var (
+			mu             sync.Mutex
+			sourceIDs      = make(map[int64]bool)
+			articleStorage = &mocks.ArticleStorageMock{
+				StoreFunc: func(ctx context.Context, article model.Article) error {
+					mu.Lock()
+					defer mu.Unlock()
+					sourceIDs[article.SourceID] = true
+					return nil
+				},
+			}
+			pausedProvider = &mocks.SourcesProviderMock{
+				SourcesFunc: func(ctx context.Context) ([]model.Source, error) {
+					return []model.Source{
+						{ID: 1, Name: "dev.to", FeedURL: source1Server.URL, Enabled: true},
+						{ID: 2, Name: "Go Time Podcast", FeedURL: source2Server.URL},
+						{ID: 3, Name: "dev.to paused", FeedURL: source1Server.URL, Enabled: true, PausedUntil: time.Now().Add(time.Hour)},
+						{ID: 4, Name: "Go Time resumed", FeedURL: source2Server.URL, Enabled: true, PausedUntil: time.Now().Add(-time.Hour)},
+					}, nil
+				},
+			}
+			fetcher = fetcher.New(articleStorage, pausedProvider, 0, nil, nil)
+		)
+
+		require.NoError(t, fetcher.Fetch(context.Background()))
+		assert.Equal(t, map[int64]bool{1: true, 4: true}, sourceIDs)
+	})
+}
+
+func TestFetcher_Preview(t *testing.T) {
+	var (
+		server  = setupFeedSever(feed1)
+		fetcher = fetcher.New(nil, nil, 0, []string{"leetcode", "golang"}, nil)
+	)
+
+	t.Run("should report the rule skipping each item", func(t *testing.T) {
+		items, err := fetcher.Preview(context.Background(), model.Source{Name: "dev.to", FeedURL: server.URL})
+		require.NoError(t, err)
+
+		skipped := make(map[string]string)
+		for _, item := range items {
+			skipped[item.Title] = item.SkipKeyword + "/" + item.SkipField
+		}
+		assert.Equal(t, "leetcode/category", skipped["Climbing Stairs LeetCode 70"])
+		assert.Equal(t, "golang/title", skipped["My Favorite Free Courses to Learn Golang in 2023"])
+	})
 }
 
 func setupFeedSever(feed []byte) *httptest.Server {
@@ -0,0 +1,153 @@
+// Package gate holds channel posts back during quiet hours and once the
+// daily post cap of a channel is reached. Held back posts are deferred to
+// the next allowed time, never dropped.
+package gate
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PostCounter is satisfied by storage.PostPostgresStorage.
+type PostCounter interface {
+	CountChannelSince(ctx context.Context, channelID int64, since time.Time) (int, error)
+}
+
+// Policy is the posting policy of one channel. Quiet hours run from
+// QuietStart to QuietEnd in Location; see InQuietHours. MaxPerDay of zero
+// means no cap; days start at midnight in Location.
+type Policy struct {
+	Location   *time.Location
+	QuietStart int
+	QuietEnd   int
+	MaxPerDay  int
+}
+
+func (p Policy) location() *time.Location {
+	if p.Location == nil {
+		return time.Local
+	}
+	return p.Location
+}
+
+// afterQuiet returns t, or the end of the quiet hours t falls in.
+func (p Policy) afterQuiet(t time.Time) time.Time {
+	if !InQuietHours(t.Hour(), p.QuietStart, p.QuietEnd) {
+		return t
+	}
+	y, m, d := t.Date()
+	if p.QuietStart > p.QuietEnd && t.Hour() >= p.QuietStart {
+		d++
+	}
+	return time.Date(y, m, d, p.QuietEnd, 0, 0, 0, t.Location())
+}
+
+type Gate struct {
+	posts    PostCounter
+	fallback Policy
+	policies map[int64]Policy
+	now      func() time.Time
+}
+
+// New returns a gate applying policies by channel ID and fallback to
+// channels without one.
+func New(posts PostCounter, fallback Policy, policies map[int64]Policy) *Gate {
+	return &Gate{
+		posts:    posts,
+		fallback: fallback,
+		policies: policies,
+		now:      time.Now,
+	}
+}
+
+func (g *Gate) policy(channelID int64) Policy {
+	if p, ok := g.policies[channelID]; ok {
+		return p
+	}
+	return g.fallback
+}
+
+// Next returns the earliest time from now on at which channelID may be
+// posted to.
+func (g *Gate) Next(ctx context.Context, channelID int64) (time.Time, error) {
+	p := g.policy(channelID)
+	next := p.afterQuiet(g.now().In(p.location()))
+	if p.MaxPerDay <= 0 {
+		return next, nil
+	}
+
+	y, m, d := next.Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, next.Location())
+	sent, err := g.posts.CountChannelSince(ctx, channelID, day)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("count posts: %w", err)
+	}
+	if sent >= p.MaxPerDay {
+		next = p.afterQuiet(day.AddDate(0, 0, 1))
+	}
+	return next, nil
+}
+
+// Allowed reports whether channelID may be posted to now.
+func (g *Gate) Allowed(ctx context.Context, channelID int64) (bool, error) {
+	next, err := g.Next(ctx, channelID)
+	if err != nil {
+		return false, err
+	}
+	return !next.After(g.now()), nil
+}
+
+// Wait blocks until channelID may be posted to or ctx is done.
+func (g *Gate) Wait(ctx context.Context, channelID int64) error {
+	for {
+		next, err := g.Next(ctx, channelID)
+		if err != nil {
+			return err
+		}
+		wait := next.Sub(g.now())
+		if wait <= 0 {
+			return nil
+		}
+
+		slog.Info("posting deferred", "channel", channelID, "until", next)
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// ParseQuietHours parses quiet hours written as "start-end", e.g. "23-7".
+func ParseQuietHours(s string) (int, int, error) {
+	startStr, endStr, ok := strings.Cut(s, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("quiet hours %q: want start-end, e.g. 23-7", s)
+	}
+	start, err := strconv.Atoi(strings.TrimSpace(startStr))
+	if err != nil || start < 0 || start > 23 {
+		return 0, 0, fmt.Errorf("quiet hours %q: invalid start hour", s)
+	}
+	end, err := strconv.Atoi(strings.TrimSpace(endStr))
+	if err != nil || end < 0 || end > 23 {
+		return 0, 0, fmt.Errorf("quiet hours %q: invalid end hour", s)
+	}
+	return start, end, nil
+}
+
+// InQuietHours reports whether hour falls in [start, end), wrapping around
+// midnight when start > end. Equal bounds mean no quiet hours.
+func InQuietHours(hour, start, end int) bool {
+	switch {
+	case start == end:
+		return false
+	case start < end:
+		return hour >= start && hour < end
+	default:
+		return hour >= start || hour < end
+	}
+}
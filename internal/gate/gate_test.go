@@ -0,0 +1,107 @@
+package gate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCounter struct {
+	sent  int
+	since time.Time
+}
+
+func (f *fakeCounter) CountChannelSince(_ context.Context, _ int64, since time.Time) (int, error) {
+	f.since = since
+	return f.sent, nil
+}
+
+func TestGate_Next(t *testing.T) {
+	loc := time.FixedZone("UTC+3", 3*60*60)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 6, day, hour, minute, 0, 0, loc)
+	}
+	newGate := func(sent int, now time.Time) (*Gate, *fakeCounter) {
+		counter := &fakeCounter{sent: sent}
+		g := New(counter, Policy{}, map[int64]Policy{
+			1: {Location: loc, QuietStart: 23, QuietEnd: 7, MaxPerDay: 3},
+		})
+		g.now = func() time.Time { return now }
+		return g, counter
+	}
+	ctx := context.Background()
+
+	t.Run("should allow posting outside quiet hours under the cap", func(t *testing.T) {
+		g, counter := newGate(2, at(1, 12, 30))
+		next, err := g.Next(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, next.Equal(at(1, 12, 30)))
+		assert.True(t, counter.since.Equal(at(1, 0, 0)))
+
+		allowed, err := g.Allowed(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	})
+
+	t.Run("should defer to the end of quiet hours", func(t *testing.T) {
+		g, _ := newGate(0, at(1, 23, 15))
+		next, err := g.Next(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, next.Equal(at(2, 7, 0)))
+
+		g, _ = newGate(0, at(2, 3, 0))
+		next, err = g.Next(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, next.Equal(at(2, 7, 0)))
+	})
+
+	t.Run("should defer to the next day once the cap is reached", func(t *testing.T) {
+		g, _ := newGate(3, at(1, 15, 0))
+		next, err := g.Next(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, next.Equal(at(2, 7, 0)))
+
+		allowed, err := g.Allowed(ctx, 1)
+		require.NoError(t, err)
+		assert.False(t, allowed)
+	})
+
+	t.Run("should use the channel timezone", func(t *testing.T) {
+		// 21:00 UTC is midnight in UTC+3, inside the quiet hours.
+		g, _ := newGate(0, time.Date(2026, 6, 1, 21, 0, 0, 0, time.UTC))
+		next, err := g.Next(ctx, 1)
+		require.NoError(t, err)
+		assert.True(t, next.Equal(at(2, 7, 0)))
+	})
+
+	t.Run("should apply the fallback policy to other channels", func(t *testing.T) {
+		g, _ := newGate(100, at(1, 23, 15))
+		allowed, err := g.Allowed(ctx, 2)
+		require.NoError(t, err)
+		assert.True(t, allowed)
+	})
+}
+
+func TestInQuietHours(t *testing.T) {
+	assert.True(t, InQuietHours(23, 23, 7))
+	assert.True(t, InQuietHours(3, 23, 7))
+	assert.False(t, InQuietHours(7, 23, 7))
+	assert.True(t, InQuietHours(13, 12, 14))
+	assert.False(t, InQuietHours(14, 12, 14))
+	assert.False(t, InQuietHours(3, 0, 0))
+}
+
+func TestParseQuietHours(t *testing.T) {
+	start, end, err := ParseQuietHours("23-7")
+	require.NoError(t, err)
+	assert.Equal(t, 23, start)
+	assert.Equal(t, 7, end)
+
+	for _, s := range []string{"", "23", "24-7", "a-7", "23-"} {
+		_, _, err := ParseQuietHours(s)
+		assert.Error(t, err, s)
+	}
+}
@@ -0,0 +1,81 @@
+package gate
+
+import (
+	"context"
+	"strconv"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Bot is satisfied by *tgbotapi.BotAPI.
+type Bot interface {
+	Send(c tgbotapi.Chattable) (tgbotapi.Message, error)
+	SendMediaGroup(config tgbotapi.MediaGroupConfig) ([]tgbotapi.Message, error)
+	MakeRequest(endpoint string, params tgbotapi.Params) (*tgbotapi.APIResponse, error)
+}
+
+type exemptKey struct{}
+
+// Exempt marks ctx so that a Sender posts without waiting for the gate. It
+// is meant for sends an admin asked for: test digests and reposts.
+func Exempt(ctx context.Context) context.Context {
+	return context.WithValue(ctx, exemptKey{}, true)
+}
+
+func exempt(ctx context.Context) bool {
+	v, _ := ctx.Value(exemptKey{}).(bool)
+	return v
+}
+
+// Sender is the one way channel posts reach Telegram: it waits for the gate
+// before every new message, photo or album, unless ctx is Exempt. Edits and
+// other requests pass through. It satisfies botkit.Sender.
+type Sender struct {
+	bot  Bot
+	gate *Gate
+}
+
+func NewSender(bot Bot, gate *Gate) *Sender {
+	return &Sender{bot: bot, gate: gate}
+}
+
+func (s *Sender) Send(ctx context.Context, c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	var chatID int64
+	switch c := c.(type) {
+	case tgbotapi.MessageConfig:
+		chatID = c.ChatID
+	case tgbotapi.PhotoConfig:
+		chatID = c.ChatID
+	}
+	if err := s.wait(ctx, chatID); err != nil {
+		return tgbotapi.Message{}, err
+	}
+	return s.bot.Send(c)
+}
+
+func (s *Sender) SendMediaGroup(ctx context.Context, config tgbotapi.MediaGroupConfig) ([]tgbotapi.Message, error) {
+	if err := s.wait(ctx, config.ChatID); err != nil {
+		return nil, err
+	}
+	return s.bot.SendMediaGroup(config)
+}
+
+func (s *Sender) MakeRequest(ctx context.Context, endpoint string, params tgbotapi.Params) (*tgbotapi.APIResponse, error) {
+	var chatID int64
+	switch endpoint {
+	case "sendMessage", "sendPhoto", "sendMediaGroup":
+		chatID, _ = strconv.ParseInt(params["chat_id"], 10, 64)
+	}
+	if err := s.wait(ctx, chatID); err != nil {
+		return nil, err
+	}
+	return s.bot.MakeRequest(endpoint, params)
+}
+
+// wait waits for the gate of chatID; zero means the request posts nothing.
+func (s *Sender) wait(ctx context.Context, chatID int64) error {
+	if chatID == 0 || exempt(ctx) {
+		return nil
+	}
+	return s.gate.Wait(ctx, chatID)
+}
@@ -0,0 +1,86 @@
+package gate
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBot struct {
+	sent []string
+}
+
+func (f *fakeBot) Send(tgbotapi.Chattable) (tgbotapi.Message, error) {
+	f.sent = append(f.sent, "send")
+	return tgbotapi.Message{}, nil
+}
+
+func (f *fakeBot) SendMediaGroup(tgbotapi.MediaGroupConfig) ([]tgbotapi.Message, error) {
+	f.sent = append(f.sent, "album")
+	return nil, nil
+}
+
+func (f *fakeBot) MakeRequest(endpoint string, _ tgbotapi.Params) (*tgbotapi.APIResponse, error) {
+	f.sent = append(f.sent, endpoint)
+	return &tgbotapi.APIResponse{Ok: true}, nil
+}
+
+func TestSender(t *testing.T) {
+	// The channel is in its quiet hours, so new posts are held back until
+	// the context gives up.
+	newSender := func() (*Sender, *fakeBot) {
+		g := New(&fakeCounter{}, Policy{}, map[int64]Policy{
+			1: {Location: time.UTC, QuietStart: 0, QuietEnd: 23},
+		})
+		g.now = func() time.Time { return time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC) }
+		bot := &fakeBot{}
+		return NewSender(bot, g), bot
+	}
+	held := func() (context.Context, context.CancelFunc) {
+		return context.WithTimeout(context.Background(), 10*time.Millisecond)
+	}
+
+	t.Run("should hold back new posts", func(t *testing.T) {
+		s, bot := newSender()
+
+		ctx, cancel := held()
+		defer cancel()
+		_, err := s.Send(ctx, tgbotapi.NewMessage(1, "post"))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		_, err = s.SendMediaGroup(ctx, tgbotapi.NewMediaGroup(1, nil))
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		_, err = s.MakeRequest(ctx, "sendMessage", tgbotapi.Params{"chat_id": "1"})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		assert.Empty(t, bot.sent)
+	})
+
+	t.Run("should pass edits through", func(t *testing.T) {
+		s, bot := newSender()
+
+		ctx, cancel := held()
+		defer cancel()
+		_, err := s.Send(ctx, tgbotapi.NewEditMessageText(1, 42, "edited"))
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"send"}, bot.sent)
+	})
+
+	t.Run("should post exempt sends right away", func(t *testing.T) {
+		s, bot := newSender()
+
+		ctx, cancel := held()
+		defer cancel()
+		ctx = Exempt(ctx)
+		_, err := s.Send(ctx, tgbotapi.NewMessage(1, "test digest"))
+		require.NoError(t, err)
+		_, err = s.MakeRequest(ctx, "sendMessage", tgbotapi.Params{"chat_id": "1"})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"send", "sendMessage"}, bot.sent)
+	})
+}
@@ -0,0 +1,75 @@
+// Package i18n holds the message catalog of the bot replies and picks the
+// language of each reply.
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Default is the language used for unknown languages and missing messages.
+const Default = "en"
+
+// Key names a message of the catalog.
+type Key string
+
+type languageKey struct{}
+
+// WithLanguage returns a context in which T replies in language.
+func WithLanguage(ctx context.Context, language string) context.Context {
+	return context.WithValue(ctx, languageKey{}, language)
+}
+
+// Language returns the language of ctx, or Default.
+func Language(ctx context.Context) string {
+	if language, _ := ctx.Value(languageKey{}).(string); language != "" {
+		return language
+	}
+	return Default
+}
+
+// T returns the message for key in the language of ctx, formatted with args.
+func T(ctx context.Context, key Key, args ...any) string {
+	return Text(Language(ctx), key, args...)
+}
+
+// Text returns the message for key in language, formatted with args. It
+// falls back to Default and then to the key itself.
+func Text(language string, key Key, args ...any) string {
+	msg, ok := catalog[language][key]
+	if !ok {
+		msg, ok = catalog[Default][key]
+	}
+	if !ok {
+		return string(key)
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+// Match returns the catalog language for a Telegram language_code such as
+// "ru" or "pt-BR", or "" when there is none.
+func Match(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	if base, _, ok := strings.Cut(code, "-"); ok {
+		code = base
+	}
+	if _, ok := catalog[code]; ok {
+		return code
+	}
+	return ""
+}
+
+// Languages returns the catalog languages, sorted.
+func Languages() []string {
+	languages := make([]string, 0, len(catalog))
+	for l := range catalog {
+		languages = append(languages, l)
+	}
+	sort.Strings(languages)
+	return languages
+}
@@ -0,0 +1,85 @@
+package i18n
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// declaredKeys returns the values of the Key constants in messages.go.
+func declaredKeys(t *testing.T) []Key {
+	t.Helper()
+	f, err := parser.ParseFile(token.NewFileSet(), "messages.go", nil, 0)
+	require.NoError(t, err)
+
+	var keys []Key
+	ast.Inspect(f, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		if ident, ok := spec.Type.(*ast.Ident); !ok || ident.Name != "Key" {
+			return true
+		}
+		for _, v := range spec.Values {
+			lit, ok := v.(*ast.BasicLit)
+			require.True(t, ok)
+			s, err := strconv.Unquote(lit.Value)
+			require.NoError(t, err)
+			keys = append(keys, Key(s))
+		}
+		return true
+	})
+	require.NotEmpty(t, keys)
+	return keys
+}
+
+var verbRe = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+func TestCatalog(t *testing.T) {
+	keys := declaredKeys(t)
+
+	t.Run("should have every key in every language", func(t *testing.T) {
+		for _, language := range Languages() {
+			for _, key := range keys {
+				assert.Contains(t, catalog[language], key, "%s is missing %s", language, key)
+			}
+			assert.Len(t, catalog[language], len(keys), "%s has undeclared keys", language)
+		}
+	})
+
+	t.Run("should take the same arguments in every language", func(t *testing.T) {
+		for _, language := range Languages() {
+			for _, key := range keys {
+				assert.Equal(t, verbRe.FindAllString(catalog[Default][key], -1), verbRe.FindAllString(catalog[language][key], -1),
+					"%s %s", language, key)
+			}
+		}
+	})
+}
+
+func TestT(t *testing.T) {
+	t.Run("should reply in the language of the context", func(t *testing.T) {
+		ctx := WithLanguage(context.Background(), "ru")
+		assert.Equal(t, "Публикация 7 отозвана.", T(ctx, PostWasRetracted, 7))
+	})
+
+	t.Run("should fall back to the default language", func(t *testing.T) {
+		assert.Equal(t, "Post 7 was retracted.", T(context.Background(), PostWasRetracted, 7))
+		assert.Equal(t, "Post 7 was retracted.", Text("de", PostWasRetracted, 7))
+	})
+}
+
+func TestMatch(t *testing.T) {
+	assert.Equal(t, "ru", Match("ru"))
+	assert.Equal(t, "en", Match("en-GB"))
+	assert.Equal(t, "", Match("pt-BR"))
+	assert.Equal(t, "", Match(""))
+}
@@ -0,0 +1,614 @@
+package i18n
+
+// Message keys, grouped by command. Messages marked MarkdownV2 are sent with
+// that parse mode and keep its escapes.
+const (
+	NoRights      Key = "no_rights"
+	InternalError Key = "internal_error"
+
+	AddSourceName           Key = "addsource_name"
+	AddSourceType           Key = "addsource_type"
+	AddSourceBadType        Key = "addsource_bad_type"
+	AddSourceFeedURL        Key = "addsource_feed_url"
+	AddSourceListingURL     Key = "addsource_listing_url"
+	AddSourceFeedFailed     Key = "addsource_feed_failed"
+	AddSourceLinkSelector   Key = "addsource_link_selector"
+	AddSourceBaseURL        Key = "addsource_base_url"
+	AddSourceURLUnreachable Key = "addsource_url_unreachable"
+	AddSourceURLStatus      Key = "addsource_url_status"
+	AddSourceDone           Key = "addsource_done" // MarkdownV2
+	AddSourceConfirm        Key = "addsource_confirm"
+	AddSourceSave           Key = "addsource_save"
+	AddSourceConfirmHint    Key = "addsource_confirm_hint"
+
+	SourceInfo        Key = "source_info" // MarkdownV2
+	GetSourceUsage    Key = "getsource_usage"
+	DeleteSourceDone  Key = "deletesource_done"
+	SetPriorityDone   Key = "setpriority_done"
+	FeedbackThanks    Key = "feedback_thanks"
+	FeedbackRemoved   Key = "feedback_removed"
+	EditDigestUsage   Key = "editdigest_usage"
+	EditDigestRunning Key = "editdigest_running"
+	EditDigestFailed  Key = "editdigest_failed"
+	EditDigestDone    Key = "editdigest_done"
+
+	ExplainFailed Key = "explain_failed"
+	ExplainEmpty  Key = "explain_empty"
+	ExplainHeader Key = "explain_header"
+
+	FeedbackUsage       Key = "feedback_usage"
+	FeedbackHeader      Key = "feedback_header"
+	FeedbackBySource    Key = "feedback_by_source"
+	FeedbackSourceLine  Key = "feedback_source_line"
+	FeedbackTopArticles Key = "feedback_top_articles"
+	FeedbackArticleLine Key = "feedback_article_line"
+
+	PostsUsage       Key = "posts_usage"
+	PostsEmpty       Key = "posts_empty"
+	PostsLine        Key = "posts_line"
+	PostsRetracted   Key = "posts_retracted"
+	PostsEdited      Key = "posts_edited"
+	PostNotFound     Key = "post_not_found"
+	PostWasRetracted Key = "post_was_retracted"
+
+	PromptsHeader        Key = "prompts_header"
+	PromptsLine          Key = "prompts_line"
+	PromptsHelp          Key = "prompts_help"
+	PromptDefault        Key = "prompt_default"
+	PromptUsage          Key = "prompt_usage"
+	PromptUnknown        Key = "prompt_unknown"
+	PromptInvalidVersion Key = "prompt_invalid_version"
+	PromptNoVersion      Key = "prompt_no_version"
+	PromptHeaderDefault  Key = "prompt_header_default"
+	PromptHeaderVersion  Key = "prompt_header_version"
+	PromptActive         Key = "prompt_active"
+
+	RepostRunning Key = "repost_running"
+	RepostFailed  Key = "repost_failed"
+	RepostDone    Key = "repost_done"
+
+	RetractUsage       Key = "retract_usage"
+	RetractNoMessageID Key = "retract_no_message_id"
+	RetractDone        Key = "retract_done"
+	RetractUndeleted   Key = "retract_undeleted"
+
+	RollbackUsage   Key = "rollback_usage"
+	RollbackFailed  Key = "rollback_failed"
+	RollbackDone    Key = "rollback_done"
+	RollbackDefault Key = "rollback_default"
+
+	SetPromptUsage   Key = "setprompt_usage"
+	SetPromptAsk     Key = "setprompt_ask"
+	SetPromptInvalid Key = "setprompt_invalid"
+	SetPromptDone    Key = "setprompt_done"
+
+	TestDigestRunning Key = "testdigest_running"
+	TestDigestFailed  Key = "testdigest_failed"
+	TestDigestDone    Key = "testdigest_done"
+	TestNewsRunning   Key = "testnews_running"
+	TestNewsFailed    Key = "testnews_failed"
+	TestNewsDone      Key = "testnews_done"
+	TestRecapRunning  Key = "testrecap_running"
+	TestRecapFailed   Key = "testrecap_failed"
+	TestRecapDone     Key = "testrecap_done"
+
+	LanguageUsage   Key = "language_usage"
+	LanguageCurrent Key = "language_current"
+	LanguageSet     Key = "language_set"
+	LanguageAuto    Key = "language_auto"
+
+	ConversationNone         Key = "conversation_none"
+	ConversationCancelled    Key = "conversation_cancelled"
+	ConversationExpired      Key = "conversation_expired"
+	ConversationFirstStep    Key = "conversation_first_step"
+	ConversationBackButton   Key = "conversation_back_button"
+	ConversationCancelButton Key = "conversation_cancel_button"
+	ConversationEmptyInput   Key = "conversation_empty_input"
+
+	SourcesPage            Key = "sources_page"
+	SourcesEmpty           Key = "sources_empty"
+	SourceDetails          Key = "source_details"
+	SourceDetailsWeb       Key = "source_details_web"
+	SourceActive           Key = "source_active"
+	SourcePaused           Key = "source_paused"
+	SourceNotFound         Key = "source_not_found"
+	SourceButtonUp         Key = "source_button_up"
+	SourceButtonDown       Key = "source_button_down"
+	SourceButtonPause      Key = "source_button_pause"
+	SourceButtonResume     Key = "source_button_resume"
+	SourceButtonTest       Key = "source_button_test"
+	SourceButtonEdit       Key = "source_button_edit"
+	SourceButtonDelete     Key = "source_button_delete"
+	SourceButtonDeleteYes  Key = "source_button_delete_yes"
+	SourceButtonKeep       Key = "source_button_keep"
+	SourceButtonList       Key = "source_button_list"
+	PageButtonPrev         Key = "page_button_prev"
+	PageButtonNext         Key = "page_button_next"
+	SourceDeleteConfirm    Key = "source_delete_confirm"
+	SourceDeleted          Key = "source_deleted"
+	SourceFetching         Key = "source_fetching"
+	SourceTestResult       Key = "source_test_result"
+	SourceTestFailed       Key = "source_test_failed"
+	EditSourceField        Key = "editsource_field"
+	EditSourceFieldName    Key = "editsource_field_name"
+	EditSourceFieldURL     Key = "editsource_field_url"
+	EditSourceFieldSel     Key = "editsource_field_selector"
+	EditSourceFieldBaseURL Key = "editsource_field_base_url"
+	EditSourceFieldTLS     Key = "editsource_field_insecure"
+	EditSourceBadField     Key = "editsource_bad_field"
+	EditSourceValue        Key = "editsource_value"
+	EditSourceInsecure     Key = "editsource_insecure"
+	EditSourceBadBool      Key = "editsource_bad_bool"
+	EditSourceDone         Key = "editsource_done"
+	EditSourceUsage        Key = "editsource_usage"
+	EditSourceProbeFailed  Key = "editsource_probe_failed"
+	EditSourceProbeEmpty   Key = "editsource_probe_empty"
+	Yes                    Key = "yes"
+	No                     Key = "no"
+
+	SourcePausedUntil    Key = "source_paused_until"
+	PauseSourceUsage     Key = "pausesource_usage"
+	PauseSourceDone      Key = "pausesource_done"
+	PauseSourceDoneUntil Key = "pausesource_done_until"
+	ResumeSourceUsage    Key = "resumesource_usage"
+	ResumeSourceDone     Key = "resumesource_done"
+	RestoreSourceUsage   Key = "restoresource_usage"
+	RestoreSourceList    Key = "restoresource_list"
+	RestoreSourceNone    Key = "restoresource_none"
+	RestoreSourceDone    Key = "restoresource_done"
+	RestoreSourceNotGone Key = "restoresource_not_deleted"
+
+	PreviewSourceUsage     Key = "previewsource_usage"
+	PreviewSourceHeader    Key = "previewsource_header"
+	PreviewSourceSkipped   Key = "previewsource_skipped"
+	PreviewSourceMore      Key = "previewsource_more"
+	PreviewFieldCategory   Key = "previewsource_field_category"
+	PreviewFieldTitle      Key = "previewsource_field_title"
+	PreviewSourceAdHocName Key = "previewsource_adhoc_name"
+
+	SearchUsage         Key = "search_usage"
+	SearchHeader        Key = "search_header"
+	SearchNoResults     Key = "search_no_results"
+	SearchUnknownSource Key = "search_unknown_source"
+	SearchBadDate       Key = "search_bad_date"
+
+	SubscribePrivateOnly Key = "subscribe_private_only"
+	SubscribeKeywords    Key = "subscribe_keywords"
+	SubscribeCategories  Key = "subscribe_categories"
+	SubscribeSources     Key = "subscribe_sources"
+	SubscribeSkip        Key = "subscribe_skip"
+	SubscribeNothing     Key = "subscribe_nothing"
+	SubscribeSchedule    Key = "subscribe_schedule"
+	SubscribeDaily       Key = "subscribe_daily"
+	SubscribeWeekly      Key = "subscribe_weekly"
+	SubscribeBadChoice   Key = "subscribe_bad_choice"
+	SubscribeTimezone    Key = "subscribe_timezone"
+	SubscribeBadTimezone Key = "subscribe_bad_timezone"
+	SubscribeHour        Key = "subscribe_hour"
+	SubscribeBadHour     Key = "subscribe_bad_hour"
+	SubscribeDone        Key = "subscribe_done"
+	SubscribeLimit       Key = "subscribe_limit"
+	MySubsEmpty          Key = "mysubs_empty"
+	MySubsHeader         Key = "mysubs_header"
+	MySubsLine           Key = "mysubs_line"
+	SubsKeywords         Key = "subs_keywords"
+	SubsCategories       Key = "subs_categories"
+	SubsSources          Key = "subs_sources"
+	UnsubscribeUsage     Key = "unsubscribe_usage"
+	UnsubscribeDone      Key = "unsubscribe_done"
+	UnsubscribeAllDone   Key = "unsubscribe_all_done"
+	UnsubscribeNotFound  Key = "unsubscribe_not_found"
+	PersonalDigestHeader Key = "personal_digest_header"
+	PersonalDigestFooter Key = "personal_digest_footer"
+
+	// Breaking news posts, in the channel language.
+	BreakingHeader      Key = "breaking_header"
+	BreakingAlsoCovered Key = "breaking_also_covered"
+)
+
+var catalog = map[string]map[Key]string{
+	"en": {
+		NoRights:      "You are not allowed to run this command.",
+		InternalError: "Internal error",
+
+		AddSourceName:           "Enter the source name:",
+		AddSourceType:           "Choose the source type: rss or web",
+		AddSourceBadType:        "Invalid type %q. Enter rss or web:",
+		AddSourceFeedURL:        "Enter the RSS feed URL:",
+		AddSourceListingURL:     "Enter the listing page URL (e.g. https://platformengineering.org/blog):",
+		AddSourceFeedFailed:     "Could not fetch a feed from this URL: %v\n\nEnter another URL or /cancel.",
+		AddSourceLinkSelector:   "Enter the CSS selector for links (e.g. a[href^='/blog/']):",
+		AddSourceBaseURL:        "Enter the base URL (e.g. https://platformengineering.org):",
+		AddSourceURLUnreachable: "URL is unreachable: %v\n\nEnter another URL or /cancel.",
+		AddSourceURLStatus:      "URL returned status %d\n\nEnter another URL or /cancel.",
+		AddSourceDone:           "Source added with ID: `%d`\\. Use this ID to update or delete the source\\.",
+		AddSourceConfirm:        "Save this source?",
+		AddSourceSave:           "Save",
+		AddSourceConfirmHint:    "Press Save, /back to change the answers, or /cancel.",
+
+		SourceInfo:        "🌐 *%s*\nID: `%d`\nFeed URL: %s\nPriority: %d",
+		GetSourceUsage:    "Usage: /getsource <source_id>",
+		DeleteSourceDone:  "Source deleted. Its articles are kept; /restoresource brings it back.",
+		SetPriorityDone:   "Priority updated",
+		FeedbackThanks:    "Thanks for the feedback!",
+		FeedbackRemoved:   "Vote removed",
+		EditDigestUsage:   "Usage: /editdigest <post_id>",
+		EditDigestRunning: "Regenerating digest %d…",
+		EditDigestFailed:  "Regeneration failed: %v",
+		EditDigestDone:    "Digest %d edited.",
+
+		ExplainFailed: "Ranking failed: %v",
+		ExplainEmpty:  "No unposted articles.",
+		ExplainHeader: "%d candidates, %d picked:\n",
+
+		FeedbackUsage:       "Usage: /feedback [days]",
+		FeedbackHeader:      "Feedback for the last %d days: 👍 %d, 👎 %d, more like this %d\n",
+		FeedbackBySource:    "By source:",
+		FeedbackSourceLine:  "• %s — 👍 %d, 👎 %d, more %d",
+		FeedbackTopArticles: "Top articles:",
+		FeedbackArticleLine: "%d. %s — 👍 %d, 👎 %d, more %d\n%s",
+
+		PostsUsage:       "Usage: /posts [count]",
+		PostsEmpty:       "No posts yet.",
+		PostsLine:        "#%d %s %s to %d, %d articles",
+		PostsRetracted:   ", retracted",
+		PostsEdited:      ", edited",
+		PostNotFound:     "Post %d not found. See /posts.",
+		PostWasRetracted: "Post %d was retracted.",
+
+		PromptsHeader:        "Prompt templates:\n\n",
+		PromptsLine:          "• %s — %s (%d saved)\n",
+		PromptsHelp:          "\n/prompt <name> [version] shows a template, /setprompt <name> saves a new version, /rollbackprompt <name> [version] activates an older one.",
+		PromptDefault:        "default",
+		PromptUsage:          "Usage: /prompt <name> [version]",
+		PromptUnknown:        "Unknown prompt %q. See /prompts.",
+		PromptInvalidVersion: "Invalid version %q",
+		PromptNoVersion:      "Prompt %s has no version %d",
+		PromptHeaderDefault:  "%s — default",
+		PromptHeaderVersion:  "%s — v%d, saved %s",
+		PromptActive:         ", active",
+
+		RepostRunning: "Reposting news digest to the channel…",
+		RepostFailed:  "Repost failed: %v",
+		RepostDone:    "News digest reposted.",
+
+		RetractUsage:       "Usage: /retractpost <post_id>",
+		RetractNoMessageID: "Post %d was sent before message IDs were recorded; delete it by hand.",
+		RetractDone:        "Post %d retracted, %d articles returned to the queue.",
+		RetractUndeleted:   " %d messages could not be deleted; remove them by hand.",
+
+		RollbackUsage:   "Usage: /rollbackprompt <name> [version]",
+		RollbackFailed:  "Rollback failed: %v",
+		RollbackDone:    "%s now uses v%d.",
+		RollbackDefault: "%s now uses the default template.",
+
+		SetPromptUsage:   "Usage: /setprompt <name>",
+		SetPromptAsk:     "Send the new template for %s. Go text/template syntax; fields: .Slot, .Channel, .Language, .Articles, .Groups, .MaxDataLen, .Topic, .NewRepos, .Trending.",
+		SetPromptInvalid: "Template error: %v\n\nSend a corrected template or any command to cancel.",
+		SetPromptDone:    "Saved %s v%d and made it active.",
+
+		TestDigestRunning: "Running test digest, please wait…",
+		TestDigestFailed:  "Test digest failed: %v",
+		TestDigestDone:    "Test digest sent.",
+		TestNewsRunning:   "Running test news digest, please wait…",
+		TestNewsFailed:    "Test news digest failed: %v",
+		TestNewsDone:      "Test news digest sent.",
+		TestRecapRunning:  "Running test weekly recap, please wait…",
+		TestRecapFailed:   "Test weekly recap failed: %v",
+		TestRecapDone:     "Test weekly recap sent.",
+
+		LanguageUsage:   "Usage: /language [%s|auto]",
+		LanguageCurrent: "Replies are in %s. Available: %s. /language auto follows your Telegram language.",
+		LanguageSet:     "Replies will be in English.",
+		LanguageAuto:    "Replies will follow your Telegram language.",
+
+		ConversationNone:         "There is nothing in progress.",
+		ConversationCancelled:    "Cancelled.",
+		ConversationExpired:      "The dialog timed out. Start over with the command.",
+		ConversationFirstStep:    "This is the first step; /cancel to leave.",
+		ConversationBackButton:   "« Back",
+		ConversationCancelButton: "Cancel",
+		ConversationEmptyInput:   "Send the answer as text.",
+
+		SourcesPage:            "Sources (%d total), page %d of %d:",
+		SourcesEmpty:           "No sources yet. Add one with /addsource.",
+		SourceDetails:          "%s\nID: %d\nType: %s\nURL: %s\nPriority: %d\nStatus: %s",
+		SourceDetailsWeb:       "\nLink selector: %s\nBase URL: %s",
+		SourceActive:           "active",
+		SourcePaused:           "paused",
+		SourceNotFound:         "Source %d not found.",
+		SourceButtonUp:         "⬆ Priority",
+		SourceButtonDown:       "⬇ Priority",
+		SourceButtonPause:      "⏸ Pause",
+		SourceButtonResume:     "▶ Resume",
+		SourceButtonTest:       "🧪 Test fetch",
+		SourceButtonEdit:       "✏ Edit",
+		SourceButtonDelete:     "🗑 Delete",
+		SourceButtonDeleteYes:  "Yes, delete",
+		SourceButtonKeep:       "No, keep it",
+		SourceButtonList:       "« Sources",
+		PageButtonPrev:         "‹ Prev",
+		PageButtonNext:         "Next ›",
+		SourceDeleteConfirm:    "Delete %s? Its articles are kept and /restoresource brings it back.",
+		SourceDeleted:          "Source %s deleted.",
+		SourceFetching:         "Fetching…",
+		SourceTestResult:       "%s: %d items fetched.",
+		SourceTestFailed:       "Fetch of %s failed: %v",
+		EditSourceField:        "What should change in %s?",
+		EditSourceFieldName:    "Name",
+		EditSourceFieldURL:     "URL",
+		EditSourceFieldSel:     "Link selector",
+		EditSourceFieldBaseURL: "Base URL",
+		EditSourceFieldTLS:     "TLS check",
+		EditSourceBadField:     "Pick one of the buttons.",
+		EditSourceValue:        "New value for «%s»:",
+		EditSourceInsecure:     "Skip TLS certificate checks for this source?",
+		EditSourceBadBool:      "Answer yes or no.",
+		EditSourceDone:         "Source %d updated.",
+		EditSourceUsage:        "Usage: /editsource <source_id>",
+		EditSourceProbeFailed:  "The source fails to fetch with this value: %v\nSend another value or /cancel.",
+		EditSourceProbeEmpty:   "Nothing was fetched with this value. Send another value or /cancel.",
+		Yes:                    "Yes",
+		No:                     "No",
+
+		SourcePausedUntil:    "paused until %s",
+		PauseSourceUsage:     "Usage: /pausesource <source_id> [duration, e.g. 12h]",
+		PauseSourceDone:      "Source %s paused until /resumesource.",
+		PauseSourceDoneUntil: "Source %s paused until %s.",
+		ResumeSourceUsage:    "Usage: /resumesource <source_id>",
+		ResumeSourceDone:     "Source %s resumed.",
+		RestoreSourceUsage:   "Usage: /restoresource [source_id]",
+		RestoreSourceList:    "Deleted sources:\n%s\n\nRestore one with /restoresource <source_id>.",
+		RestoreSourceNone:    "There are no deleted sources.",
+		RestoreSourceDone:    "Source %s restored.",
+		RestoreSourceNotGone: "Source %d is not deleted.",
+
+		PreviewSourceUsage:     "Usage: /previewsource <source_id>, /previewsource <feed_url> or /previewsource {\"url\": \"...\", \"type\": \"web\", \"link_selector\": \"...\", \"base_url\": \"...\"}",
+		PreviewSourceHeader:    "%s: %d items fetched, %d would be skipped. Nothing was stored.",
+		PreviewSourceSkipped:   "⛔ skipped: %s matches %q",
+		PreviewSourceMore:      "…and %d more.",
+		PreviewFieldCategory:   "category",
+		PreviewFieldTitle:      "title",
+		PreviewSourceAdHocName: "Preview",
+
+		SearchUsage:         "Usage: /search <words> [source:<id|name>] [category:<name>] [from:YYYY-MM-DD] [to:YYYY-MM-DD]\nUse \"quotes\" for phrases, OR for alternatives and -word to exclude.",
+		SearchHeader:        "Found %d, page %d of %d:",
+		SearchNoResults:     "Nothing found.",
+		SearchUnknownSource: "Unknown source %q.",
+		SearchBadDate:       "Invalid date %q, use YYYY-MM-DD.",
+
+		SubscribePrivateOnly: "Send /subscribe to me in a private chat, where your digests will arrive.",
+		SubscribeKeywords:    "Send the keywords to follow, separated by commas, or skip:",
+		SubscribeCategories:  "Send the categories to follow, separated by commas, or skip:",
+		SubscribeSources:     "Send the sources to follow by ID or name, separated by commas, or skip:",
+		SubscribeSkip:        "Skip",
+		SubscribeNothing:     "Give at least one keyword, category or source.",
+		SubscribeSchedule:    "How often should the digest come?",
+		SubscribeDaily:       "Daily",
+		SubscribeWeekly:      "Weekly, on Mondays",
+		SubscribeBadChoice:   "Pick one of the buttons.",
+		SubscribeTimezone:    "Your timezone? Pick one or send an IANA name like Europe/Berlin:",
+		SubscribeBadTimezone: "Unknown timezone %q. Send an IANA name like Europe/Berlin or UTC.",
+		SubscribeHour:        "At what hour (0-23, %s)?",
+		SubscribeBadHour:     "Send an hour from 0 to 23.",
+		SubscribeDone:        "Subscribed (#%d). See your subscriptions with /mysubs.",
+		SubscribeLimit:       "You already have %d subscriptions, the most allowed. Remove one with /unsubscribe first.",
+		MySubsEmpty:          "You have no subscriptions. Start one with /subscribe.",
+		MySubsHeader:         "Your subscriptions:",
+		MySubsLine:           "#%d · %s at %02d:00 %s\n%s",
+		SubsKeywords:         "keywords: %s",
+		SubsCategories:       "categories: %s",
+		SubsSources:          "sources: %s",
+		UnsubscribeUsage:     "Usage: /unsubscribe <subscription_id|all>",
+		UnsubscribeDone:      "Subscription #%d removed.",
+		UnsubscribeAllDone:   "Removed %d subscriptions.",
+		UnsubscribeNotFound:  "You have no subscription #%d.",
+		PersonalDigestHeader: "Your digest — new articles: %d",
+		PersonalDigestFooter: "Subscription #%d · /mysubs · /unsubscribe",
+
+		BreakingHeader:      "Breaking:",
+		BreakingAlsoCovered: "Also covered by:",
+	},
+	"ru": {
+		NoRights:      "У вас нет прав на выполнение этой команды.",
+		InternalError: "Внутренняя ошибка",
+
+		AddSourceName:           "Введите название источника:",
+		AddSourceType:           "Выберите тип источника: rss или web",
+		AddSourceBadType:        "Неверный тип %q. Введите rss или web:",
+		AddSourceFeedURL:        "Введите URL RSS-фида:",
+		AddSourceListingURL:     "Введите URL страницы-листинга (например, https://platformengineering.org/blog):",
+		AddSourceFeedFailed:     "Не удалось получить фид по указанному URL: %v\n\nВведите другой URL или /cancel для отмены.",
+		AddSourceLinkSelector:   "Введите CSS-селектор для ссылок (например, a[href^='/blog/']):",
+		AddSourceBaseURL:        "Введите базовый URL (например, https://platformengineering.org):",
+		AddSourceURLUnreachable: "URL недоступен: %v\n\nВведите другой URL или /cancel для отмены.",
+		AddSourceURLStatus:      "URL вернул статус %d\n\nВведите другой URL или /cancel для отмены.",
+		AddSourceDone:           "Источник добавлен с ID: `%d`\\. Используйте этот ID для обновления источника или удаления\\.",
+		AddSourceConfirm:        "Сохранить источник?",
+		AddSourceSave:           "Сохранить",
+		AddSourceConfirmHint:    "Нажмите «Сохранить», /back, чтобы изменить ответы, или /cancel.",
+
+		SourceInfo:        "🌐 *%s*\nID: `%d`\nURL фида: %s\nПриоритет: %d",
+		GetSourceUsage:    "Использование: /getsource <source_id>",
+		DeleteSourceDone:  "Источник удален. Его статьи сохранены; вернуть его можно через /restoresource.",
+		SetPriorityDone:   "Приоритет успешно обновлен",
+		FeedbackThanks:    "Спасибо за отзыв!",
+		FeedbackRemoved:   "Голос отменен",
+		EditDigestUsage:   "Использование: /editdigest <post_id>",
+		EditDigestRunning: "Пересоздаю дайджест %d…",
+		EditDigestFailed:  "Не удалось пересоздать дайджест: %v",
+		EditDigestDone:    "Дайджест %d изменен.",
+
+		ExplainFailed: "Не удалось ранжировать статьи: %v",
+		ExplainEmpty:  "Неопубликованных статей нет.",
+		ExplainHeader: "Кандидатов: %d, выбрано: %d\n",
+
+		FeedbackUsage:       "Использование: /feedback [дней]",
+		FeedbackHeader:      "Отзывы за последние %d дн.: 👍 %d, 👎 %d, «больше такого» %d\n",
+		FeedbackBySource:    "По источникам:",
+		FeedbackSourceLine:  "• %s — 👍 %d, 👎 %d, больше %d",
+		FeedbackTopArticles: "Лучшие статьи:",
+		FeedbackArticleLine: "%d. %s — 👍 %d, 👎 %d, больше %d\n%s",
+
+		PostsUsage:       "Использование: /posts [количество]",
+		PostsEmpty:       "Публикаций пока нет.",
+		PostsLine:        "#%d %s %s в %d, статей: %d",
+		PostsRetracted:   ", отозван",
+		PostsEdited:      ", изменен",
+		PostNotFound:     "Публикация %d не найдена. См. /posts.",
+		PostWasRetracted: "Публикация %d отозвана.",
+
+		PromptsHeader:        "Шаблоны промптов:\n\n",
+		PromptsLine:          "• %s — %s (сохранено: %d)\n",
+		PromptsHelp:          "\n/prompt <name> [version] показывает шаблон, /setprompt <name> сохраняет новую версию, /rollbackprompt <name> [version] включает старую.",
+		PromptDefault:        "по умолчанию",
+		PromptUsage:          "Использование: /prompt <name> [version]",
+		PromptUnknown:        "Неизвестный промпт %q. См. /prompts.",
+		PromptInvalidVersion: "Неверная версия %q",
+		PromptNoVersion:      "У промпта %s нет версии %d",
+		PromptHeaderDefault:  "%s — по умолчанию",
+		PromptHeaderVersion:  "%s — v%d, сохранен %s",
+		PromptActive:         ", активен",
+
+		RepostRunning: "Публикую дайджест новостей в канал заново…",
+		RepostFailed:  "Не удалось опубликовать: %v",
+		RepostDone:    "Дайджест новостей опубликован.",
+
+		RetractUsage:       "Использование: /retractpost <post_id>",
+		RetractNoMessageID: "Публикация %d отправлена до того, как начали сохраняться ID сообщений; удалите ее вручную.",
+		RetractDone:        "Публикация %d отозвана, статей возвращено в очередь: %d.",
+		RetractUndeleted:   " Не удалось удалить сообщений: %d; удалите их вручную.",
+
+		RollbackUsage:   "Использование: /rollbackprompt <name> [version]",
+		RollbackFailed:  "Не удалось откатить: %v",
+		RollbackDone:    "%s теперь использует v%d.",
+		RollbackDefault: "%s теперь использует шаблон по умолчанию.",
+
+		SetPromptUsage:   "Использование: /setprompt <name>",
+		SetPromptAsk:     "Отправьте новый шаблон для %s. Синтаксис Go text/template; поля: .Slot, .Channel, .Language, .Articles, .Groups, .MaxDataLen, .Topic, .NewRepos, .Trending.",
+		SetPromptInvalid: "Ошибка в шаблоне: %v\n\nОтправьте исправленный шаблон или любую команду для отмены.",
+		SetPromptDone:    "%s v%d сохранен и включен.",
+
+		TestDigestRunning: "Запускаю тестовый дайджест, подождите…",
+		TestDigestFailed:  "Тестовый дайджест не удался: %v",
+		TestDigestDone:    "Тестовый дайджест отправлен.",
+		TestNewsRunning:   "Запускаю тестовый дайджест новостей, подождите…",
+		TestNewsFailed:    "Тестовый дайджест новостей не удался: %v",
+		TestNewsDone:      "Тестовый дайджест новостей отправлен.",
+		TestRecapRunning:  "Запускаю тестовый недельный обзор, подождите…",
+		TestRecapFailed:   "Тестовый недельный обзор не удался: %v",
+		TestRecapDone:     "Тестовый недельный обзор отправлен.",
+
+		LanguageUsage:   "Использование: /language [%s|auto]",
+		LanguageCurrent: "Язык ответов: %s. Доступны: %s. /language auto — язык из настроек Telegram.",
+		LanguageSet:     "Ответы будут на русском.",
+		LanguageAuto:    "Ответы будут на языке из настроек Telegram.",
+
+		ConversationNone:         "Сейчас ничего не выполняется.",
+		ConversationCancelled:    "Отменено.",
+		ConversationExpired:      "Время диалога истекло. Начните заново с команды.",
+		ConversationFirstStep:    "Это первый шаг; /cancel — выйти.",
+		ConversationBackButton:   "« Назад",
+		ConversationCancelButton: "Отмена",
+		ConversationEmptyInput:   "Отправьте ответ текстом.",
+
+		SourcesPage:            "Источники (всего %d), страница %d из %d:",
+		SourcesEmpty:           "Источников пока нет. Добавьте первый командой /addsource.",
+		SourceDetails:          "%s\nID: %d\nТип: %s\nURL: %s\nПриоритет: %d\nСтатус: %s",
+		SourceDetailsWeb:       "\nСелектор ссылок: %s\nБазовый URL: %s",
+		SourceActive:           "активен",
+		SourcePaused:           "на паузе",
+		SourceNotFound:         "Источник %d не найден.",
+		SourceButtonUp:         "⬆ Приоритет",
+		SourceButtonDown:       "⬇ Приоритет",
+		SourceButtonPause:      "⏸ Пауза",
+		SourceButtonResume:     "▶ Возобновить",
+		SourceButtonTest:       "🧪 Тестовая загрузка",
+		SourceButtonEdit:       "✏ Изменить",
+		SourceButtonDelete:     "🗑 Удалить",
+		SourceButtonDeleteYes:  "Да, удалить",
+		SourceButtonKeep:       "Нет, оставить",
+		SourceButtonList:       "« Источники",
+		PageButtonPrev:         "‹ Назад",
+		PageButtonNext:         "Дальше ›",
+		SourceDeleteConfirm:    "Удалить %s? Статьи сохранятся, а вернуть источник можно через /restoresource.",
+		SourceDeleted:          "Источник %s удален.",
+		SourceFetching:         "Загружаю…",
+		SourceTestResult:       "%s: загружено записей: %d.",
+		SourceTestFailed:       "Не удалось загрузить %s: %v",
+		EditSourceField:        "Что изменить в %s?",
+		EditSourceFieldName:    "Название",
+		EditSourceFieldURL:     "URL",
+		EditSourceFieldSel:     "Селектор ссылок",
+		EditSourceFieldBaseURL: "Базовый URL",
+		EditSourceFieldTLS:     "Проверка TLS",
+		EditSourceBadField:     "Выберите одну из кнопок.",
+		EditSourceValue:        "Новое значение для «%s»:",
+		EditSourceInsecure:     "Не проверять TLS-сертификат этого источника?",
+		EditSourceBadBool:      "Ответьте да или нет.",
+		EditSourceDone:         "Источник %d обновлен.",
+		EditSourceUsage:        "Использование: /editsource <source_id>",
+		EditSourceProbeFailed:  "С этим значением источник не загружается: %v\nОтправьте другое значение или /cancel.",
+		EditSourceProbeEmpty:   "С этим значением ничего не загрузилось. Отправьте другое значение или /cancel.",
+		Yes:                    "Да",
+		No:                     "Нет",
+
+		SourcePausedUntil:    "на паузе до %s",
+		PauseSourceUsage:     "Использование: /pausesource <source_id> [длительность, например 12h]",
+		PauseSourceDone:      "Источник %s на паузе до /resumesource.",
+		PauseSourceDoneUntil: "Источник %s на паузе до %s.",
+		ResumeSourceUsage:    "Использование: /resumesource <source_id>",
+		ResumeSourceDone:     "Источник %s снова загружается.",
+		RestoreSourceUsage:   "Использование: /restoresource [source_id]",
+		RestoreSourceList:    "Удаленные источники:\n%s\n\nВосстановить: /restoresource <source_id>.",
+		RestoreSourceNone:    "Удаленных источников нет.",
+		RestoreSourceDone:    "Источник %s восстановлен.",
+		RestoreSourceNotGone: "Источник %d не удален.",
+
+		PreviewSourceUsage:     "Использование: /previewsource <source_id>, /previewsource <feed_url> или /previewsource {\"url\": \"...\", \"type\": \"web\", \"link_selector\": \"...\", \"base_url\": \"...\"}",
+		PreviewSourceHeader:    "%s: загружено %d, будет пропущено %d. Ничего не сохранено.",
+		PreviewSourceSkipped:   "⛔ пропуск: %s совпадает с %q",
+		PreviewSourceMore:      "…и еще %d.",
+		PreviewFieldCategory:   "категория",
+		PreviewFieldTitle:      "заголовок",
+		PreviewSourceAdHocName: "Предпросмотр",
+
+		SearchUsage:         "Использование: /search <слова> [source:<id|название>] [category:<название>] [from:ГГГГ-ММ-ДД] [to:ГГГГ-ММ-ДД]\nФразы берите в \"кавычки\", OR — для альтернатив, -слово — чтобы исключить.",
+		SearchHeader:        "Найдено %d, страница %d из %d:",
+		SearchNoResults:     "Ничего не найдено.",
+		SearchUnknownSource: "Неизвестный источник %q.",
+		SearchBadDate:       "Неверная дата %q, используйте ГГГГ-ММ-ДД.",
+
+		SubscribePrivateOnly: "Отправьте /subscribe мне в личные сообщения — туда будут приходить дайджесты.",
+		SubscribeKeywords:    "Отправьте ключевые слова через запятую или пропустите шаг:",
+		SubscribeCategories:  "Отправьте категории через запятую или пропустите шаг:",
+		SubscribeSources:     "Отправьте источники (ID или названия) через запятую или пропустите шаг:",
+		SubscribeSkip:        "Пропустить",
+		SubscribeNothing:     "Укажите хотя бы одно ключевое слово, категорию или источник.",
+		SubscribeSchedule:    "Как часто присылать дайджест?",
+		SubscribeDaily:       "Каждый день",
+		SubscribeWeekly:      "Раз в неделю, по понедельникам",
+		SubscribeBadChoice:   "Выберите одну из кнопок.",
+		SubscribeTimezone:    "Ваш часовой пояс? Выберите или отправьте название IANA, например Europe/Moscow:",
+		SubscribeBadTimezone: "Неизвестный часовой пояс %q. Отправьте название IANA, например Europe/Moscow или UTC.",
+		SubscribeHour:        "В котором часу (0-23, %s)?",
+		SubscribeBadHour:     "Отправьте час от 0 до 23.",
+		SubscribeDone:        "Подписка оформлена (#%d). Ваши подписки: /mysubs.",
+		SubscribeLimit:       "У вас уже %d подписок — это максимум. Сначала удалите одну через /unsubscribe.",
+		MySubsEmpty:          "У вас нет подписок. Оформить: /subscribe.",
+		MySubsHeader:         "Ваши подписки:",
+		MySubsLine:           "#%d · %s в %02d:00 %s\n%s",
+		SubsKeywords:         "ключевые слова: %s",
+		SubsCategories:       "категории: %s",
+		SubsSources:          "источники: %s",
+		UnsubscribeUsage:     "Использование: /unsubscribe <subscription_id|all>",
+		UnsubscribeDone:      "Подписка #%d удалена.",
+		UnsubscribeAllDone:   "Удалено подписок: %d.",
+		UnsubscribeNotFound:  "У вас нет подписки #%d.",
+		PersonalDigestHeader: "Ваш дайджест: новых статей — %d",
+		PersonalDigestFooter: "Подписка #%d · /mysubs · /unsubscribe",
+
+		BreakingHeader:      "Срочно:",
+		BreakingAlsoCovered: "Также пишут:",
+	},
+}
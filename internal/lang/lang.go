@@ -0,0 +1,43 @@
+// Package lang guesses the language of article text.
+package lang
+
+import "unicode"
+
+// Language codes Detect returns.
+const (
+	English = "en"
+	Russian = "ru"
+)
+
+// minLetters is how many letters Detect needs to tell a language.
+const minLetters = 3
+
+// Detect guesses the language of text from its script: Russian when most
+// letters are Cyrillic, English when most are Latin. It returns "" when
+// there are too few letters to tell or another script dominates.
+func Detect(text string) string {
+	var cyrillic, latin, other int
+	for _, r := range text {
+		switch {
+		case !unicode.IsLetter(r):
+		case unicode.Is(unicode.Cyrillic, r):
+			cyrillic++
+		case unicode.Is(unicode.Latin, r):
+			latin++
+		default:
+			other++
+		}
+	}
+
+	total := cyrillic + latin + other
+	switch {
+	case total < minLetters:
+		return ""
+	case cyrillic*2 > total:
+		return Russian
+	case latin*2 > total:
+		return English
+	default:
+		return ""
+	}
+}
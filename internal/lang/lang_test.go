@@ -0,0 +1,15 @@
+package lang
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	assert.Equal(t, English, Detect("Go 1.26 released with generic methods"))
+	assert.Equal(t, Russian, Detect("Вышел Go 1.26 с обобщёнными методами"))
+	assert.Equal(t, Russian, Detect("Релиз Kubernetes 2.0: что нового"))
+	assert.Equal(t, "", Detect("1.26"))
+	assert.Equal(t, "", Detect("東京でイベント開催"))
+}
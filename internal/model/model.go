@@ -11,6 +11,8 @@ type Item struct {
 	Date       time.Time
 	Summary    string
 	SourceName string
+	// ImageURL is the lead image from the feed or the article page, if any.
+	ImageURL string
 }
 
 // ScraperConfig holds CSS-selector-based configuration for web scraping sources.
@@ -39,17 +41,222 @@ type Source struct {
 	Insecure      bool
 	SourceType    string
 	ScraperConfig *ScraperConfig
-	CreatedAt     time.Time
+	// Enabled is false while the source is paused; the fetcher skips it.
+	Enabled bool
+	// PausedUntil, if set, pauses the source until then.
+	PausedUntil time.Time
+	// DeletedAt is set once the source is deleted. Its articles are kept and
+	// it can be restored.
+	DeletedAt time.Time
+	CreatedAt time.Time
+}
+
+// Paused reports whether the fetcher skips the source at now.
+func (s Source) Paused(now time.Time) bool {
+	return !s.Enabled || now.Before(s.PausedUntil)
 }
 
 type Article struct {
-	ID          int64
-	SourceID    int64
-	Title       string
-	Link        string
-	Summary     string
-	Categories  []string
+	ID             int64
+	SourceID       int64
+	SourceName     string
+	SourcePriority int
+	Title          string
+	Link           string
+	// Summary is the full content from the feed when it carries one, and
+	// the feed summary otherwise.
+	Summary    string
+	Categories []string
+	ImageURL   string
+	// Language is the detected language code of the title and summary, or
+	// empty when it could not be told.
+	Language    string
 	PublishedAt time.Time
 	PostedAt    time.Time
 	CreatedAt   time.Time
 }
+
+// ArticleSearch is a full-text search over articles. Query uses web search
+// syntax: words, "quoted phrases", OR and -excluded. Zero fields do not
+// filter; an empty Query lists the matching articles newest first.
+type ArticleSearch struct {
+	Query string
+	// From and To bound the publication time, To exclusive.
+	From time.Time
+	To   time.Time
+	// FetchedFrom bounds the time the article was stored, which is later
+	// than its publication for feeds that list articles late.
+	FetchedFrom time.Time
+	SourceIDs   []int64
+	Categories  []string
+	// LiveSources drops the articles of deleted sources.
+	LiveSources bool
+	// Limit zero returns all matches.
+	Limit  int
+	Offset int
+}
+
+const (
+	PostKindNewsDigest   = "news_digest"
+	PostKindGitHubDigest = "github_digest"
+	PostKindBreaking     = "breaking"
+	PostKindArticle      = "article"
+	PostKindWeeklyRecap  = "weekly_recap"
+)
+
+// Post is a message published to a channel together with the LLM token
+// usage of the generation that produced it.
+type Post struct {
+	ID        int64
+	Kind      string
+	ChannelID int64
+	Slot      string
+	// ArticleCount is the number of articles or repos the post covers.
+	ArticleCount int
+	Model        string
+	// EstimatedTokens is the CountTokens estimate of the prompt input.
+	EstimatedTokens int
+	// PromptTokens and CompletionTokens are reported by the provider and stay
+	// zero when the LLM was not used or did not report them.
+	PromptTokens     int
+	CompletionTokens int
+	// Fallback is true when the LLM failed and a plain digest was posted.
+	Fallback bool
+	// MessageID is the Telegram message holding the post text and
+	// MediaMessageIDs the album sent ahead of it, if any.
+	MessageID       int
+	MediaMessageIDs []int
+	// ArticleIDs are the articles the post marked as posted.
+	ArticleIDs  []int64
+	EditedAt    time.Time
+	RetractedAt time.Time
+	CreatedAt   time.Time
+}
+
+// PromptTemplate is one version of a named text/template prompt. Exactly one
+// version per name is active.
+type PromptTemplate struct {
+	ID        int64
+	Name      string
+	Version   int
+	Body      string
+	Active    bool
+	CreatedBy int64
+	CreatedAt time.Time
+}
+
+// Feedback votes readers leave with the buttons under a post.
+const (
+	VoteUp   = "up"
+	VoteDown = "down"
+	VoteMore = "more"
+)
+
+// Vote is one reader's vote on a posted message. ArticleID is zero for posts
+// that cover several articles, such as digests.
+type Vote struct {
+	ChatID    int64
+	MessageID int
+	ArticleID int64
+	UserID    int64
+	Vote      string
+	CreatedAt time.Time
+}
+
+// FeedbackStats sums up the votes left since a point in time.
+type FeedbackStats struct {
+	Totals   map[string]int
+	Sources  []SourceFeedback
+	Articles []ArticleFeedback
+}
+
+// SourceFeedback counts the votes on articles of one source.
+type SourceFeedback struct {
+	SourceName string
+	Up         int
+	Down       int
+	More       int
+}
+
+// ArticleFeedback counts the votes on one article.
+type ArticleFeedback struct {
+	ArticleID int64
+	Title     string
+	Link      string
+	Up        int
+	Down      int
+	More      int
+}
+
+// Conversation is the state of a multi-step bot dialog in a chat; see
+// botkit.Conversation.
+type Conversation struct {
+	ChatID int64
+	// UserID is the user who started the dialog; only their input counts.
+	UserID int64
+	Name   string
+	Step   string
+	// Values are the answers so far, keyed by step name.
+	Values map[string]string
+	// History lists the steps answered so far, for going back.
+	History []string
+	// MessageID is the prompt of the current step; buttons of older prompts
+	// are ignored.
+	MessageID int
+	UpdatedAt time.Time
+}
+
+// Subscription schedules.
+const (
+	ScheduleDaily  = "daily"
+	ScheduleWeekly = "weekly"
+)
+
+// Subscription asks for personal digests of the articles matching any of
+// Keywords, in any of Categories and from any of SourceIDs; empty lists do
+// not filter. Digests go to ChatID at Hour in Timezone every day, or on
+// Mondays for a weekly Schedule.
+type Subscription struct {
+	ID         int64
+	UserID     int64
+	ChatID     int64
+	Keywords   []string
+	Categories []string
+	SourceIDs  []int64
+	Schedule   string
+	Hour       int
+	// Timezone is the IANA name of the zone of Hour; empty or unknown is UTC.
+	Timezone string
+	// Language is the language of the digest texts.
+	Language   string
+	LastSentAt time.Time
+	CreatedAt  time.Time
+}
+
+// Due reports whether a digest is due in the hour of now and has not been
+// sent yet. The hour and weekday are those of now in the subscription's
+// timezone, not the server's.
+func (s Subscription) Due(now time.Time) bool {
+	local := now.In(s.Location())
+	if local.Hour() != s.Hour || now.Sub(s.LastSentAt) < time.Hour {
+		return false
+	}
+	return s.Schedule != ScheduleWeekly || local.Weekday() == time.Monday
+}
+
+// Location returns the zone of Timezone, UTC if it is empty or unknown.
+func (s Subscription) Location() *time.Location {
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// Period is how far back the first digest of a subscription looks.
+func (s Subscription) Period() time.Duration {
+	if s.Schedule == ScheduleWeekly {
+		return 7 * 24 * time.Hour
+	}
+	return 24 * time.Hour
+}
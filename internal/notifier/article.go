@@ -0,0 +1,310 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/botkit/markup"
+	"github.com/0x0BSoD/newsMaker/internal/feedback"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+	"github.com/0x0BSoD/newsMaker/internal/prompt"
+	"github.com/0x0BSoD/newsMaker/internal/ranking"
+	"github.com/0x0BSoD/newsMaker/internal/reporter"
+	"github.com/0x0BSoD/newsMaker/internal/summary"
+)
+
+// Channel modes: a channel gets scheduled digests or one article per
+// notification interval.
+const (
+	ChannelModeDigest  = "digest"
+	ChannelModeArticle = "article"
+)
+
+// Link preview styles of article posts.
+const (
+	PreviewLarge = "large"
+	PreviewSmall = "small"
+	PreviewNone  = "none"
+)
+
+// maxHashtags bounds the tags appended to an article post.
+const maxHashtags = 5
+
+// ParseLinkPreview returns the link preview options for a preview style,
+// shown above the text when above is set.
+func ParseLinkPreview(style string, above bool) (botkit.LinkPreview, error) {
+	switch style {
+	case PreviewLarge, "":
+		return botkit.LinkPreview{PreferLargeMedia: true, ShowAboveText: above}, nil
+	case PreviewSmall:
+		return botkit.LinkPreview{PreferSmallMedia: true, ShowAboveText: above}, nil
+	case PreviewNone:
+		return botkit.LinkPreview{Disabled: true}, nil
+	default:
+		return botkit.LinkPreview{}, fmt.Errorf("unknown link preview %q, want %s, %s or %s", style, PreviewLarge, PreviewSmall, PreviewNone)
+	}
+}
+
+// ArticleNotifier posts the best-ranked unposted article with a short LLM
+// summary every interval. It shares the article queue with the digest
+// Notifier: articles are claimed atomically before posting, so whatever one
+// posts, the other skips.
+type ArticleNotifier struct {
+	articles        ArticleProvider
+	posts           PostStorage
+	summarizer      summary.Summarizer
+	ranker          ArticleRanker
+	bot             botkit.Sender
+	reporter        *reporter.Reporter
+	channelID       int64
+	interval        time.Duration
+	lookback        time.Duration
+	candidates      int
+	maxInputDataLen int
+	prompts         PromptRenderer
+	language        string
+	preview         botkit.LinkPreview
+	images          bool
+	feedback        bool
+	gate            PostGate
+	translate       bool
+}
+
+func NewArticleNotifier(
+	articleProvider ArticleProvider,
+	postStorage PostStorage,
+	summarizer summary.Summarizer,
+	ranker ArticleRanker,
+	bot botkit.Sender,
+	rep *reporter.Reporter,
+	channelID int64,
+	interval time.Duration,
+	lookback time.Duration,
+	candidates int,
+	maxInputDataLen int,
+	prompts PromptRenderer,
+	language string,
+	preview botkit.LinkPreview,
+	images bool,
+	feedback bool,
+	gate PostGate,
+	translate bool,
+) *ArticleNotifier {
+	return &ArticleNotifier{
+		articles:        articleProvider,
+		posts:           postStorage,
+		summarizer:      summarizer,
+		ranker:          ranker,
+		bot:             bot,
+		reporter:        rep,
+		channelID:       channelID,
+		interval:        interval,
+		lookback:        lookback,
+		candidates:      candidates,
+		maxInputDataLen: maxInputDataLen,
+		prompts:         prompts,
+		language:        language,
+		preview:         preview,
+		images:          images,
+		feedback:        feedback,
+		gate:            gate,
+		translate:       translate,
+	}
+}
+
+func (n *ArticleNotifier) Start(ctx context.Context) error {
+	slog.Info("notifier started (article mode)", "channel", n.channelID, "interval", n.interval)
+	ticker := time.NewTicker(n.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := n.SendArticle(ctx); err != nil {
+				slog.Error("article send failed", "channel", n.channelID, "err", err)
+				n.reporter.Notify(fmt.Sprintf("Article post error: %v", err))
+			}
+		}
+	}
+}
+
+// SendArticle posts the top-ranked unposted article, if any, claiming it first
+// so no other channel posts it too. During quiet hours or over the daily cap
+// it posts nothing and the article waits for a later interval.
+func (n *ArticleNotifier) SendArticle(ctx context.Context) (err error) {
+	if allowed, err := n.gate.Allowed(ctx, n.channelID); err != nil || !allowed {
+		return err
+	}
+
+	scores, err := rankCandidates(ctx, n.articles, n.ranker, n.lookback, n.candidates, 1)
+	if err != nil {
+		return err
+	}
+	picked, err := claimArticles(ctx, n.articles, ranking.Picked(scores))
+	if err != nil {
+		return err
+	}
+	if len(picked) == 0 {
+		return nil
+	}
+	article := picked[0]
+	defer func() {
+		if err != nil {
+			unclaimArticles(ctx, n.articles, picked)
+		}
+	}()
+
+	var titleUsage summary.Usage
+	if n.translate {
+		translated, usage, err := translateTitles(ctx, n.summarizer, n.prompts, n.channelID, n.language, []model.Article{article})
+		if err != nil {
+			slog.Warn("title translation failed, keeping the original title", "articleID", article.ID, "err", err)
+		}
+		article, titleUsage = translated[0], usage
+	}
+
+	description, usage, err := n.summarize(ctx, article)
+	usage.Add(titleUsage)
+	fallback := err != nil || description == ""
+	if fallback {
+		if err != nil {
+			slog.Warn("article summary failed, using the feed summary", "articleID", article.ID, "err", err)
+		}
+		description = prompt.Truncate(strings.TrimSpace(article.Summary), n.maxInputDataLen)
+	}
+
+	preview := n.preview
+	preview.URL = article.Link
+	var keyboard *tgbotapi.InlineKeyboardMarkup
+	if n.feedback {
+		kb := feedback.Keyboard(article.ID, nil)
+		keyboard = &kb
+	}
+	var sent tgbotapi.Message
+	if n.images && article.ImageURL != "" {
+		sent, err = botkit.SendPhotoOrHTML(ctx, n.bot, n.channelID, article.ImageURL, fitCaption(article, description), preview, keyboard)
+	} else {
+		sent, err = botkit.SendHTML(ctx, n.bot, n.channelID, formatArticle(article, description), preview, keyboard)
+	}
+	if err != nil {
+		return fmt.Errorf("send article: %w", err)
+	}
+
+	if _, err := n.posts.Store(ctx, model.Post{
+		Kind:             model.PostKindArticle,
+		ChannelID:        n.channelID,
+		ArticleCount:     1,
+		Model:            usage.Model,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		Fallback:         fallback,
+		MessageID:        sent.MessageID,
+		ArticleIDs:       []int64{article.ID},
+	}); err != nil {
+		slog.Error("store post failed", "err", err)
+	}
+
+	return nil
+}
+
+func (n *ArticleNotifier) summarize(ctx context.Context, a model.Article) (string, summary.Usage, error) {
+	systemPrompt, err := n.prompts.Render(ctx, prompt.ArticleSummary, prompt.Data{
+		Channel:    n.channelID,
+		Language:   n.language,
+		Articles:   []model.Article{a},
+		MaxDataLen: n.maxInputDataLen,
+	})
+	if err != nil {
+		return "", summary.Usage{}, fmt.Errorf("render article prompt: %w", err)
+	}
+
+	input := a.Title
+	if a.Summary != "" {
+		input += "\n\n" + prompt.Truncate(a.Summary, n.maxInputDataLen)
+	}
+
+	res, err := n.summarizer.Summarize(ctx, input, summary.WithSystemPrompt(systemPrompt))
+	if err != nil {
+		return "", res.Usage, err
+	}
+	return strings.TrimSpace(res.Text), res.Usage, nil
+}
+
+// formatArticle renders an article post: the linked title, the source, the
+// summary and the categories as hashtags.
+func formatArticle(a model.Article, description string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("<b><a href=\"%s\">%s</a></b>\n", a.Link, markup.EscapeForHTML(a.Title)))
+	if a.SourceName != "" {
+		sb.WriteString(fmt.Sprintf("<i>%s</i>\n", markup.EscapeForHTML(a.SourceName)))
+	}
+	if description = strings.TrimSpace(description); description != "" {
+		sb.WriteString("\n")
+		sb.WriteString(markup.EscapeForHTML(description))
+		sb.WriteString("\n")
+	}
+	if tags := hashtags(a.Categories); len(tags) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(strings.Join(tags, " "))
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// fitCaption formats the article, shortening the description until the post
+// fits a photo caption. Escaping makes the text longer than the description,
+// so the description is cut in proportion and retried.
+func fitCaption(a model.Article, description string) string {
+	text := formatArticle(a, description)
+	for limit := utf8.RuneCountInString(description); !botkit.CaptionFits(text) && limit > 0; {
+		limit = min(limit-1, limit*botkit.MaxCaptionLen/utf8.RuneCountInString(text))
+		short := ""
+		if limit > 0 {
+			short = prompt.Truncate(description, limit)
+		}
+		text = formatArticle(a, short)
+	}
+	return text
+}
+
+// hashtags turns categories into Telegram hashtags. Characters a hashtag
+// cannot contain become underscores; duplicates and purely numeric tags are
+// dropped.
+func hashtags(categories []string) []string {
+	var (
+		tags []string
+		seen = make(map[string]bool)
+	)
+	for _, c := range categories {
+		tag := strings.Trim(strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+				return r
+			}
+			return '_'
+		}, strings.TrimSpace(c)), "_")
+		for strings.Contains(tag, "__") {
+			tag = strings.ReplaceAll(tag, "__", "_")
+		}
+
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] || strings.IndexFunc(tag, func(r rune) bool { return !unicode.IsDigit(r) }) < 0 {
+			continue
+		}
+		seen[key] = true
+		tags = append(tags, "#"+tag)
+		if len(tags) == maxHashtags {
+			break
+		}
+	}
+	return tags
+}
@@ -0,0 +1,65 @@
+package notifier
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+func TestFormatArticle(t *testing.T) {
+	a := model.Article{
+		Title:      "Go <1.26> released",
+		Link:       "https://go.dev/blog/go1.26",
+		SourceName: "Go Blog",
+		Categories: []string{"golang", "Cloud Native", "2026"},
+	}
+
+	assert.Equal(t, "<b><a href=\"https://go.dev/blog/go1.26\">Go &lt;1.26&gt; released</a></b>\n"+
+		"<i>Go Blog</i>\n\n"+
+		"Faster builds &amp; more.\n\n"+
+		"#golang #Cloud_Native\n",
+		formatArticle(a, " Faster builds & more. "))
+}
+
+func TestHashtags(t *testing.T) {
+	t.Run("should sanitize and deduplicate categories", func(t *testing.T) {
+		assert.Equal(t, []string{"#node_js", "#AI"}, hashtags([]string{"node.js", "Node-JS", "AI", "ai", "!!!"}))
+	})
+
+	t.Run("should cap the number of tags", func(t *testing.T) {
+		assert.Len(t, hashtags([]string{"a", "b", "c", "d", "e", "f"}), maxHashtags)
+	})
+}
+
+func TestParseLinkPreview(t *testing.T) {
+	p, err := ParseLinkPreview("", true)
+	require.NoError(t, err)
+	assert.Equal(t, botkit.LinkPreview{PreferLargeMedia: true, ShowAboveText: true}, p)
+
+	p, err = ParseLinkPreview(PreviewNone, true)
+	require.NoError(t, err)
+	assert.Equal(t, botkit.LinkPreview{Disabled: true}, p)
+
+	_, err = ParseLinkPreview("huge", false)
+	assert.Error(t, err)
+}
+
+func TestFitCaption(t *testing.T) {
+	a := model.Article{Title: "Title", Link: "https://example.com", Categories: []string{"go"}}
+
+	t.Run("should keep a short caption", func(t *testing.T) {
+		assert.Equal(t, formatArticle(a, "Short."), fitCaption(a, "Short."))
+	})
+
+	t.Run("should shorten the description to fit", func(t *testing.T) {
+		caption := fitCaption(a, strings.Repeat("long & ", 300))
+		assert.True(t, botkit.CaptionFits(caption))
+		assert.Contains(t, caption, "...")
+		assert.Contains(t, caption, "#go")
+	})
+}
@@ -0,0 +1,237 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+	"github.com/0x0BSoD/newsMaker/internal/prompt"
+	"github.com/0x0BSoD/newsMaker/internal/summary"
+)
+
+const (
+	DigestModeSingle    = "single"
+	DigestModeMapReduce = "map_reduce"
+	// DigestModeAuto uses a single prompt while the input fits the chunk
+	// budget and switches to map-reduce once it does not.
+	DigestModeAuto = "auto"
+
+	// maxReduceDepth bounds how many times partial summaries are re-condensed
+	// when they still overflow the budget after the map step.
+	maxReduceDepth = 3
+)
+
+// TokenCounter is the part of summary.Summarizer needed to measure prompt size.
+type TokenCounter interface {
+	CountTokens(text string) (int, error)
+}
+
+// digestChunk is one unit of work for the map step: articles from a single
+// topic whose rendered lines fit the token budget together.
+type digestChunk struct {
+	topic string
+	lines []string
+}
+
+func (c digestChunk) input() string {
+	return fmt.Sprintf("Topic: %s\n%s\n", c.topic, strings.Join(c.lines, "\n"))
+}
+
+// mapReduceInput condenses every chunk with the map prompt and returns the
+// input for the final compose step, built from the partial results.
+func (n *Notifier) mapReduceInput(ctx context.Context, data prompt.Data, grouped map[string][]model.Article) (string, summary.Usage, error) {
+	mapName, lineName := prompt.NewsDigestMap, prompt.NewsDigestArticle
+	if n.output == DigestOutputJSON {
+		mapName, lineName = prompt.NewsDigestJSONMap, prompt.NewsDigestJSONArticle
+	}
+
+	var usage summary.Usage
+	mapPrompt, err := n.prompts.Render(ctx, mapName, data)
+	if err != nil {
+		return "", usage, fmt.Errorf("render map prompt: %w", err)
+	}
+
+	line := func(a model.Article) (string, error) {
+		d := data
+		d.Article = a
+		out, err := n.prompts.Render(ctx, lineName, d)
+		return strings.TrimSpace(out), err
+	}
+	chunks, err := buildDigestChunks(grouped, n.chunkTokens, n.summarizer, line)
+	if err != nil {
+		return "", usage, fmt.Errorf("render article line: %w", err)
+	}
+	slog.Info("map-reduce digest", "chunks", len(chunks), "budget", n.chunkTokens)
+
+	partials := make([]string, 0, len(chunks))
+	for i, c := range chunks {
+		if err := ctx.Err(); err != nil {
+			return "", usage, err
+		}
+
+		res, err := n.summarizer.Summarize(ctx, c.input(), summary.WithSystemPrompt(mapPrompt))
+		usage.Add(res.Usage)
+		partial := res.Text
+		if err != nil || strings.TrimSpace(partial) == "" {
+			// Keep the raw lines so the reduce step still sees these articles.
+			slog.Warn("map step failed, passing chunk through", "chunk", i, "topic", c.topic, "err", err)
+			partial = strings.Join(c.lines, "\n")
+		}
+		partials = append(partials, fmt.Sprintf("Topic: %s\n%s\n", c.topic, strings.TrimSpace(partial)))
+	}
+
+	for depth := 0; depth < maxReduceDepth && len(partials) > 1; depth++ {
+		tokens := countTokens(n.summarizer, strings.Join(partials, "\n"))
+		if tokens <= n.chunkTokens {
+			break
+		}
+		slog.Info("partial summaries exceed budget, condensing", "depth", depth+1, "tokens", tokens, "partials", len(partials))
+		partials = n.condensePartials(ctx, partials, mapPrompt, &usage)
+	}
+
+	reduceInput := buildReduceInput(data.Slot, data.Language, partials)
+	writeSummaryInput(n.summaryInputDir, "digest_reduce.txt", reduceInput)
+
+	return reduceInput, usage, nil
+}
+
+// condensePartials packs partial summaries into budget-sized groups and runs
+// the map prompt over each group once more.
+func (n *Notifier) condensePartials(ctx context.Context, partials []string, mapPrompt string, usage *summary.Usage) []string {
+	var (
+		out     []string
+		batch   []string
+		batchSz int
+	)
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		input := strings.Join(batch, "\n")
+		condensed, err := n.summarizer.Summarize(ctx, input, summary.WithSystemPrompt(mapPrompt))
+		usage.Add(condensed.Usage)
+		if err != nil || strings.TrimSpace(condensed.Text) == "" {
+			slog.Warn("condense step failed, keeping partials", "err", err)
+			out = append(out, batch...)
+		} else {
+			out = append(out, strings.TrimSpace(condensed.Text)+"\n")
+		}
+		batch, batchSz = nil, 0
+	}
+
+	for _, p := range partials {
+		if ctx.Err() != nil {
+			out = append(out, p)
+			continue
+		}
+		tokens := countTokens(n.summarizer, p)
+		if batchSz+tokens > n.chunkTokens {
+			flush()
+		}
+		batch = append(batch, p)
+		batchSz += tokens
+	}
+	flush()
+
+	return out
+}
+
+// buildDigestChunks renders every article as a single input line with line
+// and packs the lines of each topic into chunks that fit within budget
+// tokens. A line that alone exceeds the budget is truncated to fit.
+func buildDigestChunks(grouped map[string][]model.Article, budget int, counter TokenCounter, line func(model.Article) (string, error)) ([]digestChunk, error) {
+	themes := make([]string, 0, len(grouped))
+	for theme := range grouped {
+		themes = append(themes, theme)
+	}
+	sort.Strings(themes)
+
+	var chunks []digestChunk
+	for _, theme := range themes {
+		header := countTokens(counter, fmt.Sprintf("Topic: %s\n", theme))
+		lineBudget := budget - header
+
+		current := digestChunk{topic: theme}
+		used := 0
+		for _, a := range grouped[theme] {
+			text, err := line(a)
+			if err != nil {
+				return nil, err
+			}
+			tokens := countTokens(counter, text)
+			if tokens > lineBudget {
+				text = truncateTokens(text, lineBudget, counter)
+				tokens = countTokens(counter, text)
+			}
+
+			if len(current.lines) > 0 && used+tokens > lineBudget {
+				chunks = append(chunks, current)
+				current = digestChunk{topic: theme}
+				used = 0
+			}
+			current.lines = append(current.lines, text)
+			used += tokens
+		}
+		if len(current.lines) > 0 {
+			chunks = append(chunks, current)
+		}
+	}
+
+	return chunks, nil
+}
+
+// buildReduceInput constructs the input for the final compose step from the
+// partial topic summaries. Like the default news_digest_input it names the
+// digest language, if any, for the system prompt to follow.
+func buildReduceInput(greeting, language string, partials []string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Time of day: %s\n", greeting))
+	if language != "" {
+		sb.WriteString(fmt.Sprintf("Language: %s\n", language))
+	}
+	sb.WriteString("\n")
+	sb.WriteString("Articles by topic:\n\n")
+	for _, p := range partials {
+		sb.WriteString(p)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// truncateTokens shortens s on a rune boundary so that it counts at most
+// maxTokens tokens, using binary search over the rune length.
+func truncateTokens(s string, maxTokens int, counter TokenCounter) string {
+	if maxTokens <= 0 {
+		return ""
+	}
+	if countTokens(counter, s) <= maxTokens {
+		return s
+	}
+
+	runes := []rune(s)
+	lo, hi := 0, len(runes)
+	for lo < hi {
+		mid := (lo + hi + 1) / 2
+		if countTokens(counter, string(runes[:mid])+"...") <= maxTokens {
+			lo = mid
+		} else {
+			hi = mid - 1
+		}
+	}
+	return string(runes[:lo]) + "..."
+}
+
+// countTokens asks counter for the token count of text and falls back to
+// summary.EstimateTokens when counting fails.
+func countTokens(counter TokenCounter, text string) int {
+	if counter != nil {
+		if tokens, err := counter.CountTokens(text); err == nil {
+			return tokens
+		}
+	}
+	return summary.EstimateTokens(text)
+}
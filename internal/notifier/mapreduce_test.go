@@ -0,0 +1,117 @@
+package notifier
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+	"github.com/0x0BSoD/newsMaker/internal/prompt"
+)
+
+// wordCounter counts whitespace-separated words as tokens.
+type wordCounter struct{}
+
+func (wordCounter) CountTokens(text string) (int, error) {
+	return len(strings.Fields(text)), nil
+}
+
+// articleLine renders article lines with the news_digest_article template of
+// lib.
+func articleLine(lib *prompt.Library) func(model.Article) (string, error) {
+	return func(a model.Article) (string, error) {
+		out, err := lib.Render(context.Background(), prompt.NewsDigestArticle, prompt.Data{Article: a, MaxDataLen: 500})
+		return strings.TrimSpace(out), err
+	}
+}
+
+func TestTruncateTokens(t *testing.T) {
+	got := truncateTokens("one two three four five six", 4, wordCounter{})
+
+	tokens, err := wordCounter{}.CountTokens(got)
+	require.NoError(t, err)
+	assert.LessOrEqual(t, tokens, 4)
+	assert.True(t, strings.HasPrefix(got, "one two three"))
+}
+
+func TestBuildDigestChunks(t *testing.T) {
+	grouped := map[string][]model.Article{
+		"Go": {
+			{Title: "Go 1.26 released", Link: "https://go.dev/1", Summary: "release notes for the new version"},
+			{Title: "Generics tips", Link: "https://go.dev/2", Summary: "how to use type parameters well"},
+			{Title: "Profiling guide", Link: "https://go.dev/3", Summary: "pprof in practice"},
+		},
+		"Rust": {
+			{Title: "Rust 2027", Link: "https://rust-lang.org/1"},
+		},
+	}
+
+	t.Run("should split topics that overflow the budget", func(t *testing.T) {
+		chunks, err := buildDigestChunks(grouped, 20, wordCounter{}, articleLine(prompt.NewLibrary(nil, nil)))
+		require.NoError(t, err)
+
+		var goChunks int
+		for _, c := range chunks {
+			tokens, _ := wordCounter{}.CountTokens(c.input())
+			assert.LessOrEqual(t, tokens, 20, "chunk %q exceeds budget", c.topic)
+			if c.topic == "Go" {
+				goChunks++
+			}
+		}
+		assert.Greater(t, goChunks, 1)
+	})
+
+	t.Run("should keep every article", func(t *testing.T) {
+		chunks, err := buildDigestChunks(grouped, 20, wordCounter{}, articleLine(prompt.NewLibrary(nil, nil)))
+		require.NoError(t, err)
+
+		var lines int
+		for _, c := range chunks {
+			lines += len(c.lines)
+		}
+		assert.Equal(t, 4, lines)
+	})
+
+	t.Run("should truncate a line that alone exceeds the budget", func(t *testing.T) {
+		long := map[string][]model.Article{
+			"News": {{Title: "Long", Link: "https://example.com", Summary: strings.Repeat("слово ", 50)}},
+		}
+		chunks, err := buildDigestChunks(long, 10, wordCounter{}, articleLine(prompt.NewLibrary(nil, nil)))
+		require.NoError(t, err)
+
+		require.Len(t, chunks, 1)
+		tokens, _ := wordCounter{}.CountTokens(chunks[0].input())
+		assert.LessOrEqual(t, tokens, 10)
+		assert.True(t, utf8.ValidString(chunks[0].input()))
+	})
+	t.Run("should render lines with the article template", func(t *testing.T) {
+		lib := prompt.NewLibrary(nil, map[string]string{prompt.NewsDigestArticle: "* {{.Article.Title}}"})
+		chunks, err := buildDigestChunks(grouped, 20, wordCounter{}, articleLine(lib))
+		require.NoError(t, err)
+
+		require.NotEmpty(t, chunks)
+		assert.Equal(t, "* Go 1.26 released", chunks[0].lines[0])
+	})
+}
+
+func TestNotifier_MapReduceInput(t *testing.T) {
+	t.Run("should name the channel language in the reduce input", func(t *testing.T) {
+		s := &scriptedSummarizer{replies: []string{"Go news."}}
+		n := &Notifier{
+			summarizer:      s,
+			prompts:         prompt.NewLibrary(nil, map[string]string{prompt.NewsDigestMap: "Condense."}),
+			chunkTokens:     1000,
+			summaryInputDir: t.TempDir(),
+		}
+		grouped := map[string][]model.Article{"Go": {{Title: "Go 1.26 released", Link: "https://go.dev/1"}}}
+
+		input, _, err := n.mapReduceInput(context.Background(), prompt.Data{Slot: "morning", Language: "ru"}, grouped)
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(input, "Time of day: morning\nLanguage: ru\n\nArticles by topic:\n\n"), input)
+		assert.Contains(t, input, "Topic: Go\nGo news.")
+	})
+}
@@ -2,6 +2,7 @@ package notifier
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -10,26 +11,68 @@ import (
 	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/samber/lo"
 
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
 	"github.com/0x0BSoD/newsMaker/internal/botkit/markup"
+	"github.com/0x0BSoD/newsMaker/internal/feedback"
+	"github.com/0x0BSoD/newsMaker/internal/gate"
 	"github.com/0x0BSoD/newsMaker/internal/model"
+	"github.com/0x0BSoD/newsMaker/internal/prompt"
+	"github.com/0x0BSoD/newsMaker/internal/ranking"
 	"github.com/0x0BSoD/newsMaker/internal/reporter"
+	"github.com/0x0BSoD/newsMaker/internal/summary"
 )
 
 type ArticleProvider interface {
 	AllNotPosted(ctx context.Context, since time.Time, limit uint64) ([]model.Article, error)
-	MarkAsPosted(ctx context.Context, article model.Article) error
+	PostedSince(ctx context.Context, since time.Time, limit uint64) ([]model.Article, error)
+	ArticlesByIDs(ctx context.Context, ids []int64) ([]model.Article, error)
+	Claim(ctx context.Context, ids []int64) ([]int64, error)
+	Unclaim(ctx context.Context, ids []int64) error
 }
 
-type Summarizer interface {
-	Summarize(text string) (string, error)
-	CountTokens(text string) (int, error)
+// PostStorage records published posts with their token usage.
+type PostStorage interface {
+	Store(ctx context.Context, post model.Post) (int64, error)
+	MarkEdited(ctx context.Context, id int64) error
+}
+
+// PostGate defers posts during quiet hours and over the daily cap; see
+// gate.Gate. It is checked before a post is generated, so that none goes
+// stale while held back; the sender enforces it again on the send itself.
+type PostGate interface {
+	Wait(ctx context.Context, channelID int64) error
+	Allowed(ctx context.Context, channelID int64) (bool, error)
+}
+
+// VoteCounter is satisfied by storage.FeedbackPostgresStorage.
+type VoteCounter interface {
+	VoteCounts(ctx context.Context, chatID int64, messageID int) (map[string]int, error)
+}
+
+// ArticleGrouper groups articles into digest topics; see cluster.Grouper.
+type ArticleGrouper interface {
+	Group(ctx context.Context, articles []model.Article) map[string][]model.Article
+}
+
+// ArticleRanker scores candidates and picks the top n; see ranking.Ranker.
+type ArticleRanker interface {
+	Rank(ctx context.Context, articles, posted []model.Article, n int) []ranking.Score
+}
+
+// PromptRenderer renders named prompt templates; see prompt.Library.
+type PromptRenderer interface {
+	Render(ctx context.Context, name string, data prompt.Data) (string, error)
 }
 
 type Notifier struct {
 	articles        ArticleProvider
-	summarizer      Summarizer
-	bot             *tgbotapi.BotAPI
+	posts           PostStorage
+	summarizer      summary.Summarizer
+	grouper         ArticleGrouper
+	ranker          ArticleRanker
+	bot             botkit.Sender
 	reporter        *reporter.Reporter
 	channelID       int64
 	morningHour     int
@@ -37,31 +80,59 @@ type Notifier struct {
 	eveningHour     int
 	lookback        time.Duration
 	maxArticles     int
+	candidates      int
 	retryInterval   time.Duration
 	maxRetries      int
 	summaryInputDir string
 	maxInputDataLen int
+	prompts         PromptRenderer
+	language        string
+	mode            string
+	chunkTokens     int
+	output          string
+	images          bool
+	feedback        bool
+	gate            PostGate
+	translate       bool
+	votes           VoteCounter
 }
 
 func New(
 	articleProvider ArticleProvider,
-	summarizer Summarizer,
-	bot *tgbotapi.BotAPI,
+	postStorage PostStorage,
+	summarizer summary.Summarizer,
+	grouper ArticleGrouper,
+	ranker ArticleRanker,
+	bot botkit.Sender,
 	morningHour int,
 	noonHour int,
 	eveningHour int,
 	lookback time.Duration,
 	maxArticles int,
+	candidates int,
 	channelID int64,
 	rep *reporter.Reporter,
 	retryInterval time.Duration,
 	maxRetries int,
 	summaryInputDir string,
 	maxInputDataLen int,
+	prompts PromptRenderer,
+	language string,
+	mode string,
+	chunkTokens int,
+	output string,
+	images bool,
+	feedback bool,
+	gate PostGate,
+	translate bool,
+	votes VoteCounter,
 ) *Notifier {
 	return &Notifier{
 		articles:        articleProvider,
+		posts:           postStorage,
 		summarizer:      summarizer,
+		grouper:         grouper,
+		ranker:          ranker,
 		bot:             bot,
 		reporter:        rep,
 		channelID:       channelID,
@@ -70,10 +141,21 @@ func New(
 		eveningHour:     eveningHour,
 		lookback:        lookback,
 		maxArticles:     maxArticles,
+		candidates:      candidates,
 		retryInterval:   retryInterval,
 		maxRetries:      maxRetries,
 		summaryInputDir: summaryInputDir,
 		maxInputDataLen: maxInputDataLen,
+		prompts:         prompts,
+		language:        language,
+		mode:            mode,
+		chunkTokens:     chunkTokens,
+		output:          output,
+		images:          images,
+		feedback:        feedback,
+		gate:            gate,
+		translate:       translate,
+		votes:           votes,
 	}
 }
 
@@ -96,11 +178,13 @@ func (n *Notifier) Start(ctx context.Context) error {
 // sendWithRetry attempts SendDigest up to maxRetries times, waiting
 // retryInterval between attempts. Stops early if ctx is cancelled.
 func (n *Notifier) sendWithRetry(ctx context.Context, greeting string) {
+	attemptCtx := ctx
 	for attempt := 1; attempt <= n.maxRetries; attempt++ {
-		err := n.SendDigest(ctx, greeting)
+		err := n.SendDigest(attemptCtx, greeting)
 		if err == nil {
 			return
 		}
+		attemptCtx = retryContext(ctx, err)
 
 		slog.Error("digest send failed", "err", err, "attempt", attempt, "maxRetries", n.maxRetries)
 		n.reporter.Notify(fmt.Sprintf("Digest error (attempt %d/%d): %v", attempt, n.maxRetries, err))
@@ -119,6 +203,30 @@ func (n *Notifier) sendWithRetry(ctx context.Context, greeting string) {
 	}
 }
 
+// errOutputRejected marks a digest that failed because of the LLM output
+// itself rather than a transport or send error.
+var errOutputRejected = errors.New("digest output rejected")
+
+// retryContext returns the context of the attempt after one that failed with
+// err. The LLM cache is bypassed only when the output was rejected, by
+// structured validation or by Telegram for its markup, so the same output is
+// not replayed; after any other error the cached output is still good and
+// saves the regeneration.
+func retryContext(ctx context.Context, err error) context.Context {
+	if outputRejected(err) {
+		return summary.WithoutCache(ctx)
+	}
+	return ctx
+}
+
+func outputRejected(err error) bool {
+	var tgErr *tgbotapi.Error
+	if errors.As(err, &tgErr) && strings.Contains(tgErr.Message, "can't parse entities") {
+		return true
+	}
+	return errors.Is(err, errOutputRejected)
+}
+
 // nextScheduledTime returns the next morning or evening schedule time and the
 // greeting word ("morning" or "evening") to use in the digest.
 func (n *Notifier) nextScheduledTime() (time.Time, string) {
@@ -147,20 +255,25 @@ func (n *Notifier) nextScheduledTime() (time.Time, string) {
 	return time.Date(ty, tm, td, n.morningHour, 0, 0, 0, loc), "morning"
 }
 
+// SendDigest sends the scheduled digest once the channel may be posted to.
 func (n *Notifier) SendDigest(ctx context.Context, greeting string) error {
+	if err := n.gate.Wait(ctx, n.channelID); err != nil {
+		return err
+	}
 	return n.send(ctx, greeting, n.channelID, true)
 }
 
 // SendTestDigest sends a digest to channelID without marking articles as
 // posted, so the production article queue is not affected.
 func (n *Notifier) SendTestDigest(ctx context.Context, channelID int64) error {
-	return n.send(ctx, n.currentGreeting(), channelID, false)
+	return n.send(gate.Exempt(ctx), n.currentGreeting(), channelID, false)
 }
 
 // Repost sends a digest to the production channel and marks articles as posted.
-// Intended for manual recovery after all automatic retry attempts have failed.
+// Intended for manual recovery after all automatic retry attempts have failed,
+// so it is exempt from quiet hours and the daily cap.
 func (n *Notifier) Repost(ctx context.Context) error {
-	return n.send(ctx, n.currentGreeting(), n.channelID, true)
+	return n.send(gate.Exempt(ctx), n.currentGreeting(), n.channelID, true)
 }
 
 func (n *Notifier) currentGreeting() string {
@@ -175,22 +288,214 @@ func (n *Notifier) currentGreeting() string {
 	}
 }
 
-func (n *Notifier) send(ctx context.Context, greeting string, channelID int64, markPosted bool) error {
-	since := time.Now().Add(-n.lookback)
-	articles, err := n.articles.AllNotPosted(ctx, since, uint64(n.maxArticles))
+// Explain ranks the current digest candidates without sending anything, so
+// admins can see why each article would be picked or dropped.
+func (n *Notifier) Explain(ctx context.Context) ([]ranking.Score, error) {
+	return rankCandidates(ctx, n.articles, n.ranker, n.lookback, n.candidates, n.maxArticles)
+}
+
+// rankCandidates ranks the unposted articles of the last lookback and picks
+// the top n. Coverage of stories posted within lookback is dropped, since it
+// stays unposted when its story goes out.
+func rankCandidates(ctx context.Context, articles ArticleProvider, ranker ArticleRanker, lookback time.Duration, candidates, n int) ([]ranking.Score, error) {
+	since := time.Now().Add(-lookback)
+	unposted, err := articles.AllNotPosted(ctx, since, uint64(candidates))
 	if err != nil {
-		return fmt.Errorf("fetch articles: %w", err)
+		return nil, fmt.Errorf("fetch articles: %w", err)
+	}
+	posted, err := articles.PostedSince(ctx, since, uint64(candidates))
+	if err != nil {
+		return nil, fmt.Errorf("fetch posted articles: %w", err)
+	}
+	return ranker.Rank(ctx, unposted, posted, n), nil
+}
+
+func (n *Notifier) send(ctx context.Context, greeting string, channelID int64, markPosted bool) (err error) {
+	scores, err := n.Explain(ctx)
+	if err != nil {
+		return err
 	}
 
+	articles := ranking.Picked(scores)
 	if len(articles) == 0 {
 		slog.Info("no unposted articles for digest")
 		return nil
 	}
+	slog.Info("ranked digest candidates", "candidates", len(scores), "picked", len(articles))
+
+	// The articles are claimed before the digest is built so no other
+	// channel posts them meanwhile, and returned to the queue if it fails.
+	if markPosted {
+		if articles, err = claimArticles(ctx, n.articles, articles); err != nil {
+			return err
+		}
+		if len(articles) == 0 {
+			slog.Info("digest articles were claimed by another channel")
+			return nil
+		}
+		defer func() {
+			if err != nil {
+				unclaimArticles(ctx, n.articles, articles)
+			}
+		}()
+	}
 
 	slog.Info("building digest", "articles", len(articles), "slot", greeting, "channel", channelID, "markPosted", markPosted)
 
-	grouped := groupByTheme(articles)
-	digestInput := buildDigestInput(greeting, grouped, n.maxInputDataLen)
+	digest, err := n.render(ctx, greeting, channelID, articles)
+	if err != nil {
+		return err
+	}
+
+	var mediaIDs []int
+	if n.images {
+		mediaIDs = n.sendLeadImages(ctx, channelID, articles)
+	}
+
+	msg := tgbotapi.NewMessage(channelID, digest.text)
+	msg.ParseMode = "HTML"
+	msg.DisableWebPagePreview = true
+	if n.feedback {
+		msg.ReplyMarkup = feedback.Keyboard(0, nil)
+	}
+
+	sent, err := n.bot.Send(ctx, msg)
+	if err != nil {
+		return fmt.Errorf("send digest: %w", err)
+	}
+
+	if !markPosted {
+		return nil
+	}
+
+	if _, err := n.posts.Store(ctx, model.Post{
+		Kind:             model.PostKindNewsDigest,
+		ChannelID:        channelID,
+		Slot:             greeting,
+		ArticleCount:     len(articles),
+		Model:            digest.usage.Model,
+		EstimatedTokens:  digest.tokens,
+		PromptTokens:     digest.usage.PromptTokens,
+		CompletionTokens: digest.usage.CompletionTokens,
+		Fallback:         digest.fallback,
+		MessageID:        sent.MessageID,
+		MediaMessageIDs:  mediaIDs,
+		ArticleIDs:       articleIDs(articles),
+	}); err != nil {
+		slog.Error("store post failed", "err", err)
+	}
+
+	return nil
+}
+
+// claimArticles claims articles for posting and returns the ones no other
+// notifier has posted or claimed.
+func claimArticles(ctx context.Context, provider ArticleProvider, articles []model.Article) ([]model.Article, error) {
+	claimed, err := provider.Claim(ctx, articleIDs(articles))
+	if err != nil {
+		return nil, fmt.Errorf("claim articles: %w", err)
+	}
+	return lo.Filter(articles, func(a model.Article, _ int) bool { return lo.Contains(claimed, a.ID) }), nil
+}
+
+// unclaimArticles returns claimed articles to the queue after a failed post.
+func unclaimArticles(ctx context.Context, provider ArticleProvider, articles []model.Article) {
+	if err := provider.Unclaim(context.WithoutCancel(ctx), articleIDs(articles)); err != nil {
+		slog.Error("unclaim articles failed", "articles", articleIDs(articles), "err", err)
+	}
+}
+
+// Regenerate rebuilds a posted news digest from the articles it covered and
+// edits the channel message in place. The articles are not ranked again: all
+// of them stay marked as posted, so each must stay in the message. The
+// feedback buttons keep the votes cast so far.
+func (n *Notifier) Regenerate(ctx context.Context, post model.Post) error {
+	if post.Kind != model.PostKindNewsDigest {
+		return fmt.Errorf("post %d is a %s post, only news digests can be regenerated", post.ID, post.Kind)
+	}
+	if post.MessageID == 0 || len(post.ArticleIDs) == 0 {
+		return fmt.Errorf("post %d was sent before messages and articles were recorded", post.ID)
+	}
+
+	articles, err := n.articles.ArticlesByIDs(ctx, post.ArticleIDs)
+	if err != nil {
+		return fmt.Errorf("fetch articles: %w", err)
+	}
+	if len(articles) == 0 {
+		return fmt.Errorf("post %d has no articles left", post.ID)
+	}
+
+	slog.Info("regenerating digest", "post", post.ID, "articles", len(articles), "channel", post.ChannelID)
+
+	digest, err := n.render(ctx, post.Slot, post.ChannelID, articles)
+	if err != nil {
+		return err
+	}
+
+	edit := tgbotapi.NewEditMessageText(post.ChannelID, post.MessageID, digest.text)
+	edit.ParseMode = tgbotapi.ModeHTML
+	edit.DisableWebPagePreview = true
+	if n.feedback {
+		counts, err := n.votes.VoteCounts(ctx, post.ChannelID, post.MessageID)
+		if err != nil {
+			slog.Warn("load vote counts failed, buttons show none", "post", post.ID, "err", err)
+		}
+		keyboard := feedback.Keyboard(0, counts)
+		edit.ReplyMarkup = &keyboard
+	}
+	if _, err := n.bot.Send(ctx, edit); err != nil {
+		return fmt.Errorf("edit digest: %w", err)
+	}
+
+	if err := n.posts.MarkEdited(ctx, post.ID); err != nil {
+		slog.Error("mark post as edited failed", "post", post.ID, "err", err)
+	}
+	return nil
+}
+
+// renderedDigest is the digest text with what it took to generate it.
+type renderedDigest struct {
+	text     string
+	tokens   int
+	usage    summary.Usage
+	fallback bool
+}
+
+// render generates the digest text for the picked articles, falling back to
+// a plain list when the LLM fails.
+func (n *Notifier) render(ctx context.Context, greeting string, channelID int64, articles []model.Article) (renderedDigest, error) {
+	var titleUsage summary.Usage
+	if n.translate {
+		translated, usage, err := translateTitles(ctx, n.summarizer, n.prompts, channelID, n.language, articles)
+		if err != nil {
+			slog.Warn("title translation failed, keeping original titles", "err", err)
+		}
+		articles, titleUsage = translated, usage
+	}
+
+	grouped := n.grouper.Group(ctx, articles)
+	data := prompt.Data{
+		Slot:       greeting,
+		Channel:    channelID,
+		Language:   n.language,
+		Articles:   articles,
+		Groups:     prompt.GroupArticles(grouped),
+		MaxDataLen: n.maxInputDataLen,
+	}
+
+	inputName, systemName := prompt.NewsDigestInput, prompt.NewsDigestSystem
+	if n.output == DigestOutputJSON {
+		inputName, systemName = prompt.NewsDigestJSONInput, prompt.NewsDigestJSONSystem
+	}
+
+	digestInput, err := n.prompts.Render(ctx, inputName, data)
+	if err != nil {
+		return renderedDigest{}, fmt.Errorf("render digest input: %w", err)
+	}
+	systemPrompt, err := n.prompts.Render(ctx, systemName, data)
+	if err != nil {
+		return renderedDigest{}, fmt.Errorf("render digest prompt: %w", err)
+	}
 
 	writeSummaryInput(n.summaryInputDir, "digest.txt", digestInput)
 
@@ -198,80 +503,98 @@ func (n *Notifier) send(ctx context.Context, greeting string, channelID int64, m
 	if err != nil {
 		slog.Warn("summarizer.CountTokens", "err", err)
 	}
-	slog.Info("done digest input", "articles", len(articles), "slot", greeting, "channel", channelID, "tokens", tokens, "markPosted", markPosted)
-
-	digestText, err := n.summarizer.Summarize(digestInput)
-	if err != nil || strings.TrimSpace(digestText) == "" {
+	slog.Info("done digest input", "articles", len(articles), "slot", greeting, "channel", channelID, "tokens", tokens)
+
+	var (
+		result   summary.Result
+		mapUsage summary.Usage
+	)
+	composeInput := digestInput
+	if n.useMapReduce(tokens) {
+		composeInput, mapUsage, err = n.mapReduceInput(ctx, data, grouped)
+	}
+	if err == nil {
+		result, err = n.compose(ctx, composeInput, systemPrompt, articles)
+	}
+	result.Usage.Add(mapUsage)
+	result.Usage.Add(titleUsage)
+	digestText := result.Text
+	fallback := err != nil || strings.TrimSpace(digestText) == ""
+	if fallback {
 		if err != nil {
 			slog.Error("digest summarization failed, using simple fallback", "err", err)
 			n.reporter.Notify(fmt.Sprintf("Digest summarization error: %v", err))
 		} else {
 			slog.Warn("summarizer returned empty text, using simple fallback")
 		}
-		digestText = buildSimpleDigest(greeting, grouped)
+		digestText = buildSimpleDigest(n.language, greeting, grouped)
 	} else {
 		writeSummaryInput(n.summaryInputDir, "digest_output.txt", digestText)
 		digestText = markup.SanitizeTelegramHTML(digestText)
 	}
 
-	msg := tgbotapi.NewMessage(channelID, digestText)
-	msg.ParseMode = "HTML"
-	msg.DisableWebPagePreview = true
+	return renderedDigest{
+		text:     digestText,
+		tokens:   tokens,
+		usage:    result.Usage,
+		fallback: fallback,
+	}, nil
+}
 
-	if _, err := n.bot.Send(msg); err != nil {
-		return fmt.Errorf("send digest: %w", err)
+// sendLeadImages posts the lead images of the top stories as an album ahead
+// of the digest text and returns the album message IDs. Articles without a
+// usable image are skipped, and a failed album only costs the images.
+func (n *Notifier) sendLeadImages(ctx context.Context, channelID int64, articles []model.Article) []int {
+	var photos []botkit.Photo
+	for _, a := range articles {
+		if len(photos) == botkit.MaxMediaGroup {
+			break
+		}
+		if a.ImageURL == "" {
+			continue
+		}
+		if err := botkit.CheckPhoto(ctx, a.ImageURL); err != nil {
+			slog.Debug("skipping lead image", "articleID", a.ID, "err", err)
+			continue
+		}
+		photos = append(photos, botkit.Photo{
+			URL:     a.ImageURL,
+			Caption: fmt.Sprintf("<a href=\"%s\">%s</a>", a.Link, markup.EscapeForHTML(a.Title)),
+		})
 	}
 
-	if !markPosted {
+	msgs, err := botkit.SendMediaGroup(ctx, n.bot, channelID, photos)
+	if err != nil {
+		slog.Warn("sending digest images failed, sending text only", "images", len(photos), "err", err)
 		return nil
 	}
+	return lo.Map(msgs, func(m tgbotapi.Message, _ int) int { return m.MessageID })
+}
 
-	for _, article := range articles {
-		if err := n.articles.MarkAsPosted(ctx, article); err != nil {
-			slog.Error("mark as posted failed", "articleID", article.ID, "err", err)
-		}
-	}
-
-	return nil
+func articleIDs(articles []model.Article) []int64 {
+	return lo.Map(articles, func(a model.Article, _ int) int64 { return a.ID })
 }
 
-// groupByTheme groups articles by their primary RSS category.
-// Falls back to "General" when no category is set.
-func groupByTheme(articles []model.Article) map[string][]model.Article {
-	groups := make(map[string][]model.Article)
-	for _, a := range articles {
-		theme := "General"
-		if len(a.Categories) > 0 && strings.TrimSpace(a.Categories[0]) != "" {
-			theme = a.Categories[0]
-		}
-		groups[theme] = append(groups[theme], a)
+// compose runs the final digest prompt over input. In JSON output mode the
+// answer is validated and rendered to HTML by composeStructured.
+func (n *Notifier) compose(ctx context.Context, input, systemPrompt string, articles []model.Article) (summary.Result, error) {
+	if n.output == DigestOutputJSON {
+		return n.composeStructured(ctx, input, systemPrompt, articles)
 	}
-	return groups
+	return n.summarizer.Summarize(ctx, input, summary.WithSystemPrompt(systemPrompt))
 }
 
-// buildDigestInput constructs the structured text passed to the LLM.
-func buildDigestInput(greeting string, grouped map[string][]model.Article, maxDataLen int) string {
-	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("Time of day: %s\n\n", greeting))
-	sb.WriteString("Articles by topic:\n\n")
-
-	for theme, articles := range grouped {
-		sb.WriteString(fmt.Sprintf("Topic: %s\n", theme))
-		for _, a := range articles {
-			summary := a.Summary
-			if len(summary) > maxDataLen {
-				summary = summary[:maxDataLen] + "..."
-			}
-			if summary != "" {
-				sb.WriteString(fmt.Sprintf("- %s <%s> — %s\n", a.Title, a.Link, summary))
-			} else {
-				sb.WriteString(fmt.Sprintf("- %s <%s>\n", a.Title, a.Link))
-			}
-		}
-		sb.WriteString("\n")
+// useMapReduce reports whether the digest should be built hierarchically,
+// given the token count of the single-prompt input.
+func (n *Notifier) useMapReduce(tokens int) bool {
+	switch n.mode {
+	case DigestModeMapReduce:
+		return true
+	case DigestModeAuto:
+		return n.chunkTokens > 0 && tokens > n.chunkTokens
+	default:
+		return false
 	}
-
-	return sb.String()
 }
 
 // writeSummaryInput saves the LLM input text to a file in dir for inspection.
@@ -283,18 +606,53 @@ func writeSummaryInput(dir, filename, content string) {
 	}
 }
 
+// digestHeaders are the fallback digest headers by language and greeting.
+var digestHeaders = map[string]map[string]string{
+	"en": {
+		"morning":   "Good morning! Here's your tech news digest:",
+		"afternoon": "Good afternoon! Here's your tech news digest:",
+		"evening":   "Good evening! Here's your tech news digest:",
+	},
+	"ru": {
+		"morning":   "Доброе утро! Дайджест технологических новостей:",
+		"afternoon": "Добрый день! Дайджест технологических новостей:",
+		"evening":   "Добрый вечер! Дайджест технологических новостей:",
+	},
+}
+
+// digestHeader returns the fallback digest header for greeting in language,
+// falling back to English.
+func digestHeader(language, greeting string) string {
+	if h, ok := digestHeaders[language][greeting]; ok {
+		return h
+	}
+	if h, ok := digestHeaders["en"][greeting]; ok {
+		return h
+	}
+	return fmt.Sprintf("Good %s! Here's your tech news digest:", greeting)
+}
+
 // buildSimpleDigest is a plain HTML fallback when the LLM is unavailable.
-func buildSimpleDigest(greeting string, grouped map[string][]model.Article) string {
+func buildSimpleDigest(language, greeting string, grouped map[string][]model.Article) string {
 	var sb strings.Builder
-	sb.WriteString(fmt.Sprintf("Good %s! Here's your tech news digest:\n\n", greeting))
+	sb.WriteString(digestHeader(language, greeting) + "\n\n")
 
 	for theme, articles := range grouped {
 		sb.WriteString(fmt.Sprintf("<b>%s</b>\n", markup.EscapeForHTML(theme)))
 		for _, a := range articles {
-			sb.WriteString(fmt.Sprintf("• <a href=\"%s\">%s</a>\n", a.Link, markup.EscapeForHTML(a.Title)))
+			sb.WriteString(digestItem(a, ""))
 		}
 		sb.WriteString("\n")
 	}
 
 	return sb.String()
 }
+
+// digestItem renders one bullet of a digest, with an optional description.
+func digestItem(a model.Article, description string) string {
+	item := fmt.Sprintf("• <a href=\"%s\">%s</a>", a.Link, markup.EscapeForHTML(a.Title))
+	if description = strings.TrimSpace(description); description != "" {
+		item += " — " + markup.EscapeForHTML(description)
+	}
+	return item + "\n"
+}
@@ -0,0 +1,70 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x0BSoD/newsMaker/internal/summary"
+)
+
+// memCache is an in-memory summary.CacheStorage.
+type memCache map[string]string
+
+func (m memCache) Get(_ context.Context, key string) (string, bool, error) {
+	value, ok := m[key]
+	return value, ok, nil
+}
+
+func (m memCache) Put(_ context.Context, key, _, value string, _ time.Time) error {
+	m[key] = value
+	return nil
+}
+
+func (m memCache) DeleteExpired(context.Context) (int64, error) {
+	return 0, nil
+}
+
+func TestRetryContext(t *testing.T) {
+	// summarize primes the cache with the first reply and returns what a
+	// retry under ctx is served.
+	summarize := func(t *testing.T, retry func(ctx context.Context) context.Context) string {
+		s := &scriptedSummarizer{replies: []string{"first", "second"}}
+		cached := summary.NewCached(s, memCache{}, time.Hour)
+
+		_, err := cached.Summarize(context.Background(), "input")
+		require.NoError(t, err)
+
+		res, err := cached.Summarize(retry(context.Background()), "input")
+		require.NoError(t, err)
+		return res.Text
+	}
+
+	t.Run("should keep the cache after a send error", func(t *testing.T) {
+		text := summarize(t, func(ctx context.Context) context.Context {
+			return retryContext(ctx, fmt.Errorf("send digest: %w", errors.New("connection reset")))
+		})
+		assert.Equal(t, "first", text)
+	})
+
+	t.Run("should bypass the cache after Telegram refused the markup", func(t *testing.T) {
+		text := summarize(t, func(ctx context.Context) context.Context {
+			err := &tgbotapi.Error{Code: 400, Message: "Bad Request: can't parse entities: unclosed tag"}
+			return retryContext(ctx, fmt.Errorf("send digest: %w", err))
+		})
+		assert.Equal(t, "second", text)
+	})
+
+	t.Run("should bypass the cache after a rejected structured digest", func(t *testing.T) {
+		text := summarize(t, func(ctx context.Context) context.Context {
+			return retryContext(ctx, fmt.Errorf("structured digest still invalid after repair: %w", errOutputRejected))
+		})
+		assert.Equal(t, "second", text)
+	})
+}
@@ -0,0 +1,174 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/sashabaranov/go-openai/jsonschema"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit/markup"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+	"github.com/0x0BSoD/newsMaker/internal/summary"
+)
+
+const (
+	// DigestOutputHTML asks the model for Telegram HTML directly.
+	DigestOutputHTML = "html"
+	// DigestOutputJSON asks the model for a structuredDigest; the HTML is
+	// rendered in Go from the articles the model referenced.
+	DigestOutputJSON = "json"
+)
+
+// structuredDigest is the JSON shape the model must return in JSON output
+// mode. Articles are referenced by ID so the model cannot invent links.
+type structuredDigest struct {
+	Greeting string            `json:"greeting" description:"Short greeting matching the time of day"`
+	Topics   []structuredTopic `json:"topics"`
+}
+
+type structuredTopic struct {
+	Title    string           `json:"title" description:"Short topic header"`
+	Articles []structuredItem `json:"articles"`
+}
+
+type structuredItem struct {
+	ID          int64  `json:"id" description:"Article ID from the input"`
+	Description string `json:"description" description:"One-sentence description of the article"`
+}
+
+var digestSchema, digestSchemaJSON = mustDigestSchema()
+
+func mustDigestSchema() (jsonschema.Definition, json.RawMessage) {
+	def, err := jsonschema.GenerateSchemaForType(structuredDigest{})
+	if err != nil {
+		panic(fmt.Sprintf("digest schema: %v", err))
+	}
+	raw, err := json.Marshal(def)
+	if err != nil {
+		panic(fmt.Sprintf("digest schema: %v", err))
+	}
+	return *def, raw
+}
+
+// composeStructured asks for a structuredDigest, validates it against the
+// schema and the articles in the input, and renders it to HTML. An invalid
+// answer is sent back once with the validation error for repair. Only valid
+// answers are cached, and the repair always runs afresh.
+func (n *Notifier) composeStructured(ctx context.Context, input, systemPrompt string, articles []model.Article) (summary.Result, error) {
+	opts := []summary.Option{summary.WithSystemPrompt(systemPrompt), summary.WithJSONSchema(digestSchemaJSON)}
+	ctx = summary.WithCacheCheck(ctx, func(text string) error {
+		_, err := parseStructuredDigest(text, articles)
+		return err
+	})
+
+	res, err := n.summarizer.Summarize(ctx, input, opts...)
+	if err != nil {
+		return res, err
+	}
+	usage := res.Usage
+
+	digest, err := parseStructuredDigest(res.Text, articles)
+	if err != nil {
+		slog.Warn("structured digest invalid, asking for repair", "err", err)
+
+		res, err = n.summarizer.Summarize(summary.WithoutCache(ctx), buildRepairInput(input, res.Text, err), opts...)
+		usage.Add(res.Usage)
+		if err != nil {
+			return summary.Result{Usage: usage}, fmt.Errorf("repair structured digest: %w", err)
+		}
+		if digest, err = parseStructuredDigest(res.Text, articles); err != nil {
+			return summary.Result{Usage: usage}, fmt.Errorf("structured digest still invalid after repair: %w: %w", errOutputRejected, err)
+		}
+	}
+	writeSummaryInput(n.summaryInputDir, "digest_output.json", res.Text)
+
+	return summary.Result{
+		Text:  renderStructuredDigest(digest, articles),
+		Usage: usage,
+	}, nil
+}
+
+// parseStructuredDigest decodes text as a structuredDigest and checks it
+// against the schema and the IDs of articles.
+func parseStructuredDigest(text string, articles []model.Article) (structuredDigest, error) {
+	var d structuredDigest
+
+	text = strings.TrimSpace(text)
+	// Models without schema support tend to wrap JSON in a code fence.
+	text = strings.TrimPrefix(text, "```json")
+	text = strings.TrimPrefix(text, "```")
+	text = strings.TrimSuffix(text, "```")
+
+	if err := jsonschema.VerifySchemaAndUnmarshal(digestSchema, []byte(text), &d); err != nil {
+		return d, err
+	}
+
+	known := make(map[int64]bool, len(articles))
+	for _, a := range articles {
+		known[a.ID] = true
+	}
+
+	if strings.TrimSpace(d.Greeting) == "" {
+		return d, errors.New("greeting is empty")
+	}
+	if len(d.Topics) == 0 {
+		return d, errors.New("no topics")
+	}
+
+	seen := make(map[int64]bool)
+	for _, t := range d.Topics {
+		if strings.TrimSpace(t.Title) == "" {
+			return d, errors.New("topic with empty title")
+		}
+		if len(t.Articles) == 0 {
+			return d, fmt.Errorf("topic %q has no articles", t.Title)
+		}
+		for _, item := range t.Articles {
+			if !known[item.ID] {
+				return d, fmt.Errorf("topic %q references unknown article ID %d", t.Title, item.ID)
+			}
+			if seen[item.ID] {
+				return d, fmt.Errorf("article ID %d is listed more than once", item.ID)
+			}
+			seen[item.ID] = true
+		}
+	}
+
+	return d, nil
+}
+
+// buildRepairInput asks the model to fix its previous answer.
+func buildRepairInput(input, answer string, verr error) string {
+	return fmt.Sprintf(
+		"Your previous answer was rejected: %v\n\nPrevious answer:\n%s\n\n"+
+			"Reply again with corrected JSON only, using only article IDs from this input:\n\n%s",
+		verr, answer, input,
+	)
+}
+
+// renderStructuredDigest formats a validated digest the way buildSimpleDigest
+// does, taking links and titles from articles rather than from the model.
+func renderStructuredDigest(d structuredDigest, articles []model.Article) string {
+	byID := make(map[int64]model.Article, len(articles))
+	for _, a := range articles {
+		byID[a.ID] = a
+	}
+
+	var sb strings.Builder
+	sb.WriteString(markup.EscapeForHTML(strings.TrimSpace(d.Greeting)))
+	sb.WriteString("\n\n")
+
+	for _, t := range d.Topics {
+		sb.WriteString(fmt.Sprintf("<b>%s</b>\n", markup.EscapeForHTML(t.Title)))
+		for _, item := range t.Articles {
+			sb.WriteString(digestItem(byID[item.ID], item.Description))
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
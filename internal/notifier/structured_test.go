@@ -0,0 +1,105 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+	"github.com/0x0BSoD/newsMaker/internal/summary"
+)
+
+// scriptedSummarizer returns its replies in order, one per call.
+type scriptedSummarizer struct {
+	replies []string
+	inputs  []string
+}
+
+func (s *scriptedSummarizer) Summarize(_ context.Context, input string, _ ...summary.Option) (summary.Result, error) {
+	s.inputs = append(s.inputs, input)
+	reply := s.replies[0]
+	s.replies = s.replies[1:]
+	return summary.Result{Text: reply, Usage: summary.Usage{PromptTokens: 10, CompletionTokens: 5}}, nil
+}
+
+func (s *scriptedSummarizer) Stream(ctx context.Context, input string, _ summary.StreamFunc, opts ...summary.Option) (summary.Result, error) {
+	return s.Summarize(ctx, input, opts...)
+}
+
+func (s *scriptedSummarizer) CountTokens(text string) (int, error) {
+	return summary.EstimateTokens(text), nil
+}
+
+func (s *scriptedSummarizer) Model() string {
+	return "test"
+}
+
+var structuredArticles = []model.Article{
+	{ID: 1, Title: "Go 1.26 released", Link: "https://go.dev/blog/go1.26"},
+	{ID: 2, Title: "Rust <2027>", Link: "https://rust-lang.org/2027"},
+}
+
+const validDigestJSON = `{"greeting":"Good morning!","topics":[{"title":"Languages","articles":[` +
+	`{"id":1,"description":"New release."},{"id":2,"description":"Edition plans & more."}]}]}`
+
+func TestParseStructuredDigest(t *testing.T) {
+	t.Run("should accept a valid digest", func(t *testing.T) {
+		d, err := parseStructuredDigest(validDigestJSON, structuredArticles)
+		require.NoError(t, err)
+		assert.Equal(t, "Good morning!", d.Greeting)
+		require.Len(t, d.Topics, 1)
+		assert.Len(t, d.Topics[0].Articles, 2)
+	})
+
+	t.Run("should strip a code fence", func(t *testing.T) {
+		_, err := parseStructuredDigest("```json\n"+validDigestJSON+"\n```", structuredArticles)
+		assert.NoError(t, err)
+	})
+
+	t.Run("should reject unknown article IDs", func(t *testing.T) {
+		_, err := parseStructuredDigest(`{"greeting":"Hi","topics":[{"title":"T","articles":[{"id":42,"description":"x"}]}]}`, structuredArticles)
+		assert.ErrorContains(t, err, "unknown article ID 42")
+	})
+
+	t.Run("should reject JSON that does not match the schema", func(t *testing.T) {
+		_, err := parseStructuredDigest(`{"greeting":"Hi","topics":[{"title":"T"}]}`, structuredArticles)
+		assert.Error(t, err)
+
+		_, err = parseStructuredDigest(`<b>Good morning</b>`, structuredArticles)
+		assert.Error(t, err)
+	})
+}
+
+func TestRenderStructuredDigest(t *testing.T) {
+	d, err := parseStructuredDigest(validDigestJSON, structuredArticles)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Good morning!\n\n<b>Languages</b>\n"+
+		"• <a href=\"https://go.dev/blog/go1.26\">Go 1.26 released</a> — New release.\n"+
+		"• <a href=\"https://rust-lang.org/2027\">Rust &lt;2027&gt;</a> — Edition plans &amp; more.\n\n",
+		renderStructuredDigest(d, structuredArticles))
+}
+
+func TestNotifier_ComposeStructured(t *testing.T) {
+	t.Run("should repair an invalid answer once", func(t *testing.T) {
+		s := &scriptedSummarizer{replies: []string{`{"greeting":"Hi"}`, validDigestJSON}}
+		n := &Notifier{summarizer: s, summaryInputDir: t.TempDir()}
+
+		res, err := n.composeStructured(context.Background(), "input", "system", structuredArticles)
+		require.NoError(t, err)
+		assert.Contains(t, res.Text, "<b>Languages</b>")
+		assert.Equal(t, summary.Usage{PromptTokens: 20, CompletionTokens: 10}, res.Usage)
+		require.Len(t, s.inputs, 2)
+		assert.Contains(t, s.inputs[1], "Previous answer:\n{\"greeting\":\"Hi\"}")
+	})
+
+	t.Run("should fail when the repair is invalid too", func(t *testing.T) {
+		s := &scriptedSummarizer{replies: []string{`nope`, `still nope`}}
+		n := &Notifier{summarizer: s, summaryInputDir: t.TempDir()}
+
+		_, err := n.composeStructured(context.Background(), "input", "system", structuredArticles)
+		assert.ErrorContains(t, err, "after repair")
+	})
+}
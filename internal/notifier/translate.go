@@ -0,0 +1,82 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+	"github.com/0x0BSoD/newsMaker/internal/prompt"
+	"github.com/0x0BSoD/newsMaker/internal/summary"
+)
+
+// translateTitles translates the titles of the articles detected in another
+// language than language in one LLM call. Articles of unknown language are
+// left alone, and titles missing from the answer stay untranslated.
+func translateTitles(
+	ctx context.Context,
+	summarizer summary.Summarizer,
+	prompts PromptRenderer,
+	channelID int64,
+	language string,
+	articles []model.Article,
+) ([]model.Article, summary.Usage, error) {
+	var foreign []int
+	for i, a := range articles {
+		if a.Language != "" && a.Language != language {
+			foreign = append(foreign, i)
+		}
+	}
+	if len(foreign) == 0 {
+		return articles, summary.Usage{}, nil
+	}
+
+	systemPrompt, err := prompts.Render(ctx, prompt.TitleTranslation, prompt.Data{
+		Channel:  channelID,
+		Language: language,
+	})
+	if err != nil {
+		return articles, summary.Usage{}, fmt.Errorf("render translation prompt: %w", err)
+	}
+
+	var sb strings.Builder
+	for n, i := range foreign {
+		sb.WriteString(fmt.Sprintf("%d. %s\n", n+1, articles[i].Title))
+	}
+
+	res, err := summarizer.Summarize(ctx, sb.String(), summary.WithSystemPrompt(systemPrompt))
+	if err != nil {
+		return articles, res.Usage, err
+	}
+
+	translated := parseNumberedLines(res.Text)
+	out := make([]model.Article, len(articles))
+	copy(out, articles)
+	for n, i := range foreign {
+		if title := translated[n+1]; title != "" {
+			out[i].Title = title
+		}
+	}
+	return out, res.Usage, nil
+}
+
+// parseNumberedLines reads lines of the form "N. text", keyed by N. Other
+// lines are skipped.
+func parseNumberedLines(text string) map[int]string {
+	lines := make(map[int]string)
+	for _, line := range strings.Split(text, "\n") {
+		num, rest, ok := strings.Cut(strings.TrimSpace(line), ".")
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(num))
+		if err != nil {
+			continue
+		}
+		if rest = strings.TrimSpace(rest); rest != "" {
+			lines[n] = rest
+		}
+	}
+	return lines
+}
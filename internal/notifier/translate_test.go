@@ -0,0 +1,61 @@
+package notifier
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+	"github.com/0x0BSoD/newsMaker/internal/prompt"
+)
+
+type staticPrompts struct{}
+
+func (staticPrompts) Render(context.Context, string, prompt.Data) (string, error) {
+	return "translate", nil
+}
+
+func TestTranslateTitles(t *testing.T) {
+	articles := []model.Article{
+		{ID: 1, Title: "Go 1.26 released", Language: "en"},
+		{ID: 2, Title: "Вышел Go 1.26", Language: "ru"},
+		{ID: 3, Title: "Kubernetes 2.0", Language: "en"},
+		{ID: 4, Title: "v1.2.3"},
+	}
+
+	t.Run("should translate only foreign titles", func(t *testing.T) {
+		s := &scriptedSummarizer{replies: []string{"1. Вышел Go 1.26\n2. Kubernetes 2.0 вышел"}}
+		out, usage, err := translateTitles(context.Background(), s, staticPrompts{}, 1, "ru", articles)
+		require.NoError(t, err)
+		assert.Equal(t, "1. Go 1.26 released\n2. Kubernetes 2.0\n", s.inputs[0])
+		assert.Equal(t, "Вышел Go 1.26", out[0].Title)
+		assert.Equal(t, "Вышел Go 1.26", out[1].Title)
+		assert.Equal(t, "Kubernetes 2.0 вышел", out[2].Title)
+		assert.Equal(t, "v1.2.3", out[3].Title)
+		assert.Equal(t, 10, usage.PromptTokens)
+		assert.Equal(t, "Go 1.26 released", articles[0].Title)
+	})
+
+	t.Run("should keep titles missing from the answer", func(t *testing.T) {
+		s := &scriptedSummarizer{replies: []string{"Sure!\n2. Kubernetes 2.0 вышел"}}
+		out, _, err := translateTitles(context.Background(), s, staticPrompts{}, 1, "ru", articles)
+		require.NoError(t, err)
+		assert.Equal(t, "Go 1.26 released", out[0].Title)
+		assert.Equal(t, "Kubernetes 2.0 вышел", out[2].Title)
+	})
+
+	t.Run("should skip the call when nothing is foreign", func(t *testing.T) {
+		s := &scriptedSummarizer{}
+		out, _, err := translateTitles(context.Background(), s, staticPrompts{}, 1, "en", articles[:1])
+		require.NoError(t, err)
+		assert.Equal(t, articles[:1], out)
+		assert.Empty(t, s.inputs)
+	})
+}
+
+func TestDigestHeader(t *testing.T) {
+	assert.Equal(t, "Добрый вечер! Дайджест технологических новостей:", digestHeader("ru", "evening"))
+	assert.Equal(t, "Good morning! Here's your tech news digest:", digestHeader("de", "morning"))
+}
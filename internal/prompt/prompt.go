@@ -0,0 +1,272 @@
+// Package prompt renders the LLM prompts from named text/template templates.
+// Templates are versioned in the database; the built-in defaults are used
+// for any name that has no active version there.
+package prompt
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+	"github.com/0x0BSoD/newsMaker/internal/storage"
+)
+
+const (
+	NewsDigestSystem = "news_digest_system"
+	NewsDigestInput  = "news_digest_input"
+	// NewsDigestMap is the system prompt of the map-reduce map step, whose
+	// input lists one NewsDigestArticle line per article.
+	NewsDigestMap      = "news_digest_map"
+	NewsDigestArticle  = "news_digest_article"
+	GitHubDigestSystem = "github_digest_system"
+	GitHubDigestInput  = "github_digest_input"
+
+	// The JSON variants are used when the news digest is generated as
+	// structured output; their input lists article IDs.
+	NewsDigestJSONSystem  = "news_digest_json_system"
+	NewsDigestJSONInput   = "news_digest_json_input"
+	NewsDigestJSONMap     = "news_digest_json_map"
+	NewsDigestJSONArticle = "news_digest_json_article"
+
+	// TopicLabel is the system prompt used to name embedding clusters.
+	TopicLabel = "topic_label"
+	// BreakingSummary is the system prompt of breaking news posts.
+	BreakingSummary = "breaking_summary"
+	// ArticleSummary is the system prompt of per-article posts.
+	ArticleSummary = "article_summary"
+	// TitleTranslation is the system prompt that translates headlines into
+	// the channel language.
+	TitleTranslation = "title_translation"
+	// The weekly recap input lists the week's top stories, most important
+	// first.
+	WeeklyRecapSystem = "weekly_recap_system"
+	WeeklyRecapInput  = "weekly_recap_input"
+)
+
+//go:embed templates/*.tmpl
+var builtin embed.FS
+
+// Data is what templates are executed against. News digest templates see
+// Articles and Groups, and the per-article templates Article; GitHub digest
+// templates see Topic, NewRepos and Trending.
+type Data struct {
+	Slot     string
+	Channel  int64
+	Language string
+	Articles []model.Article
+	Groups   []Group
+	Article  model.Article
+	// MaxDataLen is the per-article summary limit in runes, for use with
+	// the truncate function.
+	MaxDataLen int
+
+	Topic    string
+	NewRepos []storage.GitHubRepo
+	Trending []storage.GitHubRepo
+}
+
+// Group is the articles of one topic.
+type Group struct {
+	Topic    string
+	Articles []model.Article
+}
+
+// GroupArticles turns articles grouped by topic into Groups sorted by topic,
+// so the rendered prompt is stable between runs.
+func GroupArticles(grouped map[string][]model.Article) []Group {
+	groups := make([]Group, 0, len(grouped))
+	for topic, articles := range grouped {
+		groups = append(groups, Group{Topic: topic, Articles: articles})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Topic < groups[j].Topic })
+	return groups
+}
+
+// Store is satisfied by storage.PromptPostgresStorage.
+type Store interface {
+	ActivePrompt(ctx context.Context, name string) (*model.PromptTemplate, error)
+	PromptVersion(ctx context.Context, name string, version int) (*model.PromptTemplate, error)
+}
+
+type versionsKey struct{}
+
+// WithVersions returns a context in which Render uses the given template
+// versions instead of the active ones, keyed by template name.
+func WithVersions(ctx context.Context, versions map[string]int) context.Context {
+	return context.WithValue(ctx, versionsKey{}, versions)
+}
+
+func versionFromContext(ctx context.Context, name string) int {
+	versions, _ := ctx.Value(versionsKey{}).(map[string]int)
+	return versions[name]
+}
+
+type Library struct {
+	store    Store
+	defaults map[string]string
+}
+
+// NewLibrary creates a Library backed by store. defaults override the
+// built-in default templates by name; empty values are ignored.
+func NewLibrary(store Store, defaults map[string]string) *Library {
+	l := &Library{
+		store:    store,
+		defaults: make(map[string]string),
+	}
+
+	entries, _ := builtin.ReadDir("templates")
+	for _, e := range entries {
+		body, err := builtin.ReadFile("templates/" + e.Name())
+		if err != nil {
+			continue
+		}
+		l.defaults[strings.TrimSuffix(e.Name(), ".tmpl")] = string(body)
+	}
+	for name, body := range defaults {
+		if body != "" {
+			l.defaults[name] = body
+		}
+	}
+
+	return l
+}
+
+// Names returns the names of all known templates.
+func (l *Library) Names() []string {
+	names := make([]string, 0, len(l.defaults))
+	for name := range l.defaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Default returns the built-in template for name.
+func (l *Library) Default(name string) (string, bool) {
+	body, ok := l.defaults[name]
+	return body, ok
+}
+
+// Render executes the named template against data. A version requested via
+// WithVersions must exist and render cleanly. Otherwise the active version is
+// used, and the default template when there is none or it fails, so a broken
+// edit cannot stop the digest.
+func (l *Library) Render(ctx context.Context, name string, data Data) (string, error) {
+	def, ok := l.defaults[name]
+	if !ok {
+		return "", fmt.Errorf("unknown prompt %q", name)
+	}
+
+	if version := versionFromContext(ctx, name); version > 0 {
+		if l.store == nil {
+			return "", fmt.Errorf("prompt %s has no version %d", name, version)
+		}
+		p, err := l.store.PromptVersion(ctx, name, version)
+		if err != nil {
+			return "", fmt.Errorf("load prompt %s v%d: %w", name, version, err)
+		}
+		if p == nil {
+			return "", fmt.Errorf("prompt %s has no version %d", name, version)
+		}
+		return execute(name, p.Body, data)
+	}
+
+	if l.store != nil {
+		p, err := l.store.ActivePrompt(ctx, name)
+		if err != nil {
+			slog.Warn("load prompt failed, using default", "name", name, "err", err)
+		}
+		if p != nil {
+			out, err := execute(name, p.Body, data)
+			if err == nil {
+				return out, nil
+			}
+			slog.Error("prompt template failed, using default", "name", name, "version", p.Version, "err", err)
+		}
+	}
+
+	return execute(name, def, data)
+}
+
+// Validate parses body and executes it against sample data, so template
+// errors surface when a template is saved rather than at digest time.
+func Validate(name, body string) error {
+	_, err := execute(name, body, sampleData())
+	return err
+}
+
+func execute(name, body string, data Data) (string, error) {
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(body)
+	if err != nil {
+		return "", fmt.Errorf("parse prompt %s: %w", name, err)
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("execute prompt %s: %w", name, err)
+	}
+	return sb.String(), nil
+}
+
+var funcs = template.FuncMap{
+	"truncate": Truncate,
+	"join":     strings.Join,
+	"growth":   growth,
+}
+
+// Truncate shortens s to at most maxRunes runes, appending an ellipsis when
+// anything was cut. It never splits a multi-byte rune.
+func Truncate(s string, maxRunes int) string {
+	if maxRunes <= 0 || utf8.RuneCountInString(s) <= maxRunes {
+		return s
+	}
+	return string([]rune(s)[:maxRunes]) + "..."
+}
+
+// growth formats a repo's star growth since the last digest, e.g. "+42%".
+func growth(r storage.GitHubRepo) string {
+	if r.StarsAtLastDigest == nil || *r.StarsAtLastDigest <= 0 {
+		return ""
+	}
+	prev := *r.StarsAtLastDigest
+	return fmt.Sprintf("+%.0f%%", float64(r.Stars-prev)/float64(prev)*100)
+}
+
+func sampleData() Data {
+	article := model.Article{
+		ID:          1,
+		Title:       "Sample article",
+		Link:        "https://example.com/article",
+		Summary:     "Sample summary.",
+		Categories:  []string{"General"},
+		PublishedAt: time.Now(),
+	}
+	prev := 100
+	repo := storage.GitHubRepo{
+		FullName:          "example/repo",
+		Topic:             "go",
+		Stars:             150,
+		StarsAtLastDigest: &prev,
+		Language:          "Go",
+		Description:       "Sample repository.",
+	}
+
+	return Data{
+		Slot:       "morning",
+		Language:   "en",
+		Articles:   []model.Article{article},
+		Groups:     []Group{{Topic: "General", Articles: []model.Article{article}}},
+		Article:    article,
+		MaxDataLen: 500,
+		Topic:      "go",
+		NewRepos:   []storage.GitHubRepo{repo},
+		Trending:   []storage.GitHubRepo{repo},
+	}
+}
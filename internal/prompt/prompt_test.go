@@ -0,0 +1,136 @@
+package prompt
+
+import (
+	"context"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+	"github.com/0x0BSoD/newsMaker/internal/storage"
+)
+
+// memStore holds prompt versions by name; the last one is active.
+type memStore map[string][]string
+
+func (m memStore) ActivePrompt(_ context.Context, name string) (*model.PromptTemplate, error) {
+	versions := m[name]
+	if len(versions) == 0 {
+		return nil, nil
+	}
+	return &model.PromptTemplate{Name: name, Version: len(versions), Body: versions[len(versions)-1], Active: true}, nil
+}
+
+func (m memStore) PromptVersion(_ context.Context, name string, version int) (*model.PromptTemplate, error) {
+	versions := m[name]
+	if version < 1 || version > len(versions) {
+		return nil, nil
+	}
+	return &model.PromptTemplate{Name: name, Version: version, Body: versions[version-1]}, nil
+}
+
+func TestLibrary_Render(t *testing.T) {
+	t.Run("should render the default news digest input", func(t *testing.T) {
+		lib := NewLibrary(nil, nil)
+		data := Data{
+			Slot: "morning",
+			Groups: GroupArticles(map[string][]model.Article{
+				"Go":   {{Title: "Go 1.25", Link: "https://go.dev", Summary: "Released today"}},
+				"Rust": {{Title: "Rust 2.0", Link: "https://rust-lang.org"}},
+			}),
+			MaxDataLen: 8,
+		}
+
+		out, err := lib.Render(context.Background(), NewsDigestInput, data)
+		require.NoError(t, err)
+		assert.Equal(t, "Time of day: morning\n\nArticles by topic:\n\n"+
+			"Topic: Go\n- Go 1.25 <https://go.dev> — Released...\n\n"+
+			"Topic: Rust\n- Rust 2.0 <https://rust-lang.org>\n\n", out)
+	})
+
+	t.Run("should name the digest language in the news digest input", func(t *testing.T) {
+		lib := NewLibrary(nil, nil)
+		out, err := lib.Render(context.Background(), NewsDigestInput, Data{Slot: "evening", Language: "ru"})
+		require.NoError(t, err)
+		assert.Equal(t, "Time of day: evening\nLanguage: ru\n\nArticles by topic:\n\n", out)
+	})
+
+	t.Run("should render the default article lines", func(t *testing.T) {
+		lib := NewLibrary(nil, nil)
+		data := Data{
+			Article:    model.Article{ID: 7, Title: "Go 1.25", Link: "https://go.dev", Summary: "Released today"},
+			MaxDataLen: 8,
+		}
+
+		out, err := lib.Render(context.Background(), NewsDigestArticle, data)
+		require.NoError(t, err)
+		assert.Equal(t, "- Go 1.25 <https://go.dev> — Released...", out)
+
+		out, err = lib.Render(context.Background(), NewsDigestJSONArticle, data)
+		require.NoError(t, err)
+		assert.Equal(t, "- [7] Go 1.25 — Released...", out)
+	})
+
+	t.Run("should render the default github digest input", func(t *testing.T) {
+		lib := NewLibrary(nil, nil)
+		prev := 100
+		data := Data{
+			Topic:    "go",
+			NewRepos: []storage.GitHubRepo{{FullName: "a/new", Stars: 10, Description: "new repo"}},
+			Trending: []storage.GitHubRepo{{FullName: "b/hot", Stars: 150, StarsAtLastDigest: &prev, Language: "Go", Description: "hot repo"}},
+		}
+
+		out, err := lib.Render(context.Background(), GitHubDigestInput, data)
+		require.NoError(t, err)
+		assert.Equal(t, "Topic: go\n\nNew repos:\n- a/new (10 stars, unknown): new repo\n"+
+			"\nTrending (significant star growth):\n- b/hot (150 stars +50%, Go): hot repo\n", out)
+	})
+
+	t.Run("should prefer the active version over the default", func(t *testing.T) {
+		lib := NewLibrary(memStore{NewsDigestSystem: {"v1 {{.Slot}}", "v2 {{.Slot}}"}}, map[string]string{NewsDigestSystem: "default"})
+
+		out, err := lib.Render(context.Background(), NewsDigestSystem, Data{Slot: "evening"})
+		require.NoError(t, err)
+		assert.Equal(t, "v2 evening", out)
+	})
+
+	t.Run("should render the version requested in the context", func(t *testing.T) {
+		lib := NewLibrary(memStore{NewsDigestSystem: {"v1", "v2"}}, map[string]string{NewsDigestSystem: "default"})
+		ctx := WithVersions(context.Background(), map[string]int{NewsDigestSystem: 1})
+
+		out, err := lib.Render(ctx, NewsDigestSystem, Data{})
+		require.NoError(t, err)
+		assert.Equal(t, "v1", out)
+
+		_, err = lib.Render(WithVersions(context.Background(), map[string]int{NewsDigestSystem: 7}), NewsDigestSystem, Data{})
+		assert.Error(t, err)
+	})
+
+	t.Run("should fall back to the default when the active version fails", func(t *testing.T) {
+		lib := NewLibrary(memStore{NewsDigestSystem: {"{{.Missing}}"}}, map[string]string{NewsDigestSystem: "default"})
+
+		out, err := lib.Render(context.Background(), NewsDigestSystem, Data{})
+		require.NoError(t, err)
+		assert.Equal(t, "default", out)
+	})
+}
+
+func TestTruncate(t *testing.T) {
+	t.Run("should keep short text as is", func(t *testing.T) {
+		assert.Equal(t, "привет", Truncate("привет", 10))
+	})
+
+	t.Run("should not split multi-byte runes", func(t *testing.T) {
+		got := Truncate("Выйдя на пенсию", 3)
+		assert.True(t, utf8.ValidString(got))
+		assert.Equal(t, "Вый...", got)
+	})
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate("t", "{{range .Groups}}{{.Topic}}{{end}} in {{.Language}}"))
+	assert.Error(t, Validate("t", "{{range .Groups}}"))
+	assert.Error(t, Validate("t", "{{.Nope}}"))
+}
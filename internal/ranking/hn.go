@@ -0,0 +1,135 @@
+package ranking
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+const (
+	hnSearchURL    = "https://hn.algolia.com/api/v1/search"
+	hnTimeout      = 5 * time.Second
+	hnCacheTTL     = time.Hour
+	hnMaxLookups   = 50
+	hnLookupBudget = 30 * time.Second
+)
+
+// HNSignal looks up Hacker News points of article links through the Algolia
+// HN search API. Results are cached for an hour.
+type HNSignal struct {
+	client  *http.Client
+	baseURL string
+
+	mu    sync.Mutex
+	cache map[string]hnEntry
+}
+
+type hnEntry struct {
+	points  int
+	fetched time.Time
+}
+
+func NewHNSignal() *HNSignal {
+	return &HNSignal{
+		client:  &http.Client{Timeout: hnTimeout},
+		baseURL: hnSearchURL,
+		cache:   make(map[string]hnEntry),
+	}
+}
+
+// Points returns the points of the HN submissions of the articles' links.
+// At most hnMaxLookups uncached links are looked up per call; the rest count
+// as unknown.
+func (h *HNSignal) Points(ctx context.Context, articles []model.Article) (map[int64]int, error) {
+	ctx, cancel := context.WithTimeout(ctx, hnLookupBudget)
+	defer cancel()
+
+	points := make(map[int64]int, len(articles))
+	lookups := 0
+	for _, a := range articles {
+		if p, ok := h.cached(a.Link); ok {
+			points[a.ID] = p
+			continue
+		}
+		if lookups >= hnMaxLookups || ctx.Err() != nil {
+			continue
+		}
+		lookups++
+
+		p, err := h.lookup(ctx, a.Link)
+		if err != nil {
+			slog.Debug("hn lookup failed", "link", a.Link, "err", err)
+			continue
+		}
+		h.store(a.Link, p)
+		points[a.ID] = p
+	}
+
+	return points, nil
+}
+
+func (h *HNSignal) cached(link string) (int, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	e, ok := h.cache[link]
+	if !ok || time.Since(e.fetched) > hnCacheTTL {
+		return 0, false
+	}
+	return e.points, true
+}
+
+func (h *HNSignal) store(link string, points int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.cache[link] = hnEntry{points: points, fetched: time.Now()}
+}
+
+func (h *HNSignal) lookup(ctx context.Context, link string) (int, error) {
+	q := url.Values{}
+	q.Set("query", link)
+	q.Set("restrictSearchableAttributes", "url")
+	q.Set("hitsPerPage", "5")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.baseURL+"?"+q.Encode(), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("hn search: status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Hits []struct {
+			URL    string `json:"url"`
+			Points int    `json:"points"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return 0, fmt.Errorf("hn search: %w", err)
+	}
+
+	// The same link may have been submitted several times; take the best.
+	var best int
+	for _, hit := range result.Hits {
+		if hit.URL == link && hit.Points > best {
+			best = hit.Points
+		}
+	}
+	return best, nil
+}
@@ -0,0 +1,371 @@
+// Package ranking scores digest candidates and selects the articles to post.
+// Every score is the sum of named components so the choice can be explained.
+package ranking
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/0x0BSoD/newsMaker/internal/cluster"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+// storySimilarity is the title word Jaccard similarity from which two
+// articles are treated as coverage of the same story. It is high on purpose:
+// titles differing in a name or a version number are different stories.
+const storySimilarity = 0.8
+
+// Reasons a candidate was picked or dropped.
+const (
+	ReasonPicked    = "picked"
+	ReasonSourceCap = "source cap reached"
+	ReasonDuplicate = "same story as a higher-scored article"
+	ReasonPosted    = "same story as a recent post"
+	ReasonCutoff    = "below cutoff"
+)
+
+// Weights scale the score components. A zero weight disables its component.
+type Weights struct {
+	Priority float64
+	Recency  float64
+	// HalfLife is the age at which the recency component halves.
+	HalfLife time.Duration
+	// Coverage is added per additional source covering the same story.
+	Coverage float64
+	// HN multiplies log(1 + Hacker News points).
+	HN float64
+}
+
+// Keyword boosts articles whose title or summary contains Term.
+type Keyword struct {
+	Term  string
+	Boost float64
+}
+
+// ParseKeywords parses boosts of the form "term=boost", e.g. "kubernetes=2".
+// Negative boosts demote matching articles.
+func ParseKeywords(entries []string) ([]Keyword, error) {
+	keywords := make([]Keyword, 0, len(entries))
+	for _, entry := range entries {
+		term, boost, ok := strings.Cut(entry, "=")
+		term = strings.ToLower(strings.TrimSpace(term))
+		if !ok || term == "" {
+			return nil, fmt.Errorf("invalid keyword boost %q, want term=boost", entry)
+		}
+		b, err := strconv.ParseFloat(strings.TrimSpace(boost), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid keyword boost %q: %w", entry, err)
+		}
+		keywords = append(keywords, Keyword{Term: term, Boost: b})
+	}
+	return keywords, nil
+}
+
+// Clusterer groups articles by their embeddings; see cluster.Grouper.
+type Clusterer interface {
+	Clusters(ctx context.Context, articles []model.Article) ([][]int, error)
+}
+
+// Signal provides external popularity data, keyed by article ID.
+type Signal interface {
+	Points(ctx context.Context, articles []model.Article) (map[int64]int, error)
+}
+
+// Score is the scored and explained ranking of one candidate.
+type Score struct {
+	Article model.Article
+	Total   float64
+
+	Priority float64
+	Recency  float64
+	Coverage float64
+	Keywords float64
+	HN       float64
+
+	// Sources is the number of distinct sources covering the story.
+	Sources int
+	// Matched lists the keywords found in the article.
+	Matched []string
+	// Points are the Hacker News points, 0 when unknown.
+	Points int
+
+	Picked bool
+	Reason string
+}
+
+// Explain renders the components of s on one line.
+func (s Score) Explain() string {
+	parts := []string{fmt.Sprintf("priority %+.2f", s.Priority), fmt.Sprintf("recency %+.2f", s.Recency)}
+	if s.Coverage != 0 {
+		parts = append(parts, fmt.Sprintf("coverage %+.2f (%d sources)", s.Coverage, s.Sources))
+	}
+	if s.Keywords != 0 {
+		parts = append(parts, fmt.Sprintf("keywords %+.2f (%s)", s.Keywords, strings.Join(s.Matched, ", ")))
+	}
+	if s.HN != 0 {
+		parts = append(parts, fmt.Sprintf("hn %+.2f (%d points)", s.HN, s.Points))
+	}
+	return fmt.Sprintf("%.2f = %s", s.Total, strings.Join(parts, ", "))
+}
+
+type Ranker struct {
+	weights      Weights
+	keywords     []Keyword
+	perSourceCap int
+	signal       Signal
+	clusterer    Clusterer
+	now          func() time.Time
+}
+
+// NewRanker creates a Ranker. perSourceCap limits picked articles per source;
+// 0 means no limit. signal may be nil. Stories are found with StoryClusters
+// over clusterer, which may be nil too.
+func NewRanker(weights Weights, keywords []Keyword, perSourceCap int, signal Signal, clusterer Clusterer) *Ranker {
+	return &Ranker{
+		weights:      weights,
+		keywords:     keywords,
+		perSourceCap: perSourceCap,
+		signal:       signal,
+		clusterer:    clusterer,
+		now:          time.Now,
+	}
+}
+
+// Rank scores all candidates and marks the top n as picked, skipping
+// articles over the per-source cap, further coverage of a story that is
+// already picked and coverage of a story in posted, the recently posted
+// articles. The result holds every candidate, best first.
+func (r *Ranker) Rank(ctx context.Context, articles, posted []model.Article, n int) []Score {
+	var points map[int64]int
+	if r.signal != nil && r.weights.HN != 0 {
+		var err error
+		if points, err = r.signal.Points(ctx, articles); err != nil {
+			slog.Warn("ranking signal failed, ignoring it", "err", err)
+		}
+	}
+
+	stories := StoryClusters(ctx, r.clusterer, articles)
+	storySources := make([]int, len(articles))
+	for _, members := range stories {
+		sources := make(map[int64]bool)
+		for _, i := range members {
+			sources[articles[i].SourceID] = true
+		}
+		for _, i := range members {
+			storySources[i] = len(sources)
+		}
+	}
+	storyOf := make([]int, len(articles))
+	for s, members := range stories {
+		for _, i := range members {
+			storyOf[i] = s
+		}
+	}
+
+	now := r.now()
+	scores := make([]Score, len(articles))
+	for i, a := range articles {
+		scores[i] = r.score(a, now, storySources[i], points[a.ID])
+	}
+
+	order := make([]int, len(scores))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool { return scores[order[i]].Total > scores[order[j]].Total })
+
+	var (
+		picked       int
+		perSource    = make(map[int64]int)
+		pickedStory  = make(map[int]bool)
+		rankedScores = make([]Score, 0, len(scores))
+	)
+	for _, i := range order {
+		s := scores[i]
+		switch {
+		case coversAny(s.Article, posted):
+			s.Reason = ReasonPosted
+		case pickedStory[storyOf[i]]:
+			s.Reason = ReasonDuplicate
+		case picked >= n:
+			s.Reason = ReasonCutoff
+		case r.perSourceCap > 0 && perSource[s.Article.SourceID] >= r.perSourceCap:
+			s.Reason = ReasonSourceCap
+		default:
+			s.Picked, s.Reason = true, ReasonPicked
+			picked++
+			perSource[s.Article.SourceID]++
+			pickedStory[storyOf[i]] = true
+		}
+		rankedScores = append(rankedScores, s)
+	}
+
+	return rankedScores
+}
+
+func (r *Ranker) score(a model.Article, now time.Time, sources, points int) Score {
+	s := Score{Article: a, Sources: sources, Points: points}
+
+	s.Priority = r.weights.Priority * float64(a.SourcePriority)
+
+	if r.weights.HalfLife > 0 {
+		age := now.Sub(a.PublishedAt)
+		if age < 0 {
+			age = 0
+		}
+		s.Recency = r.weights.Recency * math.Exp2(-age.Hours()/r.weights.HalfLife.Hours())
+	}
+
+	if sources > 1 {
+		s.Coverage = r.weights.Coverage * float64(sources-1)
+	}
+
+	text := strings.ToLower(a.Title + " " + a.Summary)
+	for _, k := range r.keywords {
+		if strings.Contains(text, k.Term) {
+			s.Keywords += k.Boost
+			s.Matched = append(s.Matched, k.Term)
+		}
+	}
+
+	if points > 0 {
+		s.HN = r.weights.HN * math.Log1p(float64(points))
+	}
+
+	s.Total = s.Priority + s.Recency + s.Coverage + s.Keywords + s.HN
+	return s
+}
+
+// Picked returns the picked articles in rank order.
+func Picked(scores []Score) []model.Article {
+	var articles []model.Article
+	for _, s := range scores {
+		if s.Picked {
+			articles = append(articles, s.Article)
+		}
+	}
+	return articles
+}
+
+// SameStory reports whether a and b are coverage of the same story: they
+// link to the same page, or their titles share nearly all of their words.
+func SameStory(a, b model.Article) bool {
+	if la, lb := canonicalLink(a.Link), canonicalLink(b.Link); la != "" && la == lb {
+		return true
+	}
+	return jaccard(titleTokens(a.Title), titleTokens(b.Title)) >= storySimilarity
+}
+
+// StoryClusters groups articles into stories: the embedding clusters of c,
+// so the digest and the weekly recap agree on what one story is, or coverage
+// of the same story as found by Stories when c is nil, embeddings are off or
+// clustering fails.
+func StoryClusters(ctx context.Context, c Clusterer, articles []model.Article) [][]int {
+	if c != nil {
+		clusters, err := c.Clusters(ctx, articles)
+		if err == nil {
+			return clusters
+		}
+		if !errors.Is(err, cluster.ErrNoEmbedder) {
+			slog.Warn("clustering articles failed, grouping by story", "err", err)
+		}
+	}
+	return Stories(articles)
+}
+
+// Stories clusters coverage of the same story, returning the indexes of each
+// story's articles. An article joins a story only if it is the same story as
+// every article already in it.
+func Stories(articles []model.Article) [][]int {
+	var stories [][]int
+	for i := range articles {
+		joined := false
+		for s, members := range stories {
+			same := true
+			for _, m := range members {
+				if !SameStory(articles[m], articles[i]) {
+					same = false
+					break
+				}
+			}
+			if same {
+				stories[s] = append(stories[s], i)
+				joined = true
+				break
+			}
+		}
+		if !joined {
+			stories = append(stories, []int{i})
+		}
+	}
+	return stories
+}
+
+func coversAny(a model.Article, articles []model.Article) bool {
+	for _, other := range articles {
+		if other.ID != a.ID && SameStory(a, other) {
+			return true
+		}
+	}
+	return false
+}
+
+// titleTokens returns the lower-cased words of title. Short words are kept:
+// in "Go 1.26 released" the name and the version are what tell stories apart.
+func titleTokens(title string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '.'
+	})
+	tokens := make(map[string]bool, len(words))
+	for _, w := range words {
+		if w = strings.Trim(w, "."); w != "" {
+			tokens[w] = true
+		}
+	}
+	return tokens
+}
+
+// canonicalLink drops the scheme, a leading "www.", utm_* tracking
+// parameters, the fragment and a trailing slash, so they do not hide a shared
+// link.
+func canonicalLink(link string) string {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || u.Host == "" {
+		return ""
+	}
+
+	query := u.Query()
+	for key := range query {
+		if strings.HasPrefix(strings.ToLower(key), "utm_") {
+			query.Del(key)
+		}
+	}
+
+	canonical := strings.TrimPrefix(strings.ToLower(u.Host), "www.") + strings.TrimSuffix(u.Path, "/")
+	if encoded := query.Encode(); encoded != "" {
+		canonical += "?" + encoded
+	}
+	return canonical
+}
+
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	var inter int
+	for w := range a {
+		if b[w] {
+			inter++
+		}
+	}
+	return float64(inter) / float64(len(a)+len(b)-inter)
+}
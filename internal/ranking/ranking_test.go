@@ -0,0 +1,215 @@
+package ranking
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x0BSoD/newsMaker/internal/cluster"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+var testNow = time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+func testRanker(keywords []Keyword, perSourceCap int, signal Signal) *Ranker {
+	r := NewRanker(Weights{Priority: 1, Recency: 3, HalfLife: 6 * time.Hour, Coverage: 2, HN: 1}, keywords, perSourceCap, signal, nil)
+	r.now = func() time.Time { return testNow }
+	return r
+}
+
+func article(id, sourceID int64, title string, age time.Duration) model.Article {
+	return model.Article{ID: id, SourceID: sourceID, Title: title, PublishedAt: testNow.Add(-age)}
+}
+
+type staticSignal map[int64]int
+
+func (s staticSignal) Points(_ context.Context, _ []model.Article) (map[int64]int, error) {
+	return s, nil
+}
+
+func TestRanker_Rank(t *testing.T) {
+	t.Run("should decay recency with age", func(t *testing.T) {
+		scores := testRanker(nil, 0, nil).Rank(context.Background(), []model.Article{
+			article(1, 1, "Old news", 12*time.Hour),
+			article(2, 2, "Fresh news", 0),
+		}, nil, 10)
+
+		require.Len(t, scores, 2)
+		assert.Equal(t, int64(2), scores[0].Article.ID)
+		assert.InDelta(t, 3.0, scores[0].Recency, 1e-9)
+		assert.InDelta(t, 0.75, scores[1].Recency, 1e-9)
+	})
+
+	t.Run("should cap picked articles per source", func(t *testing.T) {
+		scores := testRanker(nil, 2, nil).Rank(context.Background(), []model.Article{
+			article(1, 1, "Alpha release", 0),
+			article(2, 1, "Beta launch", time.Hour),
+			article(3, 1, "Gamma update", 2*time.Hour),
+			article(4, 2, "Delta outage", 3*time.Hour),
+		}, nil, 10)
+
+		assert.Equal(t, []int64{1, 2, 4}, pickedIDs(scores))
+		assert.Equal(t, ReasonSourceCap, reasonOf(scores, 3))
+	})
+
+	t.Run("should count coverage from the embedding clusters", func(t *testing.T) {
+		r := testRanker(nil, 0, nil)
+		r.clusterer = staticClusterer{clusters: [][]int{{0, 1}, {2}}}
+		scores := r.Rank(context.Background(), []model.Article{
+			article(1, 1, "Kubernetes 1.34 ships sidecars", time.Hour),
+			article(2, 2, "Sidecar containers are stable in the new Kubernetes", 0),
+			article(3, 3, "Rust 2027 edition", 0),
+		}, nil, 10)
+
+		assert.Equal(t, []int64{2, 3}, pickedIDs(scores))
+		assert.Equal(t, ReasonDuplicate, reasonOf(scores, 1))
+		assert.Equal(t, 2, scores[0].Sources)
+	})
+
+	t.Run("should pick one article per story and credit coverage", func(t *testing.T) {
+		scores := testRanker(nil, 0, nil).Rank(context.Background(), []model.Article{
+			article(1, 1, "Kubernetes 1.34 released with sidecar support", time.Hour),
+			article(2, 2, "Kubernetes 1.34 released: sidecar support", 0),
+			article(3, 3, "Unrelated database story", 0),
+		}, nil, 10)
+
+		assert.ElementsMatch(t, []int64{2, 3}, pickedIDs(scores))
+		assert.Equal(t, ReasonDuplicate, reasonOf(scores, 1))
+		assert.InDelta(t, 2.0, scores[0].Coverage, 1e-9)
+		assert.Equal(t, 2, scores[0].Sources)
+	})
+
+	t.Run("should keep stories differing in a short word apart", func(t *testing.T) {
+		scores := testRanker(nil, 0, nil).Rank(context.Background(), []model.Article{
+			article(1, 1, "Go 1.26 released", 0),
+			article(2, 2, "Rust 1.26 released", 0),
+		}, nil, 10)
+
+		assert.ElementsMatch(t, []int64{1, 2}, pickedIDs(scores))
+	})
+
+	t.Run("should drop coverage of a recently posted story", func(t *testing.T) {
+		posted := article(9, 1, "Kubernetes 1.34 released with sidecar support", 2*time.Hour)
+		scores := testRanker(nil, 0, nil).Rank(context.Background(), []model.Article{
+			article(1, 2, "Kubernetes 1.34 released with sidecar support", time.Hour),
+			article(2, 3, "Unrelated database story", 0),
+		}, []model.Article{posted}, 10)
+
+		assert.Equal(t, []int64{2}, pickedIDs(scores))
+		assert.Equal(t, ReasonPosted, reasonOf(scores, 1))
+	})
+
+	t.Run("should apply keyword boosts and the cutoff", func(t *testing.T) {
+		keywords, err := ParseKeywords([]string{"rust=5", "crypto=-10"})
+		require.NoError(t, err)
+
+		scores := testRanker(keywords, 0, nil).Rank(context.Background(), []model.Article{
+			article(1, 1, "Crypto exchange news", 0),
+			article(2, 2, "Rust compiler speedup", 6*time.Hour),
+			article(3, 3, "Weather report", time.Hour),
+		}, nil, 2)
+
+		assert.Equal(t, []int64{2, 3}, pickedIDs(scores))
+		assert.Equal(t, ReasonCutoff, reasonOf(scores, 1))
+		assert.Equal(t, []string{"rust"}, scores[0].Matched)
+		assert.Contains(t, scores[0].Explain(), "keywords +5.00 (rust)")
+	})
+
+	t.Run("should add hacker news points", func(t *testing.T) {
+		scores := testRanker(nil, 0, staticSignal{1: 99}).Rank(context.Background(), []model.Article{
+			article(1, 1, "Show HN project", 6*time.Hour),
+			article(2, 2, "Plain story", 0),
+		}, nil, 10)
+
+		assert.Equal(t, int64(1), scores[0].Article.ID)
+		assert.Equal(t, 99, scores[0].Points)
+		assert.InDelta(t, 4.605, scores[0].HN, 1e-3)
+	})
+}
+
+func TestSameStory(t *testing.T) {
+	t.Run("should match the same link despite tracking parameters", func(t *testing.T) {
+		a := model.Article{Title: "Kubernetes 1.34", Link: "https://www.example.com/k8s/?utm_source=rss"}
+		b := model.Article{Title: "Sidecars are finally stable", Link: "http://example.com/k8s"}
+		assert.True(t, SameStory(a, b))
+	})
+
+	t.Run("should keep distinct query links apart", func(t *testing.T) {
+		a := model.Article{Title: "Show HN: a parser", Link: "https://news.ycombinator.com/item?id=1"}
+		b := model.Article{Title: "Ask HN: a question", Link: "https://news.ycombinator.com/item?id=2"}
+		assert.False(t, SameStory(a, b))
+	})
+}
+
+func TestStories(t *testing.T) {
+	t.Run("should join a story only when it matches every member", func(t *testing.T) {
+		stories := Stories([]model.Article{
+			{Title: "a b c d e f g h", Link: "https://example.com/1"},
+			{Title: "a b c d e f g h i j", Link: "https://example.com/2"},
+			{Title: "a b c d e f g h i j k l", Link: "https://example.com/3"},
+		})
+
+		assert.Equal(t, [][]int{{0, 1}, {2}}, stories)
+	})
+}
+
+// staticClusterer returns fixed clusters, or err.
+type staticClusterer struct {
+	clusters [][]int
+	err      error
+}
+
+func (c staticClusterer) Clusters(context.Context, []model.Article) ([][]int, error) {
+	return c.clusters, c.err
+}
+
+func TestStoryClusters(t *testing.T) {
+	articles := []model.Article{
+		{Title: "Kubernetes 1.34 released", Link: "https://example.com/1"},
+		{Title: "What is new in the latest Kubernetes", Link: "https://example.org/2"},
+		{Title: "Kubernetes 1.34 released", Link: "https://example.net/3"},
+	}
+
+	t.Run("should use the embedding clusters", func(t *testing.T) {
+		clusters := StoryClusters(context.Background(), staticClusterer{clusters: [][]int{{0, 1}, {2}}}, articles)
+		assert.Equal(t, [][]int{{0, 1}, {2}}, clusters)
+	})
+
+	t.Run("should fall back to title and link matching without embeddings", func(t *testing.T) {
+		clusters := StoryClusters(context.Background(), staticClusterer{err: cluster.ErrNoEmbedder}, articles)
+		assert.Equal(t, [][]int{{0, 2}, {1}}, clusters)
+		assert.Equal(t, [][]int{{0, 2}, {1}}, StoryClusters(context.Background(), nil, articles))
+	})
+}
+
+func TestParseKeywords(t *testing.T) {
+	keywords, err := ParseKeywords([]string{" Kubernetes = 2 ", "ads=-1.5"})
+	require.NoError(t, err)
+	assert.Equal(t, []Keyword{{Term: "kubernetes", Boost: 2}, {Term: "ads", Boost: -1.5}}, keywords)
+
+	_, err = ParseKeywords([]string{"no-boost"})
+	assert.Error(t, err)
+
+	_, err = ParseKeywords([]string{"go=lots"})
+	assert.Error(t, err)
+}
+
+func pickedIDs(scores []Score) []int64 {
+	var ids []int64
+	for _, a := range Picked(scores) {
+		ids = append(ids, a.ID)
+	}
+	return ids
+}
+
+func reasonOf(scores []Score, id int64) string {
+	for _, s := range scores {
+		if s.Article.ID == id {
+			return s.Reason
+		}
+	}
+	return ""
+}
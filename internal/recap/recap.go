@@ -0,0 +1,305 @@
+// Package recap posts a weekly recap of the most important stories among the
+// articles already posted that week. It never changes the posted state of
+// an article.
+package recap
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit"
+	"github.com/0x0BSoD/newsMaker/internal/botkit/markup"
+	"github.com/0x0BSoD/newsMaker/internal/gate"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+	"github.com/0x0BSoD/newsMaker/internal/prompt"
+	"github.com/0x0BSoD/newsMaker/internal/ranking"
+	"github.com/0x0BSoD/newsMaker/internal/reporter"
+	"github.com/0x0BSoD/newsMaker/internal/summary"
+	"github.com/0x0BSoD/newsMaker/internal/telegraph"
+)
+
+const (
+	week = 7 * 24 * time.Hour
+	// maxArticles bounds the posted articles considered for one recap.
+	maxArticles = 1000
+)
+
+type ArticleProvider interface {
+	PostedSince(ctx context.Context, since time.Time, limit uint64) ([]model.Article, error)
+}
+
+// VoteProvider is satisfied by storage.FeedbackPostgresStorage.
+type VoteProvider interface {
+	ArticleVotes(ctx context.Context, articleIDs []int64) (map[int64]model.ArticleFeedback, error)
+}
+
+// Clusterer groups articles by their embeddings; see cluster.Grouper.
+type Clusterer interface {
+	Clusters(ctx context.Context, articles []model.Article) ([][]int, error)
+}
+
+// PostStorage is satisfied by storage.PostPostgresStorage.
+type PostStorage interface {
+	Store(ctx context.Context, post model.Post) (int64, error)
+}
+
+// PostGate defers posts during quiet hours and over the daily cap; see
+// gate.Gate. It is checked before a post is generated, so that none goes
+// stale while held back; the sender enforces it again on the send itself.
+type PostGate interface {
+	Wait(ctx context.Context, channelID int64) error
+}
+
+// PromptRenderer renders named prompt templates; see prompt.Library.
+type PromptRenderer interface {
+	Render(ctx context.Context, name string, data prompt.Data) (string, error)
+}
+
+type Recap struct {
+	articles        ArticleProvider
+	votes           VoteProvider
+	posts           PostStorage
+	summarizer      summary.Summarizer
+	prompts         PromptRenderer
+	bot             botkit.Sender
+	tph             *telegraph.Client
+	reporter        *reporter.Reporter
+	gate            PostGate
+	channelID       int64
+	weekday         time.Weekday
+	hour            int
+	maxStories      int
+	weights         Weights
+	maxInputDataLen int
+	language        string
+	clusterer       Clusterer
+}
+
+// New returns a recap job posting to channelID every weekday at hour. tph
+// may be nil to post the recap without a Telegraph page. Stories are the
+// embedding clusters of clusterer, or coverage of the same story when it is
+// nil or embeddings are off.
+func New(
+	articleProvider ArticleProvider,
+	votes VoteProvider,
+	postStorage PostStorage,
+	summarizer summary.Summarizer,
+	prompts PromptRenderer,
+	bot botkit.Sender,
+	tph *telegraph.Client,
+	rep *reporter.Reporter,
+	gate PostGate,
+	channelID int64,
+	weekday time.Weekday,
+	hour int,
+	maxStories int,
+	weights Weights,
+	maxInputDataLen int,
+	language string,
+	clusterer Clusterer,
+) *Recap {
+	return &Recap{
+		articles:        articleProvider,
+		votes:           votes,
+		posts:           postStorage,
+		summarizer:      summarizer,
+		prompts:         prompts,
+		bot:             bot,
+		tph:             tph,
+		reporter:        rep,
+		gate:            gate,
+		channelID:       channelID,
+		weekday:         weekday,
+		hour:            hour,
+		maxStories:      maxStories,
+		weights:         weights,
+		maxInputDataLen: maxInputDataLen,
+		language:        language,
+		clusterer:       clusterer,
+	}
+}
+
+func (r *Recap) Start(ctx context.Context) error {
+	for {
+		next := nextRun(time.Now(), r.weekday, r.hour)
+		slog.Info("next weekly recap scheduled", "at", next)
+
+		select {
+		case <-time.After(time.Until(next)):
+			if err := r.gate.Wait(ctx, r.channelID); err != nil {
+				return err
+			}
+			if err := r.send(ctx, r.channelID, true); err != nil {
+				slog.Error("weekly recap failed", "err", err)
+				r.reporter.Notify(fmt.Sprintf("Weekly recap error: %v", err))
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// SendTest sends the recap of the past week to channelID without recording
+// the post.
+func (r *Recap) SendTest(ctx context.Context, channelID int64) error {
+	return r.send(gate.Exempt(ctx), channelID, false)
+}
+
+func (r *Recap) send(ctx context.Context, channelID int64, record bool) error {
+	articles, err := r.articles.PostedSince(ctx, time.Now().Add(-week), maxArticles)
+	if err != nil {
+		return fmt.Errorf("fetch posted articles: %w", err)
+	}
+	if len(articles) == 0 {
+		slog.Info("no posted articles this week, skipping recap")
+		return nil
+	}
+
+	ids := make([]int64, len(articles))
+	for i, a := range articles {
+		ids[i] = a.ID
+	}
+	votes, err := r.votes.ArticleVotes(ctx, ids)
+	if err != nil {
+		slog.Warn("fetch article votes failed, ranking without feedback", "err", err)
+	}
+
+	stories := TopStories(articles, ranking.StoryClusters(ctx, r.clusterer, articles), votes, r.weights, r.maxStories)
+	slog.Info("building weekly recap", "articles", len(articles), "stories", len(stories), "channel", channelID)
+
+	data := prompt.Data{
+		Channel:    channelID,
+		Language:   r.language,
+		Articles:   leads(stories),
+		Groups:     groups(stories),
+		MaxDataLen: r.maxInputDataLen,
+	}
+	input, err := r.prompts.Render(ctx, prompt.WeeklyRecapInput, data)
+	if err != nil {
+		return fmt.Errorf("render recap input: %w", err)
+	}
+	systemPrompt, err := r.prompts.Render(ctx, prompt.WeeklyRecapSystem, data)
+	if err != nil {
+		return fmt.Errorf("render recap prompt: %w", err)
+	}
+
+	res, err := r.summarizer.Summarize(ctx, input, summary.WithSystemPrompt(systemPrompt))
+	text := strings.TrimSpace(res.Text)
+	fallback := err != nil || text == ""
+	if fallback {
+		if err != nil {
+			slog.Error("recap summarization failed, using simple fallback", "err", err)
+			r.reporter.Notify(fmt.Sprintf("Weekly recap summarization error: %v", err))
+		}
+		text = buildSimpleRecap(stories)
+	} else {
+		text = markup.SanitizeTelegramHTML(text)
+	}
+
+	if r.tph != nil {
+		title := fmt.Sprintf("Weekly Recap %s", time.Now().Format("2006-01-02"))
+		if url, err := r.tph.CreatePage(title, buildPageNodes(stories)); err != nil {
+			slog.Error("create telegraph page failed", "err", err)
+		} else {
+			text += fmt.Sprintf("\n\n<a href=\"%s\">All stories of the week</a>", url)
+		}
+	}
+
+	msg := tgbotapi.NewMessage(channelID, text)
+	msg.ParseMode = tgbotapi.ModeHTML
+	msg.DisableWebPagePreview = true
+	sent, err := r.bot.Send(ctx, msg)
+	if err != nil {
+		return fmt.Errorf("send recap: %w", err)
+	}
+
+	if !record {
+		return nil
+	}
+
+	// ArticleIDs stay empty: the articles belong to the posts that first
+	// carried them, and retracting the recap must not requeue them.
+	if _, err := r.posts.Store(ctx, model.Post{
+		Kind:             model.PostKindWeeklyRecap,
+		ChannelID:        channelID,
+		ArticleCount:     len(stories),
+		Model:            res.Usage.Model,
+		PromptTokens:     res.Usage.PromptTokens,
+		CompletionTokens: res.Usage.CompletionTokens,
+		Fallback:         fallback,
+		MessageID:        sent.MessageID,
+	}); err != nil {
+		slog.Error("store post failed", "err", err)
+	}
+
+	return nil
+}
+
+// nextRun returns the first weekday at hour after now.
+func nextRun(now time.Time, weekday time.Weekday, hour int) time.Time {
+	y, m, d := now.Date()
+	days := (int(weekday) - int(now.Weekday()) + 7) % 7
+	next := time.Date(y, m, d+days, hour, 0, 0, 0, now.Location())
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 7)
+	}
+	return next
+}
+
+func leads(stories []Story) []model.Article {
+	articles := make([]model.Article, len(stories))
+	for i, s := range stories {
+		articles[i] = s.Lead
+	}
+	return articles
+}
+
+// groups turns stories into prompt groups named after the lead title, in
+// order of importance.
+func groups(stories []Story) []prompt.Group {
+	groups := make([]prompt.Group, len(stories))
+	for i, s := range stories {
+		groups[i] = prompt.Group{Topic: s.Lead.Title, Articles: s.Articles}
+	}
+	return groups
+}
+
+// buildSimpleRecap is a plain HTML fallback when the LLM is unavailable.
+func buildSimpleRecap(stories []Story) string {
+	var sb strings.Builder
+	sb.WriteString("<b>The week in tech</b>\n\n")
+	for _, s := range stories {
+		sb.WriteString(fmt.Sprintf("• <a href=\"%s\">%s</a>", s.Lead.Link, markup.EscapeForHTML(s.Lead.Title)))
+		if len(s.Articles) > 1 {
+			sb.WriteString(fmt.Sprintf(" (%d sources)", len(s.Articles)))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// buildPageNodes lists every story with all the articles covering it.
+func buildPageNodes(stories []Story) []telegraph.Node {
+	var nodes []telegraph.Node
+	for _, s := range stories {
+		nodes = append(nodes, telegraph.Node{Tag: "h4", Children: []any{s.Lead.Title}})
+		if s.Lead.Summary != "" {
+			nodes = append(nodes, telegraph.Node{Tag: "p", Children: []any{s.Lead.Summary}})
+		}
+
+		items := make([]any, 0, len(s.Articles))
+		for _, a := range s.Articles {
+			items = append(items, telegraph.Node{Tag: "li", Children: []any{
+				telegraph.Node{Tag: "a", Attrs: map[string]string{"href": a.Link}, Children: []any{a.Title}},
+				" — " + a.SourceName,
+			}})
+		}
+		nodes = append(nodes, telegraph.Node{Tag: "ul", Children: items})
+	}
+	return nodes
+}
@@ -0,0 +1,20 @@
+package recap
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextRun(t *testing.T) {
+	// 2026-06-05 is a Friday.
+	friday := time.Date(2026, 6, 5, 10, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 6, 7, 19, 0, 0, 0, time.UTC), nextRun(friday, time.Sunday, 19))
+
+	sundayEvening := time.Date(2026, 6, 7, 19, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 6, 14, 19, 0, 0, 0, time.UTC), nextRun(sundayEvening, time.Sunday, 19))
+
+	sundayMorning := time.Date(2026, 6, 7, 9, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2026, 6, 7, 19, 0, 0, 0, time.UTC), nextRun(sundayMorning, time.Sunday, 19))
+}
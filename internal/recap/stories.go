@@ -0,0 +1,52 @@
+package recap
+
+import (
+	"sort"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+// Weights of the signals that make a story important.
+type Weights struct {
+	// Coverage is the weight per article covering the story.
+	Coverage float64
+	// Feedback is the weight per net reader vote: up and "more like this"
+	// count for it, down against.
+	Feedback float64
+	// Priority is the weight of the lead article's source priority.
+	Priority float64
+}
+
+// Story is one news story of the week with the articles covering it.
+type Story struct {
+	Lead     model.Article
+	Articles []model.Article
+	Votes    int
+	Score    float64
+}
+
+// TopStories builds a story of each cluster, the indexes of articles covering
+// it, and returns the n most important, best first.
+func TopStories(articles []model.Article, clusters [][]int, votes map[int64]model.ArticleFeedback, w Weights, n int) []Story {
+	var stories []Story
+	for _, members := range clusters {
+		s := Story{Lead: articles[members[0]]}
+		for _, i := range members {
+			a := articles[i]
+			s.Articles = append(s.Articles, a)
+			if a.SourcePriority > s.Lead.SourcePriority {
+				s.Lead = a
+			}
+			v := votes[a.ID]
+			s.Votes += v.Up + v.More - v.Down
+		}
+		s.Score = w.Coverage*float64(len(s.Articles)) + w.Feedback*float64(s.Votes) + w.Priority*float64(s.Lead.SourcePriority)
+		stories = append(stories, s)
+	}
+
+	sort.SliceStable(stories, func(i, j int) bool { return stories[i].Score > stories[j].Score })
+	if len(stories) > n {
+		stories = stories[:n]
+	}
+	return stories
+}
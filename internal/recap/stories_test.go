@@ -0,0 +1,41 @@
+package recap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+func TestTopStories(t *testing.T) {
+	articles := []model.Article{
+		{ID: 1, Title: "Go 1.26 released with generic methods", SourcePriority: 1},
+		{ID: 2, Title: "Kubernetes 2.0 announced", SourcePriority: 1},
+		{ID: 3, Title: "Go 1.26 released with generic methods support", SourcePriority: 5},
+		{ID: 4, Title: "Small library update", SourcePriority: 2},
+	}
+	clusters := [][]int{{0, 2}, {1}, {3}}
+	w := Weights{Coverage: 2, Feedback: 1, Priority: 1}
+
+	t.Run("should rank stories by coverage and pick the lead by priority", func(t *testing.T) {
+		stories := TopStories(articles, clusters, nil, w, 10)
+		require.Len(t, stories, 3)
+		assert.Equal(t, int64(3), stories[0].Lead.ID)
+		assert.Len(t, stories[0].Articles, 2)
+		assert.Equal(t, 9.0, stories[0].Score)
+	})
+
+	t.Run("should count reader votes", func(t *testing.T) {
+		votes := map[int64]model.ArticleFeedback{
+			2: {Up: 8, More: 2},
+			4: {Up: 1, Down: 5},
+		}
+		stories := TopStories(articles, clusters, votes, w, 2)
+		require.Len(t, stories, 2)
+		assert.Equal(t, int64(2), stories[0].Lead.ID)
+		assert.Equal(t, 10, stories[0].Votes)
+		assert.Equal(t, int64(3), stories[1].Lead.ID)
+	})
+}
@@ -0,0 +1,117 @@
+package source
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+
+	"github.com/SlyMarbo/rss"
+)
+
+// mediaNS is the Media RSS namespace of media:content and media:thumbnail,
+// which the rss library does not parse.
+const mediaNS = "http://search.yahoo.com/mrss/"
+
+type mediaElement struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Medium string `xml:"medium,attr"`
+}
+
+func (m mediaElement) isImage() bool {
+	return m.URL != "" && (m.Medium == "image" || strings.HasPrefix(m.Type, "image/") || (m.Medium == "" && m.Type == ""))
+}
+
+type mediaLink struct {
+	Text string `xml:",chardata"`
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+}
+
+type mediaItem struct {
+	Links      []mediaLink    `xml:"link"`
+	Contents   []mediaElement `xml:"http://search.yahoo.com/mrss/ content"`
+	Groups     []mediaGroup   `xml:"http://search.yahoo.com/mrss/ group"`
+	Thumbnails []mediaElement `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+type mediaGroup struct {
+	Contents []mediaElement `xml:"http://search.yahoo.com/mrss/ content"`
+}
+
+// mediaImages returns the first Media RSS image of every RSS item or Atom
+// entry in the feed, keyed by item link. Feeds without Media RSS yield an
+// empty map.
+func mediaImages(data []byte) map[string]string {
+	images := make(map[string]string)
+	if !bytes.Contains(data, []byte(mediaNS)) {
+		return images
+	}
+
+	var feed struct {
+		Items   []mediaItem `xml:"channel>item"`
+		Entries []mediaItem `xml:"entry"`
+	}
+	if err := xml.Unmarshal(data, &feed); err != nil {
+		return images
+	}
+
+	for _, item := range append(feed.Items, feed.Entries...) {
+		if link, image := item.link(), item.image(); link != "" && image != "" {
+			images[link] = image
+		}
+	}
+	return images
+}
+
+// link returns the item link the way the rss library reads it: the RSS
+// link text, or the last alternate Atom link.
+func (i mediaItem) link() string {
+	var link string
+	for _, l := range i.Links {
+		if text := strings.TrimSpace(l.Text); text != "" {
+			link = text
+		} else if l.Rel == "" || l.Rel == "alternate" {
+			link = l.Href
+		}
+	}
+	return link
+}
+
+func (i mediaItem) image() string {
+	contents := i.Contents
+	for _, g := range i.Groups {
+		contents = append(contents, g.Contents...)
+	}
+	for _, c := range contents {
+		if c.isImage() {
+			return c.URL
+		}
+	}
+	for _, t := range i.Thumbnails {
+		if t.URL != "" {
+			return t.URL
+		}
+	}
+	return ""
+}
+
+// leadImage picks the lead image of an item: an image enclosure first, then
+// Media RSS content, then the item image.
+func leadImage(item *rss.Item, media map[string]string) string {
+	for _, e := range item.Enclosures {
+		if e != nil && e.URL != "" && strings.HasPrefix(e.Type, "image/") {
+			return e.URL
+		}
+	}
+	if image := media[item.Link]; image != "" {
+		return image
+	}
+	if item.Image != nil {
+		if item.Image.URL != "" {
+			return item.Image.URL
+		}
+		return item.Image.Href
+	}
+	return ""
+}
@@ -0,0 +1,48 @@
+package source
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRSSSource_Fetch_LeadImages(t *testing.T) {
+	data, err := os.ReadFile("testdata/media.xml")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(data)
+	}))
+	defer server.Close()
+
+	items, err := RSSSource{URL: server.URL, SourceName: "media"}.Fetch(context.Background())
+	require.NoError(t, err)
+
+	images := make(map[string]string)
+	for _, item := range items {
+		images[item.Link] = item.ImageURL
+	}
+	assert.Equal(t, map[string]string{
+		"https://example.com/enclosure": "https://example.com/enclosure.jpg",
+		"https://example.com/media":     "https://example.com/media.png",
+		"https://example.com/thumbnail": "https://example.com/thumb.jpg",
+		"https://example.com/none":      "",
+	}, images)
+}
+
+func TestMediaImages_Atom(t *testing.T) {
+	feed := `<feed xmlns="http://www.w3.org/2005/Atom" xmlns:media="http://search.yahoo.com/mrss/">
+		<entry>
+			<link rel="alternate" href="https://example.com/post"/>
+			<link rel="enclosure" href="https://example.com/file.zip"/>
+			<media:thumbnail url="https://example.com/post.jpg"/>
+		</entry>
+	</feed>`
+
+	assert.Equal(t, map[string]string{"https://example.com/post": "https://example.com/post.jpg"}, mediaImages([]byte(feed)))
+}
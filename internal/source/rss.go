@@ -4,6 +4,8 @@ package source
 import (
 	"context"
 	"crypto/tls"
+	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -42,11 +44,17 @@ func NewRSSSourceFromModel(m model.Source) RSSSource {
 }
 
 func (s RSSSource) Fetch(ctx context.Context) ([]model.Item, error) {
-	feed, err := s.loadFeed(ctx, s.URL)
+	data, err := s.loadFeed(ctx, s.URL)
 	if err != nil {
 		return nil, err
 	}
 
+	feed, err := rss.Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	media := mediaImages(data)
+
 	return lo.Map(feed.Items, func(item *rss.Item, _ int) model.Item {
 		return model.Item{
 			Title:      item.Title,
@@ -55,6 +63,7 @@ func (s RSSSource) Fetch(ctx context.Context) ([]model.Item, error) {
 			Date:       item.Date,
 			SourceName: s.SourceName,
 			Summary:    itemText(item),
+			ImageURL:   leadImage(item, media),
 		}
 	}), nil
 }
@@ -69,7 +78,9 @@ func itemText(item *rss.Item) string {
 	return strings.TrimSpace(item.Summary)
 }
 
-func (s RSSSource) loadFeed(ctx context.Context, url string) (*rss.Feed, error) {
+// loadFeed returns the raw feed, which is parsed twice: by the rss library
+// and for the Media RSS images it ignores.
+func (s RSSSource) loadFeed(ctx context.Context, url string) ([]byte, error) {
 	base := http.DefaultTransport
 	if s.Insecure {
 		base = &http.Transport{
@@ -80,7 +91,16 @@ func (s RSSSource) loadFeed(ctx context.Context, url string) (*rss.Feed, error)
 		Transport: contextTransport{ctx: ctx, base: base},
 		Timeout:   30 * time.Second,
 	}
-	return rss.FetchByClient(url, client)
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("feed %s returned status %d", url, resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
 }
 
 func (s RSSSource) ID() int64 {
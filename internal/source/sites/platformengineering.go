@@ -51,6 +51,7 @@ func (p *platformEngineeringParser) Parse(ctx context.Context, articleURL string
 		Title:      title,
 		Categories: categories,
 		Summary:    summary,
+		ImageURL:   OGImage(doc),
 	}, nil
 }
 
@@ -7,6 +7,9 @@ package sites
 import (
 	"context"
 	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
 )
 
 // Article holds the structured data extracted from a single article page.
@@ -14,6 +17,8 @@ type Article struct {
 	Title      string
 	Categories []string
 	Summary    string
+	// ImageURL is the page's og:image, if any.
+	ImageURL string
 }
 
 // Parser extracts structured article data from a website it knows about.
@@ -44,3 +49,13 @@ func Find(rawURL string) Parser {
 	}
 	return nil
 }
+
+// OGImage returns the og:image of a page, falling back to twitter:image.
+func OGImage(doc *goquery.Document) string {
+	for _, sel := range []string{`meta[property="og:image"]`, `meta[name="og:image"]`, `meta[name="twitter:image"]`} {
+		if content, ok := doc.Find(sel).First().Attr("content"); ok && strings.TrimSpace(content) != "" {
+			return strings.TrimSpace(content)
+		}
+	}
+	return ""
+}
@@ -131,6 +131,7 @@ func (s WebSource) enrichArticle(ctx context.Context, link string) model.Item {
 	item.Title = parsed.Title
 	item.Categories = parsed.Categories
 	item.Summary = parsed.Summary
+	item.ImageURL = parsed.ImageURL
 	return item
 }
 
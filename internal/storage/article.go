@@ -29,14 +29,16 @@ func (s *ArticlePostgresStorage) Store(ctx context.Context, article model.Articl
 
 	if _, err := conn.ExecContext(
 		ctx,
-		`INSERT INTO articles (source_id, title, link, summary, categories, published_at)
-	    				VALUES ($1, $2, $3, $4, $5, $6)
+		`INSERT INTO articles (source_id, title, link, summary, categories, image_url, language, published_at)
+	    				VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 	    				ON CONFLICT DO NOTHING;`,
 		article.SourceID,
 		article.Title,
 		article.Link,
 		article.Summary,
 		pq.Array(lo.If(article.Categories == nil, []string{}).Else(article.Categories)),
+		article.ImageURL,
+		article.Language,
 		article.PublishedAt,
 	); err != nil {
 		return err
@@ -61,15 +63,19 @@ func (s *ArticlePostgresStorage) AllNotPosted(ctx context.Context, since time.Ti
 				a.id AS a_id,
 				s.priority AS s_priority,
 				s.id AS s_id,
+				s.name AS s_name,
 				a.title AS a_title,
 				a.link AS a_link,
 				a.summary AS a_summary,
 				a.categories AS a_categories,
+				a.image_url AS a_image_url,
+				a.language AS a_language,
 				a.published_at AS a_published_at,
 				a.posted_at AS a_posted_at,
 				a.created_at AS a_created_at
 			FROM articles a JOIN sources s ON s.id = a.source_id
 			WHERE a.posted_at IS NULL
+				AND s.deleted_at IS NULL
 				AND a.published_at >= $1::timestamp
 			ORDER BY a.created_at DESC, s_priority DESC LIMIT $2;`,
 		since.UTC().Format(time.RFC3339),
@@ -78,48 +84,223 @@ func (s *ArticlePostgresStorage) AllNotPosted(ctx context.Context, since time.Ti
 		return nil, err
 	}
 
-	return lo.Map(articles, func(article dbArticleWithPriority, _ int) model.Article {
-		return model.Article{
-			ID:          article.ID,
-			SourceID:    article.SourceID,
-			Title:       article.Title,
-			Link:        article.Link,
-			Summary:     article.Summary.String,
-			Categories:  []string(article.Categories),
-			PublishedAt: article.PublishedAt,
-			CreatedAt:   article.CreatedAt,
-		}
-	}), nil
+	return lo.Map(articles, func(article dbArticleWithPriority, _ int) model.Article { return article.toModel() }), nil
 }
 
-func (s *ArticlePostgresStorage) MarkAsPosted(ctx context.Context, article model.Article) error {
+// ArticlesByIDs returns the given articles with their sources, in no
+// particular order. Missing IDs are skipped.
+func (s *ArticlePostgresStorage) ArticlesByIDs(ctx context.Context, ids []int64) ([]model.Article, error) {
 	conn, err := s.db.Connx(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer conn.Close()
 
-	if _, err := conn.ExecContext(
+	var articles []dbArticleWithPriority
+
+	if err := conn.SelectContext(
+		ctx,
+		&articles,
+		`SELECT
+				a.id AS a_id,
+				s.priority AS s_priority,
+				s.id AS s_id,
+				s.name AS s_name,
+				a.title AS a_title,
+				a.link AS a_link,
+				a.summary AS a_summary,
+				a.categories AS a_categories,
+				a.image_url AS a_image_url,
+				a.language AS a_language,
+				a.published_at AS a_published_at,
+				a.posted_at AS a_posted_at,
+				a.created_at AS a_created_at
+			FROM articles a JOIN sources s ON s.id = a.source_id
+			WHERE a.id = ANY($1);`,
+		pq.Array(ids),
+	); err != nil {
+		return nil, err
+	}
+
+	return lo.Map(articles, func(article dbArticleWithPriority, _ int) model.Article { return article.toModel() }), nil
+}
+
+// PostedSince returns the articles posted since the given time, newest
+// first. It does not change their posted state.
+func (s *ArticlePostgresStorage) PostedSince(ctx context.Context, since time.Time, limit uint64) ([]model.Article, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var articles []dbArticleWithPriority
+
+	if err := conn.SelectContext(
 		ctx,
-		`UPDATE articles SET posted_at = $1::timestamp WHERE id = $2;`,
+		&articles,
+		`SELECT
+				a.id AS a_id,
+				s.priority AS s_priority,
+				s.id AS s_id,
+				s.name AS s_name,
+				a.title AS a_title,
+				a.link AS a_link,
+				a.summary AS a_summary,
+				a.categories AS a_categories,
+				a.image_url AS a_image_url,
+				a.language AS a_language,
+				a.published_at AS a_published_at,
+				a.posted_at AS a_posted_at,
+				a.created_at AS a_created_at
+			FROM articles a JOIN sources s ON s.id = a.source_id
+			WHERE a.posted_at >= $1::timestamp
+			ORDER BY a.posted_at DESC LIMIT $2;`,
+		since.UTC().Format(time.RFC3339),
+		limit,
+	); err != nil {
+		return nil, err
+	}
+
+	return lo.Map(articles, func(article dbArticleWithPriority, _ int) model.Article { return article.toModel() }), nil
+}
+
+// Claim marks the unposted articles among ids as posted and returns their
+// IDs. It is atomic, so of several notifiers claiming the same article only
+// one gets it.
+func (s *ArticlePostgresStorage) Claim(ctx context.Context, ids []int64) ([]int64, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var claimed []int64
+	if err := conn.SelectContext(ctx, &claimed,
+		`UPDATE articles SET posted_at = $1::timestamp WHERE id = ANY($2) AND posted_at IS NULL RETURNING id`,
 		time.Now().UTC().Format(time.RFC3339),
-		article.ID,
+		pq.Array(ids),
 	); err != nil {
+		return nil, err
+	}
+
+	return claimed, nil
+}
+
+// Unclaim returns claimed articles to the queue when their post failed.
+func (s *ArticlePostgresStorage) Unclaim(ctx context.Context, ids []int64) error {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
 		return err
 	}
+	defer conn.Close()
 
-	return nil
+	_, err = conn.ExecContext(ctx, `UPDATE articles SET posted_at = NULL WHERE id = ANY($1)`, pq.Array(ids))
+	return err
+}
+
+// Search runs a full-text search with the filters of q, best matches first,
+// and returns a page of results with the number of matches in all.
+// Articles of deleted sources are included.
+func (s *ArticlePostgresStorage) Search(ctx context.Context, q model.ArticleSearch) ([]model.Article, int, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer conn.Close()
+
+	var articles []dbFoundArticle
+
+	if err := conn.SelectContext(
+		ctx,
+		&articles,
+		`SELECT
+				a.id AS a_id,
+				s.priority AS s_priority,
+				s.id AS s_id,
+				s.name AS s_name,
+				a.title AS a_title,
+				a.link AS a_link,
+				a.summary AS a_summary,
+				a.categories AS a_categories,
+				a.image_url AS a_image_url,
+				a.language AS a_language,
+				a.published_at AS a_published_at,
+				a.posted_at AS a_posted_at,
+				a.created_at AS a_created_at,
+				COUNT(*) OVER () AS total
+			FROM articles a
+				JOIN sources s ON s.id = a.source_id,
+				websearch_to_tsquery('simple', $1) q
+			WHERE ($1 = '' OR a.search_vector @@ q)
+				AND ($2::timestamp IS NULL OR a.published_at >= $2)
+				AND ($3::timestamp IS NULL OR a.published_at < $3)
+				AND (cardinality($4::bigint[]) = 0 OR a.source_id = ANY($4))
+				AND (cardinality($5::text[]) = 0 OR a.categories && $5)
+				AND ($8::timestamp IS NULL OR a.created_at >= $8)
+				AND (NOT $9 OR s.deleted_at IS NULL)
+			ORDER BY ts_rank(a.search_vector, q) DESC, a.published_at DESC
+			LIMIT NULLIF($6, 0) OFFSET $7;`,
+		q.Query,
+		nullTime(q.From),
+		nullTime(q.To),
+		pq.Array(lo.If(q.SourceIDs == nil, []int64{}).Else(q.SourceIDs)),
+		pq.Array(lo.If(q.Categories == nil, []string{}).Else(q.Categories)),
+		q.Limit,
+		q.Offset,
+		nullTime(q.FetchedFrom),
+		q.LiveSources,
+	); err != nil {
+		return nil, 0, err
+	}
+
+	if len(articles) == 0 {
+		return nil, 0, nil
+	}
+
+	return lo.Map(articles, func(article dbFoundArticle, _ int) model.Article { return article.toModel() }), articles[0].Total, nil
+}
+
+// nullTime maps the zero time to NULL.
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t.UTC(), Valid: !t.IsZero()}
+}
+
+type dbFoundArticle struct {
+	dbArticleWithPriority
+	Total int `db:"total"`
 }
 
 type dbArticleWithPriority struct {
 	ID             int64          `db:"a_id"`
 	SourcePriority int64          `db:"s_priority"`
 	SourceID       int64          `db:"s_id"`
+	SourceName     string         `db:"s_name"`
 	Title          string         `db:"a_title"`
 	Link           string         `db:"a_link"`
 	Summary        sql.NullString `db:"a_summary"`
 	Categories     pq.StringArray `db:"a_categories"`
+	ImageURL       string         `db:"a_image_url"`
+	Language       string         `db:"a_language"`
 	PublishedAt    time.Time      `db:"a_published_at"`
 	PostedAt       sql.NullTime   `db:"a_posted_at"`
 	CreatedAt      time.Time      `db:"a_created_at"`
 }
+
+func (a dbArticleWithPriority) toModel() model.Article {
+	return model.Article{
+		ID:             a.ID,
+		SourceID:       a.SourceID,
+		SourceName:     a.SourceName,
+		SourcePriority: int(a.SourcePriority),
+		Title:          a.Title,
+		Link:           a.Link,
+		Summary:        a.Summary.String,
+		Categories:     []string(a.Categories),
+		ImageURL:       a.ImageURL,
+		Language:       a.Language,
+		PublishedAt:    a.PublishedAt,
+		PostedAt:       a.PostedAt.Time,
+		CreatedAt:      a.CreatedAt,
+	}
+}
@@ -0,0 +1,108 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+type ConversationPostgresStorage struct {
+	db *sqlx.DB
+}
+
+func NewConversationStorage(db *sqlx.DB) *ConversationPostgresStorage {
+	return &ConversationPostgresStorage{db: db}
+}
+
+// Conversation returns the dialog in progress in a chat, or nil if there is
+// none.
+func (s *ConversationPostgresStorage) Conversation(ctx context.Context, chatID int64) (*model.Conversation, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var c dbConversation
+	if err := conn.GetContext(ctx, &c,
+		`SELECT chat_id, user_id, name, step, data, history, message_id, updated_at
+		 FROM conversations WHERE chat_id = $1`,
+		chatID,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	values := make(map[string]string)
+	if err := json.Unmarshal(c.Data, &values); err != nil {
+		return nil, err
+	}
+
+	return &model.Conversation{
+		ChatID:    c.ChatID,
+		UserID:    c.UserID,
+		Name:      c.Name,
+		Step:      c.Step,
+		Values:    values,
+		History:   []string(c.History),
+		MessageID: c.MessageID,
+		UpdatedAt: c.UpdatedAt,
+	}, nil
+}
+
+// SaveConversation stores the dialog state of its chat, replacing any
+// previous one, and stamps it with the current time.
+func (s *ConversationPostgresStorage) SaveConversation(ctx context.Context, c model.Conversation) error {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	data, err := json.Marshal(c.Values)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn.ExecContext(ctx,
+		`INSERT INTO conversations (chat_id, user_id, name, step, data, history, message_id, updated_at)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7, NOW())
+		 ON CONFLICT (chat_id) DO UPDATE SET
+			user_id = EXCLUDED.user_id, name = EXCLUDED.name, step = EXCLUDED.step, data = EXCLUDED.data,
+			history = EXCLUDED.history, message_id = EXCLUDED.message_id, updated_at = NOW()`,
+		c.ChatID, c.UserID, c.Name, c.Step, string(data), pq.Array(c.History), c.MessageID,
+	)
+	return err
+}
+
+// DeleteConversation ends the dialog in progress in a chat, if any.
+func (s *ConversationPostgresStorage) DeleteConversation(ctx context.Context, chatID int64) error {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, `DELETE FROM conversations WHERE chat_id = $1`, chatID)
+	return err
+}
+
+type dbConversation struct {
+	ChatID    int64          `db:"chat_id"`
+	UserID    int64          `db:"user_id"`
+	Name      string         `db:"name"`
+	Step      string         `db:"step"`
+	Data      []byte         `db:"data"`
+	History   pq.StringArray `db:"history"`
+	MessageID int            `db:"message_id"`
+	UpdatedAt time.Time      `db:"updated_at"`
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+)
+
+type EmbeddingPostgresStorage struct {
+	db *sqlx.DB
+}
+
+func NewEmbeddingStorage(db *sqlx.DB) *EmbeddingPostgresStorage {
+	return &EmbeddingPostgresStorage{db: db}
+}
+
+// Embeddings returns the stored vectors of model for the given articles,
+// keyed by article ID. Articles without a vector are absent from the map.
+func (s *EmbeddingPostgresStorage) Embeddings(ctx context.Context, model string, articleIDs []int64) (map[int64][]float32, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var rows []dbEmbedding
+	if err := conn.SelectContext(ctx, &rows,
+		`SELECT article_id, vector FROM article_embeddings WHERE model = $1 AND article_id = ANY($2)`,
+		model, pq.Array(articleIDs),
+	); err != nil {
+		return nil, err
+	}
+
+	vectors := make(map[int64][]float32, len(rows))
+	for _, r := range rows {
+		vectors[r.ArticleID] = []float32(r.Vector)
+	}
+	return vectors, nil
+}
+
+func (s *EmbeddingPostgresStorage) StoreEmbedding(ctx context.Context, articleID int64, model string, vector []float32) error {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx,
+		`INSERT INTO article_embeddings (article_id, model, vector)
+		 VALUES ($1, $2, $3)
+		 ON CONFLICT (article_id, model) DO UPDATE SET vector = EXCLUDED.vector, created_at = NOW()`,
+		articleID, model, pq.Array(vector),
+	)
+	return err
+}
+
+type dbEmbedding struct {
+	ArticleID int64           `db:"article_id"`
+	Vector    pq.Float32Array `db:"vector"`
+}
@@ -0,0 +1,244 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+type FeedbackPostgresStorage struct {
+	db *sqlx.DB
+}
+
+func NewFeedbackStorage(db *sqlx.DB) *FeedbackPostgresStorage {
+	return &FeedbackPostgresStorage{db: db}
+}
+
+// articleVotes is a WITH clause listing the votes per article. A vote on a
+// post covering several articles, such as a digest, has no article of its
+// own and counts for every article of the post.
+const articleVotes = `WITH article_votes AS (
+		SELECT f.article_id, f.vote, f.created_at
+		FROM feedback f
+		WHERE f.article_id IS NOT NULL
+		UNION ALL
+		SELECT pa.article_id, f.vote, f.created_at
+		FROM feedback f
+		JOIN posts p ON p.channel_id = f.chat_id AND p.message_id = f.message_id
+		JOIN post_articles pa ON pa.post_id = p.id
+		WHERE f.article_id IS NULL
+	)`
+
+// ToggleVote records the vote, or removes it when the user already cast the
+// same vote on the message. Up and down votes exclude each other. It reports
+// whether the vote is now set.
+func (s *FeedbackPostgresStorage) ToggleVote(ctx context.Context, vote model.Vote) (bool, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	res, err := tx.ExecContext(ctx,
+		`DELETE FROM feedback WHERE chat_id = $1 AND message_id = $2 AND user_id = $3 AND vote = $4`,
+		vote.ChatID, vote.MessageID, vote.UserID, vote.Vote,
+	)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return false, err
+	} else if n > 0 {
+		return false, tx.Commit()
+	}
+
+	var opposite string
+	switch vote.Vote {
+	case model.VoteUp:
+		opposite = model.VoteDown
+	case model.VoteDown:
+		opposite = model.VoteUp
+	}
+	if opposite != "" {
+		if _, err := tx.ExecContext(ctx,
+			`DELETE FROM feedback WHERE chat_id = $1 AND message_id = $2 AND user_id = $3 AND vote = $4`,
+			vote.ChatID, vote.MessageID, vote.UserID, opposite,
+		); err != nil {
+			return false, err
+		}
+	}
+
+	res, err = tx.ExecContext(ctx,
+		`INSERT INTO feedback (chat_id, message_id, article_id, user_id, vote)
+					VALUES ($1, $2, $3, $4, $5)
+					ON CONFLICT (chat_id, message_id, user_id, vote) DO NOTHING`,
+		vote.ChatID, vote.MessageID, sql.NullInt64{Int64: vote.ArticleID, Valid: vote.ArticleID != 0}, vote.UserID, vote.Vote,
+	)
+	if err != nil {
+		return false, err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return false, err
+	} else if n == 0 {
+		// A concurrent press of the same button set the vote first: this
+		// press toggles it off again.
+		if _, err := tx.ExecContext(ctx,
+			`DELETE FROM feedback WHERE chat_id = $1 AND message_id = $2 AND user_id = $3 AND vote = $4`,
+			vote.ChatID, vote.MessageID, vote.UserID, vote.Vote,
+		); err != nil {
+			return false, err
+		}
+		return false, tx.Commit()
+	}
+
+	return true, tx.Commit()
+}
+
+// VoteCounts returns the number of votes of each kind on a message.
+func (s *FeedbackPostgresStorage) VoteCounts(ctx context.Context, chatID int64, messageID int) (map[string]int, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var rows []dbVoteCount
+	if err := conn.SelectContext(ctx, &rows,
+		`SELECT vote, COUNT(*) AS count FROM feedback WHERE chat_id = $1 AND message_id = $2 GROUP BY vote`,
+		chatID, messageID,
+	); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(rows))
+	for _, r := range rows {
+		counts[r.Vote] = r.Count
+	}
+	return counts, nil
+}
+
+// ArticleVotes returns the votes on each of the given articles, including
+// votes on the digests that covered them. Articles without votes are absent
+// from the map.
+func (s *FeedbackPostgresStorage) ArticleVotes(ctx context.Context, articleIDs []int64) (map[int64]model.ArticleFeedback, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var rows []dbArticleFeedback
+	if err := conn.SelectContext(ctx, &rows,
+		articleVotes+`
+		SELECT a.id AS article_id, a.title, a.link,
+				COUNT(*) FILTER (WHERE f.vote = 'up')   AS up,
+				COUNT(*) FILTER (WHERE f.vote = 'down') AS down,
+				COUNT(*) FILTER (WHERE f.vote = 'more') AS more
+		 FROM article_votes f
+		 JOIN articles a ON a.id = f.article_id
+		 WHERE f.article_id = ANY($1)
+		 GROUP BY a.id, a.title, a.link`,
+		pq.Array(articleIDs),
+	); err != nil {
+		return nil, err
+	}
+
+	votes := make(map[int64]model.ArticleFeedback, len(rows))
+	for _, r := range rows {
+		votes[r.ArticleID] = model.ArticleFeedback(r)
+	}
+	return votes, nil
+}
+
+// FeedbackStats sums up the votes since the given time: totals by kind, per
+// source and for the limit most voted articles. A digest vote counts once in
+// the totals and for every article of the digest in the rest.
+func (s *FeedbackPostgresStorage) FeedbackStats(ctx context.Context, since time.Time, limit int) (model.FeedbackStats, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return model.FeedbackStats{}, err
+	}
+	defer conn.Close()
+
+	var totals []dbVoteCount
+	if err := conn.SelectContext(ctx, &totals,
+		`SELECT vote, COUNT(*) AS count FROM feedback WHERE created_at >= $1 GROUP BY vote`,
+		since,
+	); err != nil {
+		return model.FeedbackStats{}, err
+	}
+
+	var sources []dbSourceFeedback
+	if err := conn.SelectContext(ctx, &sources,
+		articleVotes+`
+		SELECT s.name AS source_name,
+				COUNT(*) FILTER (WHERE f.vote = 'up')   AS up,
+				COUNT(*) FILTER (WHERE f.vote = 'down') AS down,
+				COUNT(*) FILTER (WHERE f.vote = 'more') AS more
+		 FROM article_votes f
+		 JOIN articles a ON a.id = f.article_id
+		 JOIN sources s ON s.id = a.source_id
+		 WHERE f.created_at >= $1
+		 GROUP BY s.name
+		 ORDER BY COUNT(*) DESC, s.name`,
+		since,
+	); err != nil {
+		return model.FeedbackStats{}, err
+	}
+
+	var articles []dbArticleFeedback
+	if err := conn.SelectContext(ctx, &articles,
+		articleVotes+`
+		SELECT a.id AS article_id, a.title, a.link,
+				COUNT(*) FILTER (WHERE f.vote = 'up')   AS up,
+				COUNT(*) FILTER (WHERE f.vote = 'down') AS down,
+				COUNT(*) FILTER (WHERE f.vote = 'more') AS more
+		 FROM article_votes f
+		 JOIN articles a ON a.id = f.article_id
+		 WHERE f.created_at >= $1
+		 GROUP BY a.id, a.title, a.link
+		 ORDER BY COUNT(*) DESC, a.id DESC
+		 LIMIT $2`,
+		since, limit,
+	); err != nil {
+		return model.FeedbackStats{}, err
+	}
+
+	stats := model.FeedbackStats{Totals: make(map[string]int, len(totals))}
+	for _, t := range totals {
+		stats.Totals[t.Vote] = t.Count
+	}
+	for _, s := range sources {
+		stats.Sources = append(stats.Sources, model.SourceFeedback(s))
+	}
+	for _, a := range articles {
+		stats.Articles = append(stats.Articles, model.ArticleFeedback(a))
+	}
+	return stats, nil
+}
+
+type dbVoteCount struct {
+	Vote  string `db:"vote"`
+	Count int    `db:"count"`
+}
+
+type dbSourceFeedback struct {
+	SourceName string `db:"source_name"`
+	Up         int    `db:"up"`
+	Down       int    `db:"down"`
+	More       int    `db:"more"`
+}
+
+type dbArticleFeedback struct {
+	ArticleID int64  `db:"article_id"`
+	Title     string `db:"title"`
+	Link      string `db:"link"`
+	Up        int    `db:"up"`
+	Down      int    `db:"down"`
+	More      int    `db:"more"`
+}
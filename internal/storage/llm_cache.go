@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type LLMCachePostgresStorage struct {
+	db *sqlx.DB
+}
+
+func NewLLMCacheStorage(db *sqlx.DB) *LLMCachePostgresStorage {
+	return &LLMCachePostgresStorage{db: db}
+}
+
+// Get returns the unexpired cached value for key.
+func (s *LLMCachePostgresStorage) Get(ctx context.Context, key string) (string, bool, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	defer conn.Close()
+
+	var value string
+	if err := conn.GetContext(ctx, &value,
+		`SELECT value FROM llm_cache WHERE key = $1 AND expires_at > NOW()`,
+		key,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", false, nil
+		}
+		return "", false, err
+	}
+
+	return value, true, nil
+}
+
+// Put stores value under key.
+func (s *LLMCachePostgresStorage) Put(ctx context.Context, key, model, value string, expiresAt time.Time) error {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx,
+		`INSERT INTO llm_cache (key, model, value, expires_at)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (key) DO UPDATE
+		     SET value      = EXCLUDED.value,
+		         model      = EXCLUDED.model,
+		         created_at = NOW(),
+		         expires_at = EXCLUDED.expires_at`,
+		key, model, value, expiresAt.UTC(),
+	)
+	return err
+}
+
+// DeleteExpired drops the expired entries.
+func (s *LLMCachePostgresStorage) DeleteExpired(ctx context.Context) (int64, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	res, err := conn.ExecContext(ctx, `DELETE FROM llm_cache WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE posts
+(
+    id                BIGSERIAL PRIMARY KEY,
+    kind              TEXT        NOT NULL,
+    channel_id        BIGINT      NOT NULL,
+    slot              TEXT        NOT NULL DEFAULT '',
+    article_count     INT         NOT NULL DEFAULT 0,
+    model             TEXT        NOT NULL DEFAULT '',
+    estimated_tokens  INT         NOT NULL DEFAULT 0,
+    prompt_tokens     INT         NOT NULL DEFAULT 0,
+    completion_tokens INT         NOT NULL DEFAULT 0,
+    fallback          BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_at        TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+CREATE INDEX idx_posts_channel_created_at ON posts (channel_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS posts;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE prompt_templates
+(
+    id         BIGSERIAL PRIMARY KEY,
+    name       TEXT        NOT NULL,
+    version    INT         NOT NULL,
+    body       TEXT        NOT NULL,
+    active     BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_by BIGINT      NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (name, version)
+);
+CREATE UNIQUE INDEX idx_prompt_templates_active ON prompt_templates (name) WHERE active;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS prompt_templates;
+-- +goose StatementEnd
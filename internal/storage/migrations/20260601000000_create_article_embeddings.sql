@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE article_embeddings
+(
+    article_id BIGINT      NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    model      TEXT        NOT NULL,
+    vector     REAL[]      NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (article_id, model)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS article_embeddings;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE feedback
+(
+    id         BIGSERIAL PRIMARY KEY,
+    chat_id    BIGINT      NOT NULL,
+    message_id INT         NOT NULL,
+    article_id BIGINT REFERENCES articles (id) ON DELETE CASCADE,
+    user_id    BIGINT      NOT NULL,
+    vote       TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (chat_id, message_id, user_id, vote)
+);
+CREATE INDEX idx_feedback_created_at ON feedback (created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS feedback;
+-- +goose StatementEnd
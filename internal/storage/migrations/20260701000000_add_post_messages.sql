@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE posts
+    ADD COLUMN message_id        INT         NOT NULL DEFAULT 0,
+    ADD COLUMN media_message_ids INT[]       NOT NULL DEFAULT '{}',
+    ADD COLUMN edited_at         TIMESTAMPTZ,
+    ADD COLUMN retracted_at      TIMESTAMPTZ;
+
+CREATE TABLE post_articles
+(
+    post_id    BIGINT NOT NULL REFERENCES posts (id) ON DELETE CASCADE,
+    article_id BIGINT NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    PRIMARY KEY (post_id, article_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS post_articles;
+ALTER TABLE posts
+    DROP COLUMN IF EXISTS message_id,
+    DROP COLUMN IF EXISTS media_message_ids,
+    DROP COLUMN IF EXISTS edited_at,
+    DROP COLUMN IF EXISTS retracted_at;
+-- +goose StatementEnd
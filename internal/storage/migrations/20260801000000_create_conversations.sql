@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE conversations
+(
+    chat_id    BIGINT PRIMARY KEY,
+    user_id    BIGINT      NOT NULL,
+    name       TEXT        NOT NULL,
+    step       TEXT        NOT NULL,
+    data       JSONB       NOT NULL DEFAULT '{}',
+    history    TEXT[]      NOT NULL DEFAULT '{}',
+    message_id INT         NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS conversations;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- No foreign key: the history of a source outlives the source.
+CREATE TABLE source_changes
+(
+    id         BIGSERIAL PRIMARY KEY,
+    source_id  BIGINT      NOT NULL,
+    changed_by BIGINT      NOT NULL,
+    old_values JSONB       NOT NULL,
+    new_values JSONB       NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_source_changes_source_id ON source_changes (source_id, changed_at DESC);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS source_changes;
+-- +goose StatementEnd
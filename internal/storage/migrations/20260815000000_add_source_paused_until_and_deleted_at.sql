@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE sources ADD COLUMN paused_until TIMESTAMPTZ;
+ALTER TABLE sources ADD COLUMN deleted_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE sources DROP COLUMN deleted_at;
+ALTER TABLE sources DROP COLUMN paused_until;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The summary column holds the full content when the feed carries it, so
+-- title, summary and categories cover the content that is stored; article
+-- pages are not fetched for it. The simple configuration does no stemming,
+-- which suits the mix of languages.
+ALTER TABLE articles ADD COLUMN search_vector TSVECTOR;
+
+CREATE FUNCTION articles_search_vector_update() RETURNS TRIGGER AS
+$$
+BEGIN
+    NEW.search_vector :=
+            setweight(to_tsvector('simple', COALESCE(NEW.title, '')), 'A') ||
+            setweight(to_tsvector('simple', COALESCE(NEW.summary, '')), 'B') ||
+            setweight(to_tsvector('simple', array_to_string(NEW.categories, ' ')), 'C');
+    RETURN NEW;
+END
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER articles_search_vector
+    BEFORE INSERT OR UPDATE OF title, summary, categories
+    ON articles
+    FOR EACH ROW
+EXECUTE FUNCTION articles_search_vector_update();
+
+-- Fire the trigger for existing rows.
+UPDATE articles SET title = title;
+
+CREATE INDEX idx_articles_search_vector ON articles USING GIN (search_vector);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_articles_search_vector;
+DROP TRIGGER IF EXISTS articles_search_vector ON articles;
+DROP FUNCTION IF EXISTS articles_search_vector_update();
+ALTER TABLE articles DROP COLUMN IF EXISTS search_vector;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE subscriptions
+(
+    id           BIGSERIAL PRIMARY KEY,
+    user_id      BIGINT      NOT NULL,
+    chat_id      BIGINT      NOT NULL,
+    keywords     TEXT[]      NOT NULL DEFAULT '{}',
+    categories   TEXT[]      NOT NULL DEFAULT '{}',
+    source_ids   BIGINT[]    NOT NULL DEFAULT '{}',
+    schedule     TEXT        NOT NULL,
+    hour         INT         NOT NULL,
+    timezone     TEXT        NOT NULL DEFAULT 'UTC',
+    language     TEXT        NOT NULL DEFAULT '',
+    last_sent_at TIMESTAMPTZ,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_subscriptions_user_id ON subscriptions (user_id);
+
+-- Articles sent to a user, so overlapping subscriptions and later digests do
+-- not repeat them.
+CREATE TABLE subscription_sent
+(
+    user_id    BIGINT      NOT NULL,
+    article_id BIGINT      NOT NULL REFERENCES articles (id) ON DELETE CASCADE,
+    sent_at    TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, article_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS subscription_sent;
+DROP TABLE IF EXISTS subscriptions;
+-- +goose StatementEnd
@@ -0,0 +1,241 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/samber/lo"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+type PostPostgresStorage struct {
+	db *sqlx.DB
+}
+
+func NewPostStorage(db *sqlx.DB) *PostPostgresStorage {
+	return &PostPostgresStorage{db: db}
+}
+
+// Store records a published post with the articles it covers and returns its
+// ID.
+func (s *PostPostgresStorage) Store(ctx context.Context, post model.Post) (int64, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var id int64
+	if err := tx.QueryRowxContext(
+		ctx,
+		`INSERT INTO posts (kind, channel_id, slot, article_count, model, estimated_tokens, prompt_tokens, completion_tokens, fallback, message_id, media_message_ids)
+					VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11) RETURNING id;`,
+		post.Kind,
+		post.ChannelID,
+		post.Slot,
+		post.ArticleCount,
+		post.Model,
+		post.EstimatedTokens,
+		post.PromptTokens,
+		post.CompletionTokens,
+		post.Fallback,
+		post.MessageID,
+		pq.Array(lo.Map(post.MediaMessageIDs, func(id int, _ int) int64 { return int64(id) })),
+	).Scan(&id); err != nil {
+		return 0, err
+	}
+
+	if len(post.ArticleIDs) > 0 {
+		if _, err := tx.ExecContext(
+			ctx,
+			`INSERT INTO post_articles (post_id, article_id)
+					SELECT $1, UNNEST($2::BIGINT[]) ON CONFLICT DO NOTHING;`,
+			id,
+			pq.Array(post.ArticleIDs),
+		); err != nil {
+			return 0, err
+		}
+	}
+
+	return id, tx.Commit()
+}
+
+// CountSince returns the number of posts of kind sent to channelID since the
+// given time.
+func (s *PostPostgresStorage) CountSince(ctx context.Context, kind string, channelID int64, since time.Time) (int, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	var count int
+	if err := conn.GetContext(
+		ctx,
+		&count,
+		`SELECT COUNT(*) FROM posts WHERE kind = $1 AND channel_id = $2 AND created_at >= $3;`,
+		kind,
+		channelID,
+		since,
+	); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// CountChannelSince returns the number of posts of any kind sent to
+// channelID since the given time. Retracted posts are not counted.
+func (s *PostPostgresStorage) CountChannelSince(ctx context.Context, channelID int64, since time.Time) (int, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	var count int
+	if err := conn.GetContext(
+		ctx,
+		&count,
+		`SELECT COUNT(*) FROM posts WHERE channel_id = $1 AND created_at >= $2 AND retracted_at IS NULL;`,
+		channelID,
+		since,
+	); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Post returns the post with its article IDs, or nil if it does not exist.
+func (s *PostPostgresStorage) Post(ctx context.Context, id int64) (*model.Post, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var p dbPost
+	if err := conn.GetContext(ctx, &p, `SELECT `+postColumns+` FROM posts p WHERE p.id = $1`, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	post := p.toModel()
+	return &post, nil
+}
+
+// RecentPosts returns the latest posts, newest first.
+func (s *PostPostgresStorage) RecentPosts(ctx context.Context, limit int) ([]model.Post, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var posts []dbPost
+	if err := conn.SelectContext(ctx, &posts,
+		`SELECT `+postColumns+` FROM posts p ORDER BY p.created_at DESC, p.id DESC LIMIT $1`,
+		limit,
+	); err != nil {
+		return nil, err
+	}
+
+	return lo.Map(posts, func(p dbPost, _ int) model.Post { return p.toModel() }), nil
+}
+
+// MarkEdited records that the post text was regenerated.
+func (s *PostPostgresStorage) MarkEdited(ctx context.Context, id int64) error {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, `UPDATE posts SET edited_at = NOW() WHERE id = $1`, id)
+	return err
+}
+
+// Retract marks the post as retracted and returns its articles to the queue,
+// except those another post that is not retracted carried too.
+func (s *PostPostgresStorage) Retract(ctx context.Context, id int64) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE posts SET retracted_at = NOW() WHERE id = $1`,
+		id,
+	); err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE articles a SET posted_at = NULL
+			WHERE a.id IN (SELECT article_id FROM post_articles WHERE post_id = $1)
+				AND NOT EXISTS (
+					SELECT 1 FROM post_articles pa
+						JOIN posts p ON p.id = pa.post_id
+					WHERE pa.article_id = a.id AND pa.post_id <> $1 AND p.retracted_at IS NULL
+				)`,
+		id,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+const postColumns = `p.id, p.kind, p.channel_id, p.slot, p.article_count, p.model, p.estimated_tokens,
+	p.prompt_tokens, p.completion_tokens, p.fallback, p.message_id, p.media_message_ids,
+	ARRAY(SELECT pa.article_id FROM post_articles pa WHERE pa.post_id = p.id ORDER BY pa.article_id) AS article_ids,
+	p.edited_at, p.retracted_at, p.created_at`
+
+type dbPost struct {
+	ID               int64         `db:"id"`
+	Kind             string        `db:"kind"`
+	ChannelID        int64         `db:"channel_id"`
+	Slot             string        `db:"slot"`
+	ArticleCount     int           `db:"article_count"`
+	Model            string        `db:"model"`
+	EstimatedTokens  int           `db:"estimated_tokens"`
+	PromptTokens     int           `db:"prompt_tokens"`
+	CompletionTokens int           `db:"completion_tokens"`
+	Fallback         bool          `db:"fallback"`
+	MessageID        int           `db:"message_id"`
+	MediaMessageIDs  pq.Int64Array `db:"media_message_ids"`
+	ArticleIDs       pq.Int64Array `db:"article_ids"`
+	EditedAt         sql.NullTime  `db:"edited_at"`
+	RetractedAt      sql.NullTime  `db:"retracted_at"`
+	CreatedAt        time.Time     `db:"created_at"`
+}
+
+func (p dbPost) toModel() model.Post {
+	return model.Post{
+		ID:               p.ID,
+		Kind:             p.Kind,
+		ChannelID:        p.ChannelID,
+		Slot:             p.Slot,
+		ArticleCount:     p.ArticleCount,
+		Model:            p.Model,
+		EstimatedTokens:  p.EstimatedTokens,
+		PromptTokens:     p.PromptTokens,
+		CompletionTokens: p.CompletionTokens,
+		Fallback:         p.Fallback,
+		MessageID:        p.MessageID,
+		MediaMessageIDs:  lo.Map(p.MediaMessageIDs, func(id int64, _ int) int { return int(id) }),
+		ArticleIDs:       []int64(p.ArticleIDs),
+		EditedAt:         p.EditedAt.Time,
+		RetractedAt:      p.RetractedAt.Time,
+		CreatedAt:        p.CreatedAt,
+	}
+}
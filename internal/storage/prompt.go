@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/samber/lo"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+type PromptPostgresStorage struct {
+	db *sqlx.DB
+}
+
+func NewPromptStorage(db *sqlx.DB) *PromptPostgresStorage {
+	return &PromptPostgresStorage{db: db}
+}
+
+// ActivePrompt returns the active version of the named template, or nil if
+// the template was never saved.
+func (s *PromptPostgresStorage) ActivePrompt(ctx context.Context, name string) (*model.PromptTemplate, error) {
+	return s.getPrompt(ctx, `SELECT * FROM prompt_templates WHERE name = $1 AND active`, name)
+}
+
+// PromptVersion returns a specific version of the named template, or nil if
+// it does not exist.
+func (s *PromptPostgresStorage) PromptVersion(ctx context.Context, name string, version int) (*model.PromptTemplate, error) {
+	return s.getPrompt(ctx, `SELECT * FROM prompt_templates WHERE name = $1 AND version = $2`, name, version)
+}
+
+func (s *PromptPostgresStorage) getPrompt(ctx context.Context, query string, args ...any) (*model.PromptTemplate, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var p dbPromptTemplate
+	if err := conn.GetContext(ctx, &p, query, args...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	m := p.toModel()
+	return &m, nil
+}
+
+// PromptVersions returns all versions of the named template, newest first.
+func (s *PromptPostgresStorage) PromptVersions(ctx context.Context, name string) ([]model.PromptTemplate, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var prompts []dbPromptTemplate
+	if err := conn.SelectContext(ctx, &prompts,
+		`SELECT * FROM prompt_templates WHERE name = $1 ORDER BY version DESC`,
+		name,
+	); err != nil {
+		return nil, err
+	}
+
+	return lo.Map(prompts, func(p dbPromptTemplate, _ int) model.PromptTemplate { return p.toModel() }), nil
+}
+
+// SavePrompt stores body as the next version of the named template and makes
+// it the active one. It returns the new version number.
+func (s *PromptPostgresStorage) SavePrompt(ctx context.Context, name, body string, createdBy int64) (int, error) {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var version int
+	if err := tx.GetContext(ctx, &version,
+		`SELECT COALESCE(MAX(version), 0) + 1 FROM prompt_templates WHERE name = $1`,
+		name,
+	); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE prompt_templates SET active = FALSE WHERE name = $1 AND active`,
+		name,
+	); err != nil {
+		return 0, err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO prompt_templates (name, version, body, active, created_by)
+					VALUES ($1, $2, $3, TRUE, $4)`,
+		name, version, body, createdBy,
+	); err != nil {
+		return 0, err
+	}
+
+	return version, tx.Commit()
+}
+
+// ActivatePrompt makes the given version of the named template the active one.
+// Version 0 deactivates all versions, reverting to the built-in default.
+func (s *PromptPostgresStorage) ActivatePrompt(ctx context.Context, name string, version int) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE prompt_templates SET active = FALSE WHERE name = $1 AND active`,
+		name,
+	); err != nil {
+		return err
+	}
+
+	if version == 0 {
+		return tx.Commit()
+	}
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE prompt_templates SET active = TRUE WHERE name = $1 AND version = $2`,
+		name, version,
+	)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("prompt %q has no version %d", name, version)
+	}
+
+	return tx.Commit()
+}
+
+type dbPromptTemplate struct {
+	ID        int64     `db:"id"`
+	Name      string    `db:"name"`
+	Version   int       `db:"version"`
+	Body      string    `db:"body"`
+	Active    bool      `db:"active"`
+	CreatedBy int64     `db:"created_by"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (p dbPromptTemplate) toModel() model.PromptTemplate {
+	return model.PromptTemplate{
+		ID:        p.ID,
+		Name:      p.Name,
+		Version:   p.Version,
+		Body:      p.Body,
+		Active:    p.Active,
+		CreatedBy: p.CreatedBy,
+		CreatedAt: p.CreatedAt,
+	}
+}
@@ -2,6 +2,7 @@ package storage
 
 import (
 	"context"
+	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
@@ -21,6 +22,7 @@ func NewSourceStorage(db *sqlx.DB) *SourcePostgresStorage {
 	return &SourcePostgresStorage{db: db}
 }
 
+// Sources returns the sources that are not deleted, paused ones included.
 func (s *SourcePostgresStorage) Sources(ctx context.Context) ([]model.Source, error) {
 	conn, err := s.db.Connx(ctx)
 	if err != nil {
@@ -29,7 +31,25 @@ func (s *SourcePostgresStorage) Sources(ctx context.Context) ([]model.Source, er
 	defer conn.Close()
 
 	var sources []dbSource
-	if err := conn.SelectContext(ctx, &sources, `SELECT * FROM sources`); err != nil {
+	if err := conn.SelectContext(ctx, &sources, `SELECT * FROM sources WHERE deleted_at IS NULL`); err != nil {
+		return nil, err
+	}
+
+	return lo.Map(sources, func(source dbSource, _ int) model.Source { return source.toModel() }), nil
+}
+
+// DeletedSources returns the deleted sources, most recently deleted first.
+func (s *SourcePostgresStorage) DeletedSources(ctx context.Context) ([]model.Source, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var sources []dbSource
+	if err := conn.SelectContext(ctx, &sources,
+		`SELECT * FROM sources WHERE deleted_at IS NOT NULL ORDER BY deleted_at DESC`,
+	); err != nil {
 		return nil, err
 	}
 
@@ -96,6 +116,84 @@ func (s *SourcePostgresStorage) SetPriority(ctx context.Context, id int64, prior
 	return err
 }
 
+// SetEnabled pauses a source until resumed, or resumes it, clearing any
+// timed pause.
+func (s *SourcePostgresStorage) SetEnabled(ctx context.Context, id int64, enabled bool) error {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, `UPDATE sources SET enabled = $1, paused_until = NULL WHERE id = $2`, enabled, id)
+
+	return err
+}
+
+// PauseUntil pauses a source until the given time. It enables the source in
+// the same statement, so a source switched off before resumes when the pause
+// ends.
+func (s *SourcePostgresStorage) PauseUntil(ctx context.Context, id int64, until time.Time) error {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, `UPDATE sources SET enabled = TRUE, paused_until = $1 WHERE id = $2`, until.UTC(), id)
+
+	return err
+}
+
+// Update saves the name, feed URL, type, scraper config and insecure flag of
+// a source and records the old and new values in source_changes, attributed
+// to changedBy.
+func (s *SourcePostgresStorage) Update(ctx context.Context, source model.Source, changedBy int64) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	var old dbSource
+	if err := tx.GetContext(ctx, &old, `SELECT * FROM sources WHERE id = $1 FOR UPDATE`, source.ID); err != nil {
+		return err
+	}
+
+	var scraperCfg *dbScraperConfig
+	if source.ScraperConfig != nil {
+		scraperCfg = &dbScraperConfig{*source.ScraperConfig}
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE sources SET name = $1, feed_url = $2, source_type = $3, scraper_config = $4, insecure = $5
+		 WHERE id = $6`,
+		source.Name, source.FeedURL, source.SourceType, scraperCfg, source.Insecure, source.ID,
+	); err != nil {
+		return err
+	}
+
+	oldValues, err := json.Marshal(newSourceSnapshot(old.toModel()))
+	if err != nil {
+		return err
+	}
+	newValues, err := json.Marshal(newSourceSnapshot(source))
+	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO source_changes (source_id, changed_by, old_values, new_values) VALUES ($1, $2, $3, $4)`,
+		source.ID, changedBy, string(oldValues), string(newValues),
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Delete marks a source deleted. It is no longer listed or fetched, but its
+// articles stay and Restore brings it back.
 func (s *SourcePostgresStorage) Delete(ctx context.Context, id int64) error {
 	conn, err := s.db.Connx(ctx)
 	if err != nil {
@@ -103,13 +201,34 @@ func (s *SourcePostgresStorage) Delete(ctx context.Context, id int64) error {
 	}
 	defer conn.Close()
 
-	if _, err := conn.ExecContext(ctx, `DELETE FROM sources WHERE id = $1`, id); err != nil {
+	if _, err := conn.ExecContext(ctx, `UPDATE sources SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL`, id); err != nil {
 		return err
 	}
 
 	return nil
 }
 
+// Restore undoes Delete. It reports false if the source is not deleted.
+func (s *SourcePostgresStorage) Restore(ctx context.Context, id int64) (bool, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	res, err := conn.ExecContext(ctx, `UPDATE sources SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL`, id)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
 type dbScraperConfig struct {
 	model.ScraperConfig
 }
@@ -133,6 +252,26 @@ func (c dbScraperConfig) Value() (driver.Value, error) {
 	return string(b), nil
 }
 
+// sourceSnapshot holds the editable fields of a source as kept in
+// source_changes.
+type sourceSnapshot struct {
+	Name          string               `json:"name"`
+	FeedURL       string               `json:"feed_url"`
+	SourceType    string               `json:"source_type"`
+	ScraperConfig *model.ScraperConfig `json:"scraper_config,omitempty"`
+	Insecure      bool                 `json:"insecure"`
+}
+
+func newSourceSnapshot(s model.Source) sourceSnapshot {
+	return sourceSnapshot{
+		Name:          s.Name,
+		FeedURL:       s.FeedURL,
+		SourceType:    s.SourceType,
+		ScraperConfig: s.ScraperConfig,
+		Insecure:      s.Insecure,
+	}
+}
+
 type dbSource struct {
 	ID            int64           `db:"id"`
 	Name          string          `db:"name"`
@@ -141,18 +280,24 @@ type dbSource struct {
 	Insecure      bool            `db:"insecure"`
 	SourceType    string          `db:"source_type"`
 	ScraperConfig *dbScraperConfig `db:"scraper_config"`
+	Enabled       bool            `db:"enabled"`
+	PausedUntil   sql.NullTime    `db:"paused_until"`
+	DeletedAt     sql.NullTime    `db:"deleted_at"`
 	CreatedAt     time.Time       `db:"created_at"`
 }
 
 func (s dbSource) toModel() model.Source {
 	m := model.Source{
-		ID:         s.ID,
-		Name:       s.Name,
-		FeedURL:    s.FeedURL,
-		Priority:   s.Priority,
-		Insecure:   s.Insecure,
-		SourceType: s.SourceType,
-		CreatedAt:  s.CreatedAt,
+		ID:          s.ID,
+		Name:        s.Name,
+		FeedURL:     s.FeedURL,
+		Priority:    s.Priority,
+		Insecure:    s.Insecure,
+		SourceType:  s.SourceType,
+		Enabled:     s.Enabled,
+		PausedUntil: s.PausedUntil.Time,
+		DeletedAt:   s.DeletedAt.Time,
+		CreatedAt:   s.CreatedAt,
 	}
 	if s.ScraperConfig != nil {
 		cfg := s.ScraperConfig.ScraperConfig
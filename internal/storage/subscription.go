@@ -0,0 +1,215 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/samber/lo"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+type SubscriptionPostgresStorage struct {
+	db *sqlx.DB
+}
+
+func NewSubscriptionStorage(db *sqlx.DB) *SubscriptionPostgresStorage {
+	return &SubscriptionPostgresStorage{db: db}
+}
+
+// Add saves sub and returns its ID. It reports false and saves nothing when
+// the user already has limit subscriptions.
+func (s *SubscriptionPostgresStorage) Add(ctx context.Context, sub model.Subscription, limit int) (int64, bool, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return 0, false, err
+	}
+	defer conn.Close()
+
+	var id int64
+	err = conn.QueryRowxContext(
+		ctx,
+		`INSERT INTO subscriptions (user_id, chat_id, keywords, categories, source_ids, schedule, hour, timezone, language)
+					SELECT $1::bigint, $2::bigint, $3::text[], $4::text[], $5::bigint[], $6::text, $7::int, $8::text, $9::text
+					WHERE (SELECT COUNT(*) FROM subscriptions WHERE user_id = $1) < $10
+					RETURNING id;`,
+		sub.UserID,
+		sub.ChatID,
+		pq.Array(lo.If(sub.Keywords == nil, []string{}).Else(sub.Keywords)),
+		pq.Array(lo.If(sub.Categories == nil, []string{}).Else(sub.Categories)),
+		pq.Array(lo.If(sub.SourceIDs == nil, []int64{}).Else(sub.SourceIDs)),
+		sub.Schedule,
+		sub.Hour,
+		lo.If(sub.Timezone == "", "UTC").Else(sub.Timezone),
+		sub.Language,
+		limit,
+	).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+
+	return id, true, nil
+}
+
+// Subscriptions returns the subscriptions of all users.
+func (s *SubscriptionPostgresStorage) Subscriptions(ctx context.Context) ([]model.Subscription, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var subs []dbSubscription
+	if err := conn.SelectContext(ctx, &subs, `SELECT * FROM subscriptions ORDER BY id`); err != nil {
+		return nil, err
+	}
+
+	return lo.Map(subs, func(sub dbSubscription, _ int) model.Subscription { return sub.toModel() }), nil
+}
+
+// UserSubscriptions returns the subscriptions of one user, oldest first.
+func (s *SubscriptionPostgresStorage) UserSubscriptions(ctx context.Context, userID int64) ([]model.Subscription, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var subs []dbSubscription
+	if err := conn.SelectContext(ctx, &subs, `SELECT * FROM subscriptions WHERE user_id = $1 ORDER BY id`, userID); err != nil {
+		return nil, err
+	}
+
+	return lo.Map(subs, func(sub dbSubscription, _ int) model.Subscription { return sub.toModel() }), nil
+}
+
+// Delete removes a subscription of userID. It reports false if the user has
+// no such subscription.
+func (s *SubscriptionPostgresStorage) Delete(ctx context.Context, userID, id int64) (bool, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	res, err := conn.ExecContext(ctx, `DELETE FROM subscriptions WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return false, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return n > 0, nil
+}
+
+// DeleteAll removes every subscription of userID and returns how many there
+// were.
+func (s *SubscriptionPostgresStorage) DeleteAll(ctx context.Context, userID int64) (int, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Close()
+
+	res, err := conn.ExecContext(ctx, `DELETE FROM subscriptions WHERE user_id = $1`, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(n), nil
+}
+
+// SentArticleIDs returns which of articleIDs were already sent to userID.
+func (s *SubscriptionPostgresStorage) SentArticleIDs(ctx context.Context, userID int64, articleIDs []int64) ([]int64, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	var ids []int64
+	if err := conn.SelectContext(ctx, &ids,
+		`SELECT article_id FROM subscription_sent WHERE user_id = $1 AND article_id = ANY($2)`,
+		userID, pq.Array(articleIDs),
+	); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// MarkSent records the articles sent to the user of sub and when its digest
+// was last sent.
+func (s *SubscriptionPostgresStorage) MarkSent(ctx context.Context, sub model.Subscription, articleIDs []int64, at time.Time) error {
+	tx, err := s.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if len(articleIDs) > 0 {
+		if _, err := tx.ExecContext(ctx,
+			`INSERT INTO subscription_sent (user_id, article_id, sent_at)
+				SELECT $1, UNNEST($2::bigint[]), $3
+				ON CONFLICT DO NOTHING`,
+			sub.UserID, pq.Array(articleIDs), at.UTC(),
+		); err != nil {
+			return err
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		`UPDATE subscriptions SET last_sent_at = $1 WHERE id = $2`, at.UTC(), sub.ID,
+	); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+type dbSubscription struct {
+	ID         int64          `db:"id"`
+	UserID     int64          `db:"user_id"`
+	ChatID     int64          `db:"chat_id"`
+	Keywords   pq.StringArray `db:"keywords"`
+	Categories pq.StringArray `db:"categories"`
+	SourceIDs  pq.Int64Array  `db:"source_ids"`
+	Schedule   string         `db:"schedule"`
+	Hour       int            `db:"hour"`
+	Timezone   string         `db:"timezone"`
+	Language   string         `db:"language"`
+	LastSentAt sql.NullTime   `db:"last_sent_at"`
+	CreatedAt  time.Time      `db:"created_at"`
+}
+
+func (s dbSubscription) toModel() model.Subscription {
+	return model.Subscription{
+		ID:         s.ID,
+		UserID:     s.UserID,
+		ChatID:     s.ChatID,
+		Keywords:   []string(s.Keywords),
+		Categories: []string(s.Categories),
+		SourceIDs:  []int64(s.SourceIDs),
+		Schedule:   s.Schedule,
+		Hour:       s.Hour,
+		Timezone:   s.Timezone,
+		Language:   s.Language,
+		LastSentAt: s.LastSentAt.Time,
+		CreatedAt:  s.CreatedAt,
+	}
+}
@@ -0,0 +1,52 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type UserSettingsPostgresStorage struct {
+	db *sqlx.DB
+}
+
+func NewUserSettingsStorage(db *sqlx.DB) *UserSettingsPostgresStorage {
+	return &UserSettingsPostgresStorage{db: db}
+}
+
+// Language returns the reply language the user chose, or "" when they did
+// not choose one.
+func (s *UserSettingsPostgresStorage) Language(ctx context.Context, userID int64) (string, error) {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	var language string
+	if err := conn.GetContext(ctx, &language, `SELECT language FROM user_settings WHERE user_id = $1`, userID); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+	return language, nil
+}
+
+// SetLanguage stores the reply language of the user; "" clears the choice.
+func (s *UserSettingsPostgresStorage) SetLanguage(ctx context.Context, userID int64, language string) error {
+	conn, err := s.db.Connx(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx,
+		`INSERT INTO user_settings (user_id, language) VALUES ($1, $2)
+		 ON CONFLICT (user_id) DO UPDATE SET language = EXCLUDED.language, updated_at = NOW()`,
+		userID, language,
+	)
+	return err
+}
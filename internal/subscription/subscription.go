@@ -0,0 +1,170 @@
+// Package subscription sends personal digests to users in private chats,
+// built from the article store with the filters of their subscriptions.
+// Articles are tracked per user, independently of the channel posted state.
+package subscription
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/samber/lo"
+
+	"github.com/0x0BSoD/newsMaker/internal/botkit/markup"
+	"github.com/0x0BSoD/newsMaker/internal/i18n"
+	"github.com/0x0BSoD/newsMaker/internal/model"
+	"github.com/0x0BSoD/newsMaker/internal/reporter"
+)
+
+// candidatesFactor sets how many matches are read per digest article, so
+// some can be dropped as already sent.
+const candidatesFactor = 3
+
+// SubscriptionStorage is satisfied by storage.SubscriptionPostgresStorage.
+type SubscriptionStorage interface {
+	Subscriptions(ctx context.Context) ([]model.Subscription, error)
+	SentArticleIDs(ctx context.Context, userID int64, articleIDs []int64) ([]int64, error)
+	MarkSent(ctx context.Context, sub model.Subscription, articleIDs []int64, at time.Time) error
+}
+
+// ArticleSearcher is satisfied by storage.ArticlePostgresStorage.
+type ArticleSearcher interface {
+	Search(ctx context.Context, q model.ArticleSearch) ([]model.Article, int, error)
+}
+
+type Digester struct {
+	subs        SubscriptionStorage
+	articles    ArticleSearcher
+	bot         *tgbotapi.BotAPI
+	reporter    *reporter.Reporter
+	maxArticles int
+}
+
+// New returns a digester sending at most maxArticles articles per digest.
+func New(
+	subs SubscriptionStorage,
+	articles ArticleSearcher,
+	bot *tgbotapi.BotAPI,
+	rep *reporter.Reporter,
+	maxArticles int,
+) *Digester {
+	return &Digester{
+		subs:        subs,
+		articles:    articles,
+		bot:         bot,
+		reporter:    rep,
+		maxArticles: maxArticles,
+	}
+}
+
+// Start sends the due digests at the top of every hour.
+func (d *Digester) Start(ctx context.Context) error {
+	slog.Info("subscription digester started")
+
+	for {
+		next := time.Now().Truncate(time.Hour).Add(time.Hour)
+
+		select {
+		case <-time.After(time.Until(next)):
+			d.sendDue(ctx, time.Now())
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (d *Digester) sendDue(ctx context.Context, now time.Time) {
+	subs, err := d.subs.Subscriptions(ctx)
+	if err != nil {
+		slog.Error("load subscriptions failed", "err", err)
+		d.reporter.Notify(fmt.Sprintf("Subscriptions error: %v", err))
+		return
+	}
+
+	var failed int
+	for _, sub := range subs {
+		if !sub.Due(now) {
+			continue
+		}
+		if err := d.Send(ctx, sub, now); err != nil {
+			// A user who blocked the bot fails every time; that is not worth
+			// a report of its own.
+			slog.Warn("personal digest failed", "subscription", sub.ID, "user", sub.UserID, "err", err)
+			failed++
+		}
+	}
+	if failed > 0 {
+		d.reporter.Notify(fmt.Sprintf("Personal digests failed: %d", failed))
+	}
+}
+
+// Send sends the digest of sub with the matching articles fetched since its
+// last digest that the user has not received yet. Nothing is sent when there
+// are none.
+func (d *Digester) Send(ctx context.Context, sub model.Subscription, now time.Time) error {
+	search := searchFor(sub, now)
+	search.Limit = d.maxArticles * candidatesFactor
+
+	found, _, err := d.articles.Search(ctx, search)
+	if err != nil {
+		return fmt.Errorf("search articles: %w", err)
+	}
+
+	sent, err := d.subs.SentArticleIDs(ctx, sub.UserID, lo.Map(found, func(a model.Article, _ int) int64 { return a.ID }))
+	if err != nil {
+		return fmt.Errorf("load sent articles: %w", err)
+	}
+
+	articles := lo.Reject(found, func(a model.Article, _ int) bool { return lo.Contains(sent, a.ID) })
+	if len(articles) > d.maxArticles {
+		articles = articles[:d.maxArticles]
+	}
+
+	if len(articles) > 0 {
+		msg := tgbotapi.NewMessage(sub.ChatID, render(sub, articles))
+		msg.ParseMode = tgbotapi.ModeHTML
+		msg.DisableWebPagePreview = true
+		if _, err := d.bot.Send(msg); err != nil {
+			return fmt.Errorf("send digest: %w", err)
+		}
+	}
+
+	return d.subs.MarkSent(ctx, sub, lo.Map(articles, func(a model.Article, _ int) int64 { return a.ID }), now)
+}
+
+// searchFor is the article search of sub: any of its keywords from live
+// sources, published within one period and fetched since the last digest.
+// Bounding the fetch rather than the publication time keeps the articles a
+// feed lists only after the digest went out.
+func searchFor(sub model.Subscription, now time.Time) model.ArticleSearch {
+	keywords := make([]string, 0, len(sub.Keywords))
+	for _, k := range sub.Keywords {
+		if k = strings.ReplaceAll(k, `"`, ""); strings.TrimSpace(k) != "" {
+			keywords = append(keywords, `"`+k+`"`)
+		}
+	}
+
+	return model.ArticleSearch{
+		Query:       strings.Join(keywords, " OR "),
+		From:        now.Add(-sub.Period()),
+		FetchedFrom: sub.LastSentAt,
+		SourceIDs:   sub.SourceIDs,
+		Categories:  sub.Categories,
+		LiveSources: true,
+	}
+}
+
+func render(sub model.Subscription, articles []model.Article) string {
+	var sb strings.Builder
+	sb.WriteString(markup.EscapeForHTML(i18n.Text(sub.Language, i18n.PersonalDigestHeader, len(articles))) + "\n\n")
+
+	for _, a := range articles {
+		sb.WriteString(fmt.Sprintf("• <a href=\"%s\">%s</a> — %s\n", a.Link, markup.EscapeForHTML(a.Title), markup.EscapeForHTML(a.SourceName)))
+	}
+
+	sb.WriteString("\n" + markup.EscapeForHTML(i18n.Text(sub.Language, i18n.PersonalDigestFooter, sub.ID)))
+	return sb.String()
+}
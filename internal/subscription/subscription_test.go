@@ -0,0 +1,86 @@
+package subscription
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/0x0BSoD/newsMaker/internal/model"
+)
+
+func TestSubscriptionDue(t *testing.T) {
+	// 2026-06-08 is a Monday.
+	monday := time.Date(2026, 6, 8, 9, 0, 0, 0, time.UTC)
+
+	t.Run("should be due at its hour", func(t *testing.T) {
+		sub := model.Subscription{Schedule: model.ScheduleDaily, Hour: 9}
+		assert.True(t, sub.Due(monday))
+		assert.True(t, sub.Due(monday.AddDate(0, 0, 1)))
+		assert.False(t, sub.Due(monday.Add(time.Hour)))
+	})
+
+	t.Run("should not be due twice in an hour", func(t *testing.T) {
+		sub := model.Subscription{Schedule: model.ScheduleDaily, Hour: 9, LastSentAt: monday.Add(-time.Minute)}
+		assert.False(t, sub.Due(monday))
+	})
+
+	t.Run("should be due on mondays only when weekly", func(t *testing.T) {
+		sub := model.Subscription{Schedule: model.ScheduleWeekly, Hour: 9}
+		assert.True(t, sub.Due(monday))
+		assert.False(t, sub.Due(monday.AddDate(0, 0, 1)))
+	})
+
+	t.Run("should be due at its hour in its timezone", func(t *testing.T) {
+		// 09:00 UTC is 12:00 in Moscow, and 23:00 UTC on Sunday is already
+		// Monday there.
+		sub := model.Subscription{Schedule: model.ScheduleWeekly, Hour: 12, Timezone: "Europe/Moscow"}
+		assert.True(t, sub.Due(monday))
+		assert.False(t, sub.Due(monday.Add(-3*time.Hour)))
+
+		sub.Hour = 2
+		assert.True(t, sub.Due(monday.Add(-10*time.Hour)))
+	})
+}
+
+func TestSearchFor(t *testing.T) {
+	now := time.Date(2026, 6, 8, 9, 0, 0, 0, time.UTC)
+
+	t.Run("should join quoted keywords with OR", func(t *testing.T) {
+		search := searchFor(model.Subscription{
+			Keywords:   []string{"go", `"kubernetes operator"`, " "},
+			Categories: []string{"devops"},
+			SourceIDs:  []int64{3},
+		}, now)
+
+		assert.Equal(t, `"go" OR "kubernetes operator"`, search.Query)
+		assert.Equal(t, []string{"devops"}, search.Categories)
+		assert.Equal(t, []int64{3}, search.SourceIDs)
+	})
+
+	t.Run("should look back one period before the first digest", func(t *testing.T) {
+		assert.Equal(t, now.Add(-24*time.Hour), searchFor(model.Subscription{Schedule: model.ScheduleDaily}, now).From)
+		assert.Equal(t, now.Add(-7*24*time.Hour), searchFor(model.Subscription{Schedule: model.ScheduleWeekly}, now).From)
+	})
+
+	t.Run("should take articles fetched since the last digest", func(t *testing.T) {
+		last := now.Add(-3 * time.Hour)
+		search := searchFor(model.Subscription{Schedule: model.ScheduleDaily, LastSentAt: last}, now)
+		assert.Equal(t, now.Add(-24*time.Hour), search.From)
+		assert.Equal(t, last, search.FetchedFrom)
+	})
+
+	t.Run("should skip deleted sources", func(t *testing.T) {
+		assert.True(t, searchFor(model.Subscription{SourceIDs: []int64{3}}, now).LiveSources)
+	})
+}
+
+func TestRender(t *testing.T) {
+	text := render(model.Subscription{ID: 7, Language: "en"}, []model.Article{
+		{Title: "Go 1.30 <released>", Link: "https://go.dev/blog", SourceName: "Go Blog"},
+	})
+
+	assert.Contains(t, text, "Your digest — new articles: 1")
+	assert.Contains(t, text, `<a href="https://go.dev/blog">Go 1.30 &lt;released&gt;</a> — Go Blog`)
+	assert.Contains(t, text, "Subscription #7")
+}
@@ -0,0 +1,94 @@
+package summary
+
+import (
+	"sync"
+	"time"
+)
+
+// Breaker is a consecutive-failure circuit breaker. After threshold failures
+// in a row it opens and rejects calls until cooldown has passed; then it lets
+// a single trial call through and closes again on success.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	if threshold <= 0 {
+		threshold = 1
+	}
+	return &Breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow reports whether a call may be attempted now.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openedAt.IsZero() {
+		return true
+	}
+	if b.trial || b.now().Sub(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+// Release gives back a call allowed by Allow that ended without telling
+// anything about the provider, such as one the caller cancelled. A pending
+// trial is dropped so the next call can try again.
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trial = false
+}
+
+// Success closes the breaker.
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.openedAt = time.Time{}
+	b.trial = false
+}
+
+// Failure records a failed call and reports whether it opened a closed
+// breaker. A failed trial restarts the cool-down but reports false: the
+// breaker was open already.
+func (b *Breaker) Failure() (opened bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.trial {
+		b.trial = false
+		b.openedAt = b.now()
+		return false
+	}
+	if b.openedAt.IsZero() && b.failures >= b.threshold {
+		b.openedAt = b.now()
+		return true
+	}
+	return false
+}
+
+// Open reports whether the breaker currently rejects calls.
+func (b *Breaker) Open() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return !b.openedAt.IsZero() && (b.trial || b.now().Sub(b.openedAt) < b.cooldown)
+}
@@ -0,0 +1,173 @@
+package summary
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// CacheStorage persists generated outputs by key.
+type CacheStorage interface {
+	// Get returns the cached value for key; ok is false on a miss or when the
+	// entry has expired.
+	Get(ctx context.Context, key string) (value string, ok bool, err error)
+	Put(ctx context.Context, key, model, value string, expiresAt time.Time) error
+	// DeleteExpired drops expired entries and returns how many there were.
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// cacheCleanupInterval is how often Cached.Start drops expired entries.
+const cacheCleanupInterval = time.Hour
+
+var (
+	cacheHits   = expvar.NewInt("llm_cache_hits")
+	cacheMisses = expvar.NewInt("llm_cache_misses")
+)
+
+type bypassCacheKey struct{}
+
+// WithoutCache returns a context for which Cached neither reads nor writes the
+// cache, forcing a fresh generation.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, bypassCacheKey{}, true)
+}
+
+func cacheBypassed(ctx context.Context) bool {
+	bypass, _ := ctx.Value(bypassCacheKey{}).(bool)
+	return bypass
+}
+
+type cacheCheckKey struct{}
+
+// WithCacheCheck returns a context for which Cached only stores and serves
+// outputs that check accepts, so a rejected answer is generated afresh on the
+// next call instead of being replayed from the cache.
+func WithCacheCheck(ctx context.Context, check func(text string) error) context.Context {
+	return context.WithValue(ctx, cacheCheckKey{}, check)
+}
+
+// cacheAccepts runs the check set with WithCacheCheck, if any.
+func cacheAccepts(ctx context.Context, text string) bool {
+	check, _ := ctx.Value(cacheCheckKey{}).(func(string) error)
+	return check == nil || check(text) == nil
+}
+
+// Cached is a Summarizer decorator that stores outputs keyed by a hash of
+// the model, the generation options and the input. Hit and miss counts are
+// published through expvar as llm_cache_hits and llm_cache_misses.
+type Cached struct {
+	next  Summarizer
+	store CacheStorage
+	ttl   time.Duration
+}
+
+func NewCached(next Summarizer, store CacheStorage, ttl time.Duration) *Cached {
+	return &Cached{
+		next:  next,
+		store: store,
+		ttl:   ttl,
+	}
+}
+
+func (c *Cached) Summarize(ctx context.Context, input string, opts ...Option) (Result, error) {
+	return c.Stream(ctx, input, nil, opts...)
+}
+
+// Stream serves a hit as a single chunk.
+func (c *Cached) Stream(ctx context.Context, input string, fn StreamFunc, opts ...Option) (Result, error) {
+	if cacheBypassed(ctx) {
+		return c.next.Stream(ctx, input, fn, opts...)
+	}
+
+	model := c.next.Model()
+	key := cacheKey(model, buildOptions(opts), input)
+
+	cached, ok, err := c.store.Get(ctx, key)
+	if err != nil {
+		slog.Warn("llm cache read failed", "err", err)
+	}
+	if ok && !cacheAccepts(ctx, cached) {
+		slog.Debug("llm cache entry rejected", "key", key, "model", model)
+		ok = false
+	}
+	if ok {
+		cacheHits.Add(1)
+		slog.Debug("llm cache hit", "key", key, "model", model)
+		if fn != nil {
+			if err := fn(cached); err != nil {
+				return Result{}, err
+			}
+		}
+		return Result{Text: cached, Usage: Usage{Model: model}}, nil
+	}
+	cacheMisses.Add(1)
+
+	res, err := c.next.Stream(ctx, input, fn, opts...)
+	if err != nil {
+		return res, err
+	}
+	if strings.TrimSpace(res.Text) == "" || !cacheAccepts(ctx, res.Text) {
+		return res, nil
+	}
+
+	// A fallback provider may have answered: the output is stored under the
+	// model that produced it.
+	served := res.Usage.Model
+	if served == "" {
+		served = model
+	}
+	key = cacheKey(served, buildOptions(opts), input)
+	if err := c.store.Put(ctx, key, served, res.Text, time.Now().Add(c.ttl)); err != nil {
+		slog.Warn("llm cache write failed", "err", err)
+	}
+
+	return res, nil
+}
+
+// Start drops expired entries every hour until ctx is done.
+func (c *Cached) Start(ctx context.Context) error {
+	ticker := time.NewTicker(cacheCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			n, err := c.store.DeleteExpired(ctx)
+			if err != nil {
+				slog.Warn("llm cache cleanup failed", "err", err)
+				continue
+			}
+			slog.Debug("llm cache cleaned up", "expired", n)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (c *Cached) CountTokens(text string) (int, error) {
+	return c.next.CountTokens(text)
+}
+
+func (c *Cached) Model() string {
+	return c.next.Model()
+}
+
+// CacheStats returns the hit and miss counts since process start.
+func CacheStats() (hits, misses int64) {
+	return cacheHits.Value(), cacheMisses.Value()
+}
+
+func cacheKey(model string, opts Options, input string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00", model, opts.SystemPrompt)
+	if opts.Temperature != nil {
+		fmt.Fprintf(h, "%g", *opts.Temperature)
+	}
+	fmt.Fprintf(h, "\x00%d\x00%s\x00%s", opts.MaxTokens, opts.JSONSchema, input)
+	return hex.EncodeToString(h.Sum(nil))
+}
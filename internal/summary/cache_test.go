@@ -0,0 +1,107 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memCache map[string]string
+
+func (m memCache) Get(_ context.Context, key string) (string, bool, error) {
+	v, ok := m[key]
+	return v, ok, nil
+}
+
+func (m memCache) Put(_ context.Context, key, _, value string, _ time.Time) error {
+	m[key] = value
+	return nil
+}
+
+func (m memCache) DeleteExpired(context.Context) (int64, error) {
+	return 0, nil
+}
+
+func TestCached_Summarize(t *testing.T) {
+	t.Run("should serve repeated inputs from the cache", func(t *testing.T) {
+		next := &stubSummarizer{model: "llama3", text: "digest"}
+		cached := NewCached(next, memCache{}, time.Hour)
+
+		for range 3 {
+			res, err := cached.Summarize(context.Background(), "input", WithSystemPrompt("prompt"))
+			require.NoError(t, err)
+			assert.Equal(t, "digest", res.Text)
+		}
+		assert.Equal(t, 1, next.calls)
+	})
+
+	t.Run("should key on the system prompt", func(t *testing.T) {
+		next := &stubSummarizer{model: "llama3", text: "digest"}
+		cached := NewCached(next, memCache{}, time.Hour)
+
+		_, _ = cached.Summarize(context.Background(), "input", WithSystemPrompt("a"))
+		_, _ = cached.Summarize(context.Background(), "input", WithSystemPrompt("b"))
+		assert.Equal(t, 2, next.calls)
+	})
+
+	t.Run("should bypass the cache on request", func(t *testing.T) {
+		next := &stubSummarizer{model: "llama3", text: "digest"}
+		store := memCache{}
+		cached := NewCached(next, store, time.Hour)
+
+		_, _ = cached.Summarize(context.Background(), "input")
+		_, err := cached.Summarize(WithoutCache(context.Background()), "input")
+		require.NoError(t, err)
+		assert.Equal(t, 2, next.calls)
+	})
+
+	t.Run("should key on the model that answered", func(t *testing.T) {
+		store := memCache{}
+		chain := NewChain(nil,
+			Provider{Name: "local", Summarizer: &stubSummarizer{model: "local", err: errors.New("down")}, Breaker: NewBreaker(3, time.Minute)},
+			Provider{Name: "cloud", Summarizer: &stubSummarizer{model: "cloud", text: "digest"}, Breaker: NewBreaker(3, time.Minute)},
+		)
+		cached := NewCached(chain, store, time.Hour)
+
+		_, err := cached.Summarize(context.Background(), "input")
+		require.NoError(t, err)
+		assert.Contains(t, store, cacheKey("cloud", Options{}, "input"))
+		assert.NotContains(t, store, cacheKey("local", Options{}, "input"))
+	})
+
+	t.Run("should cache only outputs the check accepts", func(t *testing.T) {
+		next := &stubSummarizer{model: "llama3", text: "broken"}
+		cached := NewCached(next, memCache{}, time.Hour)
+		ctx := WithCacheCheck(context.Background(), func(text string) error {
+			if text != "valid" {
+				return errors.New("invalid")
+			}
+			return nil
+		})
+
+		_, _ = cached.Summarize(ctx, "input")
+		next.text = "valid"
+		res, err := cached.Summarize(ctx, "input")
+		require.NoError(t, err)
+		assert.Equal(t, "valid", res.Text)
+
+		_, _ = cached.Summarize(ctx, "input")
+		assert.Equal(t, 2, next.calls)
+	})
+
+	t.Run("should count hits and misses", func(t *testing.T) {
+		hits, misses := CacheStats()
+		cached := NewCached(&stubSummarizer{model: "m", text: "t"}, memCache{}, time.Hour)
+
+		_, _ = cached.Summarize(context.Background(), "x")
+		_, _ = cached.Summarize(context.Background(), "x")
+
+		gotHits, gotMisses := CacheStats()
+		assert.Equal(t, hits+1, gotHits)
+		assert.Equal(t, misses+1, gotMisses)
+	})
+}
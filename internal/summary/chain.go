@@ -0,0 +1,172 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/0x0BSoD/newsMaker/internal/reporter"
+)
+
+// ErrAllProvidersFailed is returned by Chain when no provider produced output.
+// Callers fall back to their non-LLM output.
+var ErrAllProvidersFailed = errors.New("all LLM providers failed")
+
+// Provider is one entry of a Chain. The per-provider timeout is the one the
+// Summarizer was constructed with.
+type Provider struct {
+	Name       string
+	Summarizer Summarizer
+	Breaker    *Breaker
+}
+
+// Chain tries providers in order and returns the first successful result.
+// A provider whose breaker is open is skipped until its cool-down passes.
+// Admins are notified when the chain has to fall back past the first
+// provider and when it recovers.
+type Chain struct {
+	providers []Provider
+	reporter  *reporter.Reporter
+
+	mu sync.Mutex
+	// servedBy is the provider that answered the last request, empty when
+	// none did.
+	servedBy string
+}
+
+func NewChain(rep *reporter.Reporter, providers ...Provider) *Chain {
+	c := &Chain{
+		providers: providers,
+		reporter:  rep,
+	}
+	if len(providers) > 0 {
+		c.servedBy = providers[0].Name
+	}
+	return c
+}
+
+func (c *Chain) Summarize(ctx context.Context, input string, opts ...Option) (Result, error) {
+	return c.run(ctx, func(s Summarizer) (Result, bool, error) {
+		res, err := s.Summarize(ctx, input, opts...)
+		return res, false, err
+	})
+}
+
+// Stream falls back to the next provider only while nothing has been passed
+// to fn yet; a provider that fails mid-stream ends the call with its error.
+func (c *Chain) Stream(ctx context.Context, input string, fn StreamFunc, opts ...Option) (Result, error) {
+	return c.run(ctx, func(s Summarizer) (Result, bool, error) {
+		var streamed bool
+		res, err := s.Stream(ctx, input, func(chunk string) error {
+			streamed = true
+			if fn == nil {
+				return nil
+			}
+			return fn(chunk)
+		}, opts...)
+		return res, streamed, err
+	})
+}
+
+// CountTokens counts with the provider that would currently serve a request.
+func (c *Chain) CountTokens(text string) (int, error) {
+	if p, ok := c.current(); ok {
+		return p.Summarizer.CountTokens(text)
+	}
+	return EstimateTokens(text), nil
+}
+
+// Model returns the model of the provider that would currently serve a
+// request. The model that actually answered is reported in Usage.Model.
+func (c *Chain) Model() string {
+	if p, ok := c.current(); ok {
+		return p.Summarizer.Model()
+	}
+	return ""
+}
+
+func (c *Chain) current() (Provider, bool) {
+	for _, p := range c.providers {
+		if !p.Breaker.Open() {
+			return p, true
+		}
+	}
+	return Provider{}, false
+}
+
+func (c *Chain) run(ctx context.Context, call func(s Summarizer) (Result, bool, error)) (Result, error) {
+	var errs []string
+
+	for _, p := range c.providers {
+		if err := ctx.Err(); err != nil {
+			return Result{}, err
+		}
+
+		if !p.Breaker.Allow() {
+			slog.Debug("llm provider skipped, circuit open", "provider", p.Name)
+			errs = append(errs, fmt.Sprintf("%s: circuit open", p.Name))
+			continue
+		}
+
+		res, streamed, err := call(p.Summarizer)
+		if err == nil && strings.TrimSpace(res.Text) != "" {
+			p.Breaker.Success()
+			c.setServedBy(p.Name)
+			if res.Usage.Model == "" {
+				res.Usage.Model = p.Summarizer.Model()
+			}
+			return res, nil
+		}
+		if err == nil {
+			err = errors.New("empty response")
+		}
+
+		// The caller gave up; that says nothing about the provider's health,
+		// but a trial call it was allowed must not keep the breaker half-open.
+		if ctx.Err() != nil {
+			p.Breaker.Release()
+			return Result{}, ctx.Err()
+		}
+
+		slog.Warn("llm provider failed", "provider", p.Name, "err", err)
+		errs = append(errs, fmt.Sprintf("%s: %v", p.Name, err))
+
+		if p.Breaker.Failure() {
+			slog.Error("llm provider circuit opened", "provider", p.Name)
+			c.reporter.Notify(fmt.Sprintf("LLM provider %q disabled for cool-down after repeated failures: %v", p.Name, err))
+		}
+
+		if streamed {
+			return Result{}, fmt.Errorf("%s failed mid-stream: %w", p.Name, err)
+		}
+	}
+
+	c.setServedBy("")
+	return Result{}, fmt.Errorf("%w: %s", ErrAllProvidersFailed, strings.Join(errs, "; "))
+}
+
+// setServedBy records which provider answered and reports when that changes,
+// i.e. when the chain degrades past the first provider or recovers.
+func (c *Chain) setServedBy(name string) {
+	c.mu.Lock()
+	prev := c.servedBy
+	c.servedBy = name
+	c.mu.Unlock()
+
+	if prev == name || len(c.providers) == 0 {
+		return
+	}
+
+	primary := c.providers[0].Name
+	switch name {
+	case primary:
+		c.reporter.Notify(fmt.Sprintf("LLM chain recovered: %q is serving requests again", primary))
+	case "":
+		c.reporter.Notify("LLM chain degraded: no provider available, falling back to plain formatting")
+	default:
+		c.reporter.Notify(fmt.Sprintf("LLM chain degraded: %q unavailable, served by %q", primary, name))
+	}
+}
@@ -0,0 +1,152 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubSummarizer returns text, or err when it is set.
+type stubSummarizer struct {
+	model string
+	text  string
+	err   error
+	calls int
+}
+
+func (s *stubSummarizer) Summarize(_ context.Context, _ string, _ ...Option) (Result, error) {
+	s.calls++
+	if s.err != nil {
+		return Result{}, s.err
+	}
+	return Result{Text: s.text}, nil
+}
+
+func (s *stubSummarizer) Stream(ctx context.Context, input string, fn StreamFunc, opts ...Option) (Result, error) {
+	res, err := s.Summarize(ctx, input, opts...)
+	if err == nil && fn != nil {
+		err = fn(res.Text)
+	}
+	return res, err
+}
+
+func (s *stubSummarizer) CountTokens(text string) (int, error) { return EstimateTokens(text), nil }
+func (s *stubSummarizer) Model() string                        { return s.model }
+
+func TestChain_Summarize(t *testing.T) {
+	t.Run("should fall back to the next provider", func(t *testing.T) {
+		primary := &stubSummarizer{model: "local", err: errors.New("connection refused")}
+		secondary := &stubSummarizer{model: "cloud", text: "digest"}
+		chain := NewChain(nil,
+			Provider{Name: "local", Summarizer: primary, Breaker: NewBreaker(3, time.Minute)},
+			Provider{Name: "cloud", Summarizer: secondary, Breaker: NewBreaker(3, time.Minute)},
+		)
+
+		res, err := chain.Summarize(context.Background(), "input")
+		require.NoError(t, err)
+		assert.Equal(t, "digest", res.Text)
+	})
+
+	t.Run("should skip a provider while its circuit is open", func(t *testing.T) {
+		primary := &stubSummarizer{model: "local", err: errors.New("timeout")}
+		secondary := &stubSummarizer{model: "cloud", text: "digest"}
+		chain := NewChain(nil,
+			Provider{Name: "local", Summarizer: primary, Breaker: NewBreaker(2, time.Hour)},
+			Provider{Name: "cloud", Summarizer: secondary, Breaker: NewBreaker(2, time.Hour)},
+		)
+
+		for range 5 {
+			_, err := chain.Summarize(context.Background(), "input")
+			require.NoError(t, err)
+		}
+		assert.Equal(t, 2, primary.calls)
+		assert.Equal(t, 5, secondary.calls)
+		assert.Equal(t, "cloud", chain.Model())
+	})
+
+	t.Run("should report when every provider fails", func(t *testing.T) {
+		chain := NewChain(nil,
+			Provider{Name: "local", Summarizer: &stubSummarizer{err: errors.New("down")}, Breaker: NewBreaker(1, time.Hour)},
+			Provider{Name: "cloud", Summarizer: &stubSummarizer{text: "  "}, Breaker: NewBreaker(1, time.Hour)},
+		)
+
+		_, err := chain.Summarize(context.Background(), "input")
+		assert.ErrorIs(t, err, ErrAllProvidersFailed)
+	})
+
+	t.Run("should not count caller cancellation as a provider failure", func(t *testing.T) {
+		primary := &stubSummarizer{err: context.Canceled}
+		breaker := NewBreaker(1, time.Hour)
+		chain := NewChain(nil, Provider{Name: "local", Summarizer: primary, Breaker: breaker})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := chain.Summarize(ctx, "input")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.False(t, breaker.Open())
+	})
+
+	t.Run("should release a cancelled trial call", func(t *testing.T) {
+		now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+		breaker := NewBreaker(1, time.Minute)
+		breaker.now = func() time.Time { return now }
+		breaker.Failure()
+		now = now.Add(2 * time.Minute)
+
+		primary := &stubSummarizer{model: "local", err: context.Canceled}
+		chain := NewChain(nil, Provider{Name: "local", Summarizer: primary, Breaker: breaker})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := chain.Summarize(ctx, "input")
+		require.ErrorIs(t, err, context.Canceled)
+
+		primary.err, primary.text = nil, "digest"
+		res, err := chain.Summarize(context.Background(), "input")
+		require.NoError(t, err)
+		assert.Equal(t, "digest", res.Text)
+	})
+
+	t.Run("should report the model that answered", func(t *testing.T) {
+		primary := &stubSummarizer{model: "local", err: errors.New("connection refused")}
+		secondary := &stubSummarizer{model: "cloud", text: "digest"}
+		chain := NewChain(nil,
+			Provider{Name: "local", Summarizer: primary, Breaker: NewBreaker(3, time.Minute)},
+			Provider{Name: "cloud", Summarizer: secondary, Breaker: NewBreaker(3, time.Minute)},
+		)
+
+		res, err := chain.Summarize(context.Background(), "input")
+		require.NoError(t, err)
+		assert.Equal(t, "cloud", res.Usage.Model)
+		assert.Equal(t, "local", chain.Model(), "the primary is still tried first")
+	})
+}
+
+func TestBreaker(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	b := NewBreaker(2, 10*time.Minute)
+	b.now = func() time.Time { return now }
+
+	assert.False(t, b.Failure())
+	assert.True(t, b.Allow())
+	assert.True(t, b.Failure(), "second failure should open the circuit")
+	assert.False(t, b.Allow())
+
+	now = now.Add(11 * time.Minute)
+	assert.True(t, b.Allow(), "a trial call is allowed after cool-down")
+	assert.False(t, b.Allow(), "only one trial call at a time")
+
+	assert.False(t, b.Failure(), "a failed trial is not reported as opening again")
+	assert.False(t, b.Allow(), "failed trial restarts the cool-down")
+
+	now = now.Add(11 * time.Minute)
+	assert.True(t, b.Allow())
+	b.Success()
+	assert.True(t, b.Allow())
+	assert.False(t, b.Open())
+}
@@ -0,0 +1,106 @@
+package summary
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ollama/ollama/api"
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// Embedder turns texts into embedding vectors, one per text and in order.
+type Embedder interface {
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+	// Model returns the name of the embedding model. Vectors of different
+	// models are not comparable.
+	Model() string
+}
+
+type OllamaEmbedder struct {
+	client  *api.Client
+	model   string
+	timeout time.Duration
+}
+
+func NewOllamaEmbedder(baseURL, model string, timeout time.Duration) *OllamaEmbedder {
+	return &OllamaEmbedder{
+		client:  newOllamaClient(baseURL),
+		model:   model,
+		timeout: timeout,
+	}
+}
+
+func (e *OllamaEmbedder) Model() string {
+	return e.model
+}
+
+func (e *OllamaEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	resp, err := e.client.Embed(ctx, &api.EmbedRequest{
+		Model: e.model,
+		Input: texts,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("embed: %w", err)
+	}
+	if len(resp.Embeddings) != len(texts) {
+		return nil, fmt.Errorf("embed: got %d vectors for %d texts", len(resp.Embeddings), len(texts))
+	}
+
+	return resp.Embeddings, nil
+}
+
+type OpenAIEmbedder struct {
+	client  *openai.Client
+	model   string
+	timeout time.Duration
+}
+
+// NewOpenAIEmbedder creates an embedder backed by any OpenAI-compatible
+// /embeddings endpoint; leave baseURL empty for api.openai.com.
+func NewOpenAIEmbedder(baseURL, apiKey, model string, timeout time.Duration) *OpenAIEmbedder {
+	cfg := openai.DefaultConfig(apiKey)
+	if baseURL != "" {
+		cfg.BaseURL = baseURL
+	}
+	return &OpenAIEmbedder{
+		client:  openai.NewClientWithConfig(cfg),
+		model:   model,
+		timeout: timeout,
+	}
+}
+
+func (e *OpenAIEmbedder) Model() string {
+	return e.model
+}
+
+func (e *OpenAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	resp, err := e.client.CreateEmbeddings(ctx, openai.EmbeddingRequestStrings{
+		Input: texts,
+		Model: openai.EmbeddingModel(e.model),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("embed: %w", err)
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, d := range resp.Data {
+		if d.Index < 0 || d.Index >= len(vectors) {
+			return nil, fmt.Errorf("embed: unexpected index %d", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	for i, v := range vectors {
+		if v == nil {
+			return nil, fmt.Errorf("embed: no vector for text %d", i)
+		}
+	}
+
+	return vectors, nil
+}
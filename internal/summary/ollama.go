@@ -5,74 +5,91 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
-	"sync"
 	"time"
 
-	tiktoken "github.com/hupe1980/go-tiktoken"
 	"github.com/ollama/ollama/api"
 )
 
 type OllamaSummarizer struct {
 	client  *api.Client
-	prompt  string
 	model   string
 	timeout time.Duration
-	mu      sync.Mutex
 }
 
-// CountTokens Simple estimation of token usage
+// CountTokens estimates token usage. Ollama has no tokenize endpoint and its
+// models do not share a tokenizer, so this uses EstimateTokens; the exact
+// prompt size is reported in Result.Usage after generation.
 func (o *OllamaSummarizer) CountTokens(text string) (int, error) {
-	enc, err := tiktoken.NewEncodingForModel("ada")
-	if err != nil {
-		return 0, err
-	}
-
-	_, tokens, err := enc.Encode(text, nil, nil)
-	if err != nil {
-		return 0, err
-	}
-
-	return len(tokens), nil
+	return EstimateTokens(text), nil
 }
 
-func NewOllamaSummarizer(baseURL, prompt, model string, timeout time.Duration) *OllamaSummarizer {
-	httpClient := &http.Client{}
-
-	c := api.NewClient(&url.URL{
-		Scheme: "http",
-		Host:   baseURL,
-		Path:   "/",
-	}, httpClient)
-
+func NewOllamaSummarizer(baseURL, model string, timeout time.Duration) *OllamaSummarizer {
 	return &OllamaSummarizer{
-		client:  c,
-		prompt:  prompt,
+		client:  newOllamaClient(baseURL),
 		model:   model,
 		timeout: timeout,
 	}
 }
 
-func (o *OllamaSummarizer) Summarize(text string) (string, error) {
-	o.mu.Lock()
-	defer o.mu.Unlock()
+func (o *OllamaSummarizer) Model() string {
+	return o.model
+}
+
+func (o *OllamaSummarizer) Summarize(ctx context.Context, input string, opts ...Option) (Result, error) {
+	return o.Stream(ctx, input, nil, opts...)
+}
+
+func (o *OllamaSummarizer) Stream(ctx context.Context, input string, fn StreamFunc, opts ...Option) (Result, error) {
+	options := buildOptions(opts)
 
 	req := &api.GenerateRequest{
-		Model:  o.model,
-		System: o.prompt,
-		Prompt: text,
+		Model:   o.model,
+		System:  options.SystemPrompt,
+		Prompt:  input,
+		Options: map[string]any{},
+		Format:  options.JSONSchema,
+	}
+	if options.Temperature != nil {
+		req.Options["temperature"] = *options.Temperature
+	}
+	if options.MaxTokens > 0 {
+		req.Options["num_predict"] = options.MaxTokens
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), o.timeout)
+	ctx, cancel := context.WithTimeout(ctx, o.timeout)
 	defer cancel()
 
-	var responseFlow []string
+	var (
+		sb     strings.Builder
+		result Result
+	)
 	err := o.client.Generate(ctx, req, func(resp api.GenerateResponse) error {
-		responseFlow = append(responseFlow, resp.Response)
+		sb.WriteString(resp.Response)
+		if resp.Done {
+			result.Usage = Usage{
+				PromptTokens:     resp.PromptEvalCount,
+				CompletionTokens: resp.EvalCount,
+			}
+		}
+		if fn != nil && resp.Response != "" {
+			return fn(resp.Response)
+		}
 		return nil
 	})
 	if err != nil {
-		return "", err
+		return Result{}, err
 	}
 
-	return strings.Join(responseFlow, ""), nil
+	result.Text = sb.String()
+	result.Usage.Model = o.model
+	return result, nil
+}
+
+// newOllamaClient creates a client for the Ollama server at host:port baseURL.
+func newOllamaClient(baseURL string) *api.Client {
+	return api.NewClient(&url.URL{
+		Scheme: "http",
+		Host:   baseURL,
+		Path:   "/",
+	}, &http.Client{})
 }
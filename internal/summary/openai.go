@@ -2,58 +2,169 @@ package summary
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"strings"
 	"time"
 
 	openai "github.com/sashabaranov/go-openai"
 )
 
 type OpenAISummarizer struct {
-	client  *openai.Client
-	prompt  string
-	model   string
-	timeout time.Duration
+	client    *openai.Client
+	tokenizer *serverTokenizer
+	model     string
+	timeout   time.Duration
 }
 
+// CountTokens uses the tiktoken encoding of known OpenAI models. For other
+// models served through an OpenAI-compatible API it asks the server's
+// /tokenize endpoint and falls back to EstimateTokens when there is none.
 func (o *OpenAISummarizer) CountTokens(text string) (int, error) {
-	//TODO implement me
-	panic("implement me")
+	if name, ok := openAIEncodingName(o.model); ok {
+		return countWithEncoding(name, text)
+	}
+
+	n, ok, err := o.tokenizer.count(context.Background(), text)
+	if err != nil {
+		slog.Debug("server tokenizer failed, estimating", "model", o.model, "err", err)
+	}
+	if ok && err == nil {
+		return n, nil
+	}
+
+	return EstimateTokens(text), nil
 }
 
 // NewOpenAISummarizer creates a summarizer backed by any OpenAI-compatible API.
 // Set baseURL to a non-empty string to point at a local server (LM Studio,
 // llama.cpp, Ollama's /v1 endpoint, etc.); leave empty for api.openai.com.
-func NewOpenAISummarizer(baseURL, apiKey, prompt, model string, timeout time.Duration) *OpenAISummarizer {
+func NewOpenAISummarizer(baseURL, apiKey, model string, timeout time.Duration) *OpenAISummarizer {
 	cfg := openai.DefaultConfig(apiKey)
 	if baseURL != "" {
 		cfg.BaseURL = baseURL
 	}
 	return &OpenAISummarizer{
-		client:  openai.NewClientWithConfig(cfg),
-		prompt:  prompt,
-		model:   model,
-		timeout: timeout,
+		client:    openai.NewClientWithConfig(cfg),
+		tokenizer: newServerTokenizer(baseURL, model),
+		model:     model,
+		timeout:   timeout,
 	}
 }
 
-func (o *OpenAISummarizer) Summarize(text string) (string, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), o.timeout)
+func (o *OpenAISummarizer) Model() string {
+	return o.model
+}
+
+func (o *OpenAISummarizer) Summarize(ctx context.Context, input string, opts ...Option) (Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, o.timeout)
 	defer cancel()
 
-	resp, err := o.client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
-		Model: o.model,
-		Messages: []openai.ChatCompletionMessage{
-			{Role: openai.ChatMessageRoleSystem, Content: o.prompt},
-			{Role: openai.ChatMessageRoleUser, Content: text},
-		},
-	})
+	resp, err := o.client.CreateChatCompletion(ctx, o.request(input, buildOptions(opts)))
 	if err != nil {
-		return "", fmt.Errorf("chat completion: %w", err)
+		return Result{}, fmt.Errorf("chat completion: %w", err)
 	}
 
 	if len(resp.Choices) == 0 {
-		return "", fmt.Errorf("empty response from model %q", o.model)
+		return Result{}, fmt.Errorf("empty response from model %q", o.model)
 	}
 
-	return resp.Choices[0].Message.Content, nil
+	return Result{
+		Text: resp.Choices[0].Message.Content,
+		Usage: Usage{
+			PromptTokens:     resp.Usage.PromptTokens,
+			CompletionTokens: resp.Usage.CompletionTokens,
+			Model:            o.model,
+		},
+	}, nil
+}
+
+func (o *OpenAISummarizer) Stream(ctx context.Context, input string, fn StreamFunc, opts ...Option) (Result, error) {
+	ctx, cancel := context.WithTimeout(ctx, o.timeout)
+	defer cancel()
+
+	req := o.request(input, buildOptions(opts))
+	req.Stream = true
+	req.StreamOptions = &openai.StreamOptions{IncludeUsage: true}
+
+	stream, err := o.client.CreateChatCompletionStream(ctx, req)
+	if err != nil {
+		return Result{}, fmt.Errorf("chat completion stream: %w", err)
+	}
+	defer stream.Close()
+
+	var (
+		sb     strings.Builder
+		result Result
+	)
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return Result{}, fmt.Errorf("chat completion stream: %w", err)
+		}
+
+		if resp.Usage != nil {
+			result.Usage = Usage{
+				PromptTokens:     resp.Usage.PromptTokens,
+				CompletionTokens: resp.Usage.CompletionTokens,
+			}
+		}
+		if len(resp.Choices) == 0 {
+			continue
+		}
+
+		chunk := resp.Choices[0].Delta.Content
+		sb.WriteString(chunk)
+		if fn != nil && chunk != "" {
+			if err := fn(chunk); err != nil {
+				return Result{}, err
+			}
+		}
+	}
+
+	result.Text = sb.String()
+	result.Usage.Model = o.model
+	return result, nil
+}
+
+func (o *OpenAISummarizer) request(input string, options Options) openai.ChatCompletionRequest {
+	req := openai.ChatCompletionRequest{
+		Model: o.model,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleUser, Content: input},
+		},
+		// MaxTokens rather than MaxCompletionTokens: most OpenAI-compatible
+		// local servers only understand the former.
+		MaxTokens: options.MaxTokens,
+	}
+	if options.SystemPrompt != "" {
+		req.Messages = append([]openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: options.SystemPrompt},
+		}, req.Messages...)
+	}
+	if options.Temperature != nil {
+		req.Temperature = *options.Temperature
+		// The request drops a zero temperature as empty, so the API default
+		// of 1 would apply; the smallest positive float is sent instead.
+		if req.Temperature == 0 {
+			req.Temperature = math.SmallestNonzeroFloat32
+		}
+	}
+	if len(options.JSONSchema) > 0 {
+		req.ResponseFormat = &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+				Name:   "output",
+				Schema: options.JSONSchema,
+				Strict: true,
+			},
+		}
+	}
+	return req
 }
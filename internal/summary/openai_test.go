@@ -0,0 +1,25 @@
+package summary
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAISummarizer_request(t *testing.T) {
+	o := NewOpenAISummarizer("", "", "gpt-4o-mini", 0)
+
+	t.Run("should send a zero temperature", func(t *testing.T) {
+		body, err := json.Marshal(o.request("input", buildOptions([]Option{WithTemperature(0)})))
+		require.NoError(t, err)
+		assert.Contains(t, string(body), `"temperature":`)
+	})
+
+	t.Run("should leave the temperature to the model by default", func(t *testing.T) {
+		body, err := json.Marshal(o.request("input", buildOptions(nil)))
+		require.NoError(t, err)
+		assert.NotContains(t, string(body), `"temperature":`)
+	})
+}
@@ -0,0 +1,90 @@
+// Package summary wraps LLM providers behind a single Summarizer interface
+// used by the news and GitHub digests.
+package summary
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// Summarizer generates text from an input prompt. Implementations must honor
+// ctx cancellation and be safe for concurrent use.
+type Summarizer interface {
+	// Summarize runs the model on input and returns the complete output.
+	Summarize(ctx context.Context, input string, opts ...Option) (Result, error)
+	// Stream runs the model on input and calls fn with every chunk of output
+	// as it arrives. The returned Result holds the full text.
+	Stream(ctx context.Context, input string, fn StreamFunc, opts ...Option) (Result, error)
+	// CountTokens estimates how many tokens text occupies for this model.
+	CountTokens(text string) (int, error)
+	// Model returns the name of the model used for generation.
+	Model() string
+}
+
+// StreamFunc receives partial output. Returning an error aborts generation.
+type StreamFunc func(chunk string) error
+
+// Usage reports token usage as returned by the provider. Zero values mean the
+// provider did not report them.
+type Usage struct {
+	PromptTokens     int
+	CompletionTokens int
+	// Model is the model that served the call, which is not necessarily the
+	// one Summarizer.Model reports after a fallback.
+	Model string
+}
+
+// Add accumulates usage of several calls that produced one output. The model
+// of the first call that reported one is kept.
+func (u *Usage) Add(other Usage) {
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	if u.Model == "" {
+		u.Model = other.Model
+	}
+}
+
+// Result is the outcome of a single generation call.
+type Result struct {
+	Text  string
+	Usage Usage
+}
+
+// Options are per-call generation parameters.
+type Options struct {
+	SystemPrompt string
+	// Temperature is nil when the model default should be used.
+	Temperature *float32
+	// MaxTokens caps the generated output; 0 means no limit.
+	MaxTokens int
+	// JSONSchema, when set, constrains the output to JSON matching this
+	// schema. Providers that cannot enforce it still receive it as a hint,
+	// so callers must validate the output.
+	JSONSchema json.RawMessage
+}
+
+type Option func(*Options)
+
+func WithSystemPrompt(prompt string) Option {
+	return func(o *Options) { o.SystemPrompt = prompt }
+}
+
+func WithTemperature(t float32) Option {
+	return func(o *Options) { o.Temperature = &t }
+}
+
+func WithMaxTokens(n int) Option {
+	return func(o *Options) { o.MaxTokens = n }
+}
+
+func WithJSONSchema(schema json.RawMessage) Option {
+	return func(o *Options) { o.JSONSchema = schema }
+}
+
+func buildOptions(opts []Option) Options {
+	var o Options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
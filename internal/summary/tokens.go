@@ -0,0 +1,168 @@
+package summary
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	tiktoken "github.com/hupe1980/go-tiktoken"
+)
+
+// newerOpenAIPrefixes lists OpenAI model families that use o200k_base but are
+// missing from the tiktoken model tables.
+var newerOpenAIPrefixes = []string{"gpt-4.1", "gpt-4.5", "gpt-5", "o1", "o3", "o4"}
+
+var (
+	encodingsMu sync.Mutex
+	encodings   = make(map[string]*tiktoken.Encoding)
+)
+
+// encodingByName returns a cached tiktoken encoding. Building one parses the
+// whole BPE rank table, so it must not happen on every CountTokens call.
+func encodingByName(name string) (*tiktoken.Encoding, error) {
+	encodingsMu.Lock()
+	defer encodingsMu.Unlock()
+
+	if enc, ok := encodings[name]; ok {
+		return enc, nil
+	}
+	enc, err := tiktoken.NewEncodingByName(name)
+	if err != nil {
+		return nil, err
+	}
+	encodings[name] = enc
+	return enc, nil
+}
+
+// openAIEncodingName resolves the tiktoken encoding for an OpenAI model.
+// The second return value is false for models tiktoken knows nothing about,
+// e.g. open-weight models behind an OpenAI-compatible server.
+func openAIEncodingName(model string) (string, bool) {
+	if name, ok := tiktoken.ModelToEncoding[model]; ok {
+		return name, true
+	}
+	for prefix, name := range tiktoken.ModelPrefixToEncoding {
+		if strings.HasPrefix(model, prefix) {
+			return name, true
+		}
+	}
+	for _, prefix := range newerOpenAIPrefixes {
+		if strings.HasPrefix(model, prefix) {
+			return tiktoken.O200kBase, true
+		}
+	}
+	return "", false
+}
+
+func countWithEncoding(name, text string) (int, error) {
+	enc, err := encodingByName(name)
+	if err != nil {
+		return 0, err
+	}
+	_, tokens, err := enc.Encode(text, nil, nil)
+	if err != nil {
+		return 0, err
+	}
+	return len(tokens), nil
+}
+
+// EstimateTokens is the fallback used when no tokenizer is available for a
+// model. BPE tokenizers average about four bytes of UTF-8 per token for
+// English; Cyrillic letters take two bytes each and tokenize at roughly two
+// letters per token, so counting bytes rather than runes keeps the estimate
+// close for both. It tends to overestimate, which is the safe side for
+// context-window budgeting.
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// serverTokenizer counts tokens through the /tokenize endpoint exposed by
+// llama.cpp and vLLM next to their OpenAI-compatible API. It remembers when
+// the server does not support the endpoint so it is probed only once.
+type serverTokenizer struct {
+	url        string
+	model      string
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	unsupported bool
+}
+
+func newServerTokenizer(baseURL, model string) *serverTokenizer {
+	if baseURL == "" {
+		return nil
+	}
+	root := strings.TrimSuffix(strings.TrimRight(baseURL, "/"), "/v1")
+	return &serverTokenizer{
+		url:        root + "/tokenize",
+		model:      model,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type tokenizeRequest struct {
+	Model   string `json:"model,omitempty"`
+	Content string `json:"content"`
+	Prompt  string `json:"prompt"`
+}
+
+type tokenizeResponse struct {
+	Tokens []json.RawMessage `json:"tokens"`
+	Count  *int              `json:"count"`
+}
+
+// count returns the server-reported token count. ok is false when the server
+// does not expose a tokenizer.
+func (t *serverTokenizer) count(ctx context.Context, text string) (n int, ok bool, err error) {
+	if t == nil {
+		return 0, false, nil
+	}
+	t.mu.Lock()
+	unsupported := t.unsupported
+	t.mu.Unlock()
+	if unsupported {
+		return 0, false, nil
+	}
+
+	// llama.cpp reads "content", vLLM reads "prompt".
+	body, err := json.Marshal(tokenizeRequest{Model: t.model, Content: text, Prompt: text})
+	if err != nil {
+		return 0, false, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return 0, false, fmt.Errorf("tokenize: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed {
+		t.mu.Lock()
+		t.unsupported = true
+		t.mu.Unlock()
+		return 0, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, false, fmt.Errorf("tokenize: status %d", resp.StatusCode)
+	}
+
+	var out tokenizeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return 0, false, fmt.Errorf("tokenize: decode: %w", err)
+	}
+	if out.Count != nil {
+		return *out.Count, true, nil
+	}
+	return len(out.Tokens), true, nil
+}
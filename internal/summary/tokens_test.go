@@ -0,0 +1,90 @@
+package summary
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	tiktoken "github.com/hupe1980/go-tiktoken"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIEncodingName(t *testing.T) {
+	cases := []struct {
+		model string
+		want  string
+		ok    bool
+	}{
+		{"gpt-4o", tiktoken.O200kBase, true},
+		{"gpt-4o-mini", tiktoken.O200kBase, true},
+		{"gpt-4.1-nano", tiktoken.O200kBase, true},
+		{"gpt-3.5-turbo", tiktoken.CL100kBase, true},
+		{"llama3.1:8b-instruct-q4_K_M", "", false},
+	}
+
+	for _, c := range cases {
+		got, ok := openAIEncodingName(c.model)
+		assert.Equal(t, c.ok, ok, c.model)
+		assert.Equal(t, c.want, got, c.model)
+	}
+}
+
+func TestOpenAISummarizer_CountTokens(t *testing.T) {
+	t.Run("should use tiktoken for OpenAI models", func(t *testing.T) {
+		s := NewOpenAISummarizer("", "key", "gpt-4o", time.Minute)
+
+		n, err := s.CountTokens("hello world")
+		require.NoError(t, err)
+		assert.Equal(t, 2, n)
+	})
+
+	t.Run("should ask the server tokenizer for other models", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/tokenize", r.URL.Path)
+			_, _ = w.Write([]byte(`{"tokens":[1,2,3,4,5]}`))
+		}))
+		defer srv.Close()
+
+		s := NewOpenAISummarizer(srv.URL+"/v1", "", "qwen2.5", time.Minute)
+
+		n, err := s.CountTokens("hello world")
+		require.NoError(t, err)
+		assert.Equal(t, 5, n)
+	})
+
+	t.Run("should estimate when the server has no tokenizer", func(t *testing.T) {
+		var calls int
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			http.NotFound(w, r)
+		}))
+		defer srv.Close()
+
+		s := NewOpenAISummarizer(srv.URL+"/v1", "", "qwen2.5", time.Minute)
+
+		for range 2 {
+			n, err := s.CountTokens("hello world")
+			require.NoError(t, err)
+			assert.Equal(t, EstimateTokens("hello world"), n)
+		}
+		assert.Equal(t, 1, calls, "unsupported endpoint should be probed once")
+	})
+}
+
+func TestServerTokenizer_Nil(t *testing.T) {
+	var tk *serverTokenizer
+
+	_, ok, err := tk.count(context.Background(), "text")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestEstimateTokens(t *testing.T) {
+	assert.Equal(t, 0, EstimateTokens(""))
+	assert.Equal(t, 3, EstimateTokens("hello world"))
+	// Cyrillic runes take two bytes each.
+	assert.Equal(t, 3, EstimateTokens("привет"))
+}